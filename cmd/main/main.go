@@ -1,16 +1,29 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 	"to-do-list/internal/api/routes"
+	"to-do-list/internal/audit"
 	"to-do-list/internal/auth"
 	"to-do-list/internal/config"
+	"to-do-list/internal/events"
+	"to-do-list/internal/notify"
+	"to-do-list/internal/repository"
+	"to-do-list/internal/scheduler"
+	"to-do-list/internal/storage"
+	"to-do-list/internal/stream"
+	"to-do-list/internal/webhook"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -42,15 +55,186 @@ func main() {
 
 	tokenManager := auth.NewTokenManager(config.JWT.SecretKey, config.JWT.TTL)
 
+	store, err := setupBlobStore(&config.Storage)
+	if err != nil {
+		slog.Error("failed to set up attachment storage", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	// Контекст отменяется по SIGINT/SIGTERM - по нему останавливаются сервер и фоновые задачи
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	hub := stream.NewHub(config.Stream.BufferSize)
+	rooms := stream.NewRooms(stream.RoomsConfig{
+		MaxConnsPerUser: config.WS.MaxConnsPerUser,
+		MaxTopics:       config.WS.MaxTopics,
+		Buffer:          config.WS.BufferSize,
+	})
+	publishers := stream.Publishers{hub, rooms}
+
+	switch config.Stream.Backend {
+	case "local":
+	case "postgres":
+		listener, err := listenOutbox(&config.DB)
+		if err != nil {
+			slog.Error("failed to listen for outbox events", slog.Any("error", err))
+			os.Exit(1)
+		}
+		defer listener.Close()
+
+		go stream.Listen(ctx, listener, repository.NewPostgresOutboxRepository(db), publishers)
+	default:
+		slog.Error("unknown stream backend", slog.String("backend", config.Stream.Backend))
+		os.Exit(1)
+	}
+
+	guard, err := webhook.NewGuard(config.Webhooks.AllowedNetworks)
+	if err != nil {
+		slog.Error("invalid webhook allowed networks", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	jobs := setupScheduler(db, config, publishers, guard)
+	jobs.Start(ctx)
+
 	slog.Info("Starting a server", slog.String("address", config.Server.Port))
 
-	router := routes.SetupRoutes(db, tokenManager)
+	router := routes.SetupRoutes(db, tokenManager, config, store, hub, rooms, guard)
 
 	finalHandler := applyGlobalMiddleware(router)
 
-	err = http.ListenAndServe(config.Server.Port, finalHandler)
+	server := &http.Server{
+		Addr:         config.Server.Port,
+		Handler:      finalHandler,
+		ReadTimeout:  config.Server.Timeout,
+		WriteTimeout: config.Server.Timeout,
+		IdleTimeout:  config.Server.IdleTimeout,
+	}
+	// Shutdown ждёт завершения запросов, а SSE-потоки сами не завершаются - их закрывает хаб.
+	// Соединения WebSocket Shutdown не отслеживает вовсе, их закрывает Rooms
+	server.RegisterOnShutdown(hub.Close)
+	server.RegisterOnShutdown(rooms.Close)
+
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("server failed to start", slog.Any("error", err))
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	slog.Info("Shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.Server.ShutdownTimeout)
+	defer cancel()
+
+	err = server.Shutdown(shutdownCtx)
 	if err != nil {
-		slog.Error("server failed to start", slog.Any("error", err))
+		slog.Error("server shutdown failed", slog.Any("error", err))
+	}
+
+	jobs.Wait()
+	slog.Info("Server stopped")
+}
+
+func setupScheduler(db *sql.DB, cfg *config.Config, live stream.Publisher, guard *webhook.Guard) *scheduler.Scheduler {
+	jobs := scheduler.New(slog.Default())
+
+	taskRepo := repository.NewPostgresTaskRepository(db)
+
+	if cfg.Overdue.Enabled {
+		seriesRepo := repository.NewPostgresSeriesRepository(db)
+		jobs.Add(scheduler.NewOverdueJob(taskRepo, seriesRepo, cfg.Overdue.Interval, cfg.Overdue.GracePeriod))
+	}
+
+	if cfg.Trash.PurgeEnabled {
+		jobs.Add(scheduler.NewTrashPurgeJob(taskRepo, cfg.Trash.Interval, cfg.Trash.Retention))
+	}
+
+	if cfg.Archive.AutoEnabled {
+		jobs.Add(scheduler.NewAutoArchiveJob(taskRepo, cfg.Archive.Interval, cfg.Archive.After))
+	}
+
+	if cfg.Webhooks.DeliveryEnabled {
+		deliverer := webhook.NewDeliverer(repository.NewPostgresWebhookRepository(db), guard.Client(cfg.Webhooks.Timeout), webhook.DelivererConfig{
+			BatchSize:   cfg.Webhooks.BatchSize,
+			Lease:       time.Duration(cfg.Webhooks.BatchSize)*cfg.Webhooks.Timeout + time.Minute,
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			BaseBackoff: cfg.Webhooks.BaseBackoff,
+			MaxBackoff:  cfg.Webhooks.MaxBackoff,
+		})
+		jobs.Add(scheduler.NewWebhookDeliveryJob(deliverer, cfg.Webhooks.Interval))
+	}
+
+	notifier := setupNotifier(db, cfg)
+
+	if cfg.Reminders.Enabled {
+		jobs.Add(scheduler.NewReminderJob(repository.NewPostgresReminderRepository(db), notifier, cfg.Reminders.Interval, cfg.Reminders.BatchSize))
+	}
+
+	if cfg.Events.DispatchEnabled {
+		subscribers := []events.Subscriber{
+			audit.NewSubscriber(repository.NewPostgresAuditRepository(db)),
+			webhook.NewSubscriber(repository.NewPostgresWebhookRepository(db)),
+		}
+		for _, sub := range notify.NewSubscribers(notifier) {
+			subscribers = append(subscribers, sub)
+		}
+		if cfg.Stream.Backend == "local" {
+			subscribers = append(subscribers, stream.NewSubscriber(live))
+		}
+
+		outboxRepo := repository.NewPostgresOutboxRepository(db)
+		dispatcher := events.NewDispatcher(outboxRepo, events.DispatcherConfig{
+			BatchSize:   cfg.Events.BatchSize,
+			Lease:       cfg.Events.Lease,
+			MaxAttempts: cfg.Events.MaxAttempts,
+			BaseBackoff: cfg.Events.BaseBackoff,
+			MaxBackoff:  cfg.Events.MaxBackoff,
+		}, subscribers...)
+		jobs.Add(scheduler.NewEventDispatchJob(dispatcher, cfg.Events.Interval))
+		jobs.Add(scheduler.NewOutboxCleanupJob(outboxRepo, cfg.Events.CleanupInterval, cfg.Events.Retention))
+	}
+
+	return jobs
+}
+
+// setupNotifier подключает каналы уведомлений: в приложении и через вебхуки всегда, почту - если задан SMTP_HOST
+func setupNotifier(db *sql.DB, cfg *config.Config) *notify.Service {
+	channels := []notify.Channel{
+		notify.NewInAppChannel(repository.NewPostgresNotificationRepository(db)),
+		webhook.NewNotificationChannel(repository.NewPostgresWebhookRepository(db)),
+	}
+
+	if cfg.SMTP.Host != "" {
+		channels = append(channels, notify.NewEmailChannel(notify.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			From:     cfg.SMTP.From,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+		}, repository.NewPostgresUserRepository(db)))
+	}
+
+	return notify.NewService(channels...)
+}
+
+func setupBlobStore(cfg *config.StorageCfg) (storage.BlobStore, error) {
+	switch cfg.Driver {
+	case "local":
+		return storage.NewLocalStore(cfg.LocalDir)
+	case "s3":
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Bucket:    cfg.S3Bucket,
+			Region:    cfg.S3Region,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		}, &http.Client{Timeout: time.Minute})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
 	}
 }
 
@@ -60,12 +244,14 @@ func setupLogger() {
 	slog.SetDefault(logger)
 }
 
-func connectToDb(cfg *config.DBCfg) (*sql.DB, error) {
-	connString := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+func dbConnString(cfg *config.DBCfg) string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
 	)
+}
 
-	db, err := sql.Open("postgres", connString)
+func connectToDb(cfg *config.DBCfg) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dbConnString(cfg))
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +262,23 @@ func connectToDb(cfg *config.DBCfg) (*sql.DB, error) {
 	return db, nil
 }
 
+// listenOutbox открывает отдельное соединение, по которому Postgres сообщает о новых событиях outbox.
+// При разрыве pq.Listener переподключается сам
+func listenOutbox(cfg *config.DBCfg) (*pq.Listener, error) {
+	listener := pq.NewListener(dbConnString(cfg), 10*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Error("outbox listener connection problem", slog.Any("error", err))
+		}
+	})
+
+	err := listener.Listen(stream.NotifyChannel)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
 func applyGlobalMiddleware(h http.Handler) http.Handler {
 	r := chi.NewRouter()
 
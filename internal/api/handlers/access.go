@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+var errTaskAccessDenied = errors.New("not enough permissions for the task")
+
+// taskAccess определяет доступ пользователя к задаче по правилам models.ResolveTaskAccess
+func taskAccess(ctx context.Context, repo repository.TaskRepository, task models.Task, userID uint) (models.TaskAccess, error) {
+	if task.UserID == userID {
+		return models.AccessOwner, nil
+	}
+
+	var role *models.WorkspaceRole
+	if task.WorkspaceID != nil {
+		r, err := repo.GetWorkspaceRole(ctx, *task.WorkspaceID, userID)
+		if err == nil {
+			role = &r
+		} else if !errors.Is(err, repository.ErrNotWorkspaceMember) {
+			return models.AccessNone, err
+		}
+	}
+
+	var share *models.SharePermission
+	if role == nil {
+		permission, err := repo.GetTaskSharePermission(ctx, task.ID, userID)
+		if err == nil {
+			share = &permission
+		} else if !errors.Is(err, repository.ErrShareNotFound) {
+			return models.AccessNone, err
+		}
+	}
+
+	return models.ResolveTaskAccess(task, userID, role, share), nil
+}
+
+// authorizeTask загружает задачу и проверяет доступ не ниже need. Задача, к которой доступа нет совсем,
+// выглядит несуществующей (ErrTaskNotFound), а при недостаточном доступе возвращается errTaskAccessDenied
+func authorizeTask(ctx context.Context, repo repository.TaskRepository, userID, taskID uint, need models.TaskAccess) (models.Task, error) {
+	task, err := repo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	access, err := taskAccess(ctx, repo, task, userID)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if access == models.AccessNone {
+		return models.Task{}, repository.ErrTaskNotFound
+	}
+	if access < need {
+		return models.Task{}, errTaskAccessDenied
+	}
+
+	return task, nil
+}
+
+// loadAuthorizedTask достаёт задачу из {taskID} и отвечает клиенту сам, если доступа не хватает
+func loadAuthorizedTask(w http.ResponseWriter, r *http.Request, repo repository.TaskRepository, need models.TaskAccess) (models.Task, bool) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.Task{}, false
+	}
+
+	taskID, err := strconv.ParseUint(chi.URLParam(r, "taskID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid task ID"})
+		return models.Task{}, false
+	}
+
+	task, err := authorizeTask(r.Context(), repo, userID, uint(taskID), need)
+	if errors.Is(err, repository.ErrTaskNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Task not found"})
+		return models.Task{}, false
+	}
+	if errors.Is(err, errTaskAccessDenied) {
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, map[string]string{"error": "Not enough permissions for this task"})
+		return models.Task{}, false
+	}
+	if err != nil {
+		slog.Error("Failed to authorize task access", slog.Any("taskID", taskID), slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.Task{}, false
+	}
+
+	return task, true
+}
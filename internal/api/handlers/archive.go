@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+
+	"github.com/go-chi/render"
+)
+
+// ArchiveTask убирает завершённую задачу из списка по умолчанию
+func (h *TaskHandler) ArchiveTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.loadTask(w, r, models.AccessOwner)
+	if !ok {
+		return
+	}
+
+	if task.Status != models.StatusCompleted {
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "Only completed tasks can be archived"})
+		return
+	}
+
+	h.setArchived(w, r, task, true)
+}
+
+func (h *TaskHandler) UnarchiveTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.loadTask(w, r, models.AccessOwner)
+	if !ok {
+		return
+	}
+
+	h.setArchived(w, r, task, false)
+}
+
+func (h *TaskHandler) setArchived(w http.ResponseWriter, r *http.Request, task models.Task, archived bool) {
+	if (task.ArchivedAt != nil) == archived {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	updated := task
+	updated.ArchivedAt = nil
+	if archived {
+		now := time.Now()
+		updated.ArchivedAt = &now
+	}
+
+	err := h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		err := h.repo.SetTaskArchived(ctx, task.ID, archived)
+		if err != nil {
+			return err
+		}
+		return h.recordTaskUpdate(ctx, r, task.UserID, task, updated)
+	})
+	if err != nil {
+		slog.Error("Failed to update task archive state", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to update task"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) ArchiveCompletedTasks(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	var archived []models.Task
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		var err error
+		archived, err = h.repo.ArchiveCompletedTasks(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		var events []models.DomainEvent
+		for _, after := range archived {
+			before := after
+			before.ArchivedAt = nil
+			events = append(events, models.TaskUpdateEvents(before, after)...)
+		}
+		return h.events.AppendEvents(ctx, stampEvents(r, userID, events...)...)
+	})
+	if err != nil {
+		slog.Error("Failed to archive completed tasks", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to archive tasks"})
+
+		return
+	}
+
+	render.JSON(w, r, types.ArchiveCompletedResponse{Archived: int64(len(archived))})
+}
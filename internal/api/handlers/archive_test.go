@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskHandler_ArchiveTask(t *testing.T) {
+	archivedAt := time.Now()
+
+	testCases := []struct {
+		name         string
+		task         models.Task
+		expectUpdate bool
+		expectedCode int
+	}{
+		{"Completed Task", models.Task{ID: 1, UserID: 10, Status: models.StatusCompleted}, true, http.StatusNoContent},
+		{"Already Archived", models.Task{ID: 1, UserID: 10, Status: models.StatusCompleted, ArchivedAt: &archivedAt}, false, http.StatusNoContent},
+		{"Not Completed", models.Task{ID: 1, UserID: 10, Status: models.StatusPending}, false, http.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+			mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(tc.task, nil).Once()
+			if tc.expectUpdate {
+				mockRepo.On("SetTaskArchived", mock.Anything, uint(1), true).Return(nil).Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ArchiveTask(rr, withTaskID(httptest.NewRequest("POST", "/tasks/1/archive", nil), "1", 10))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			mockRepo.AssertExpectations(t)
+			if !tc.expectUpdate {
+				mockRepo.AssertNotCalled(t, "SetTaskArchived")
+			}
+		})
+	}
+}
+
+func TestTaskHandler_UnarchiveTask(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	archivedAt := time.Now()
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10, Status: models.StatusCompleted, ArchivedAt: &archivedAt}, nil).Once()
+	mockRepo.On("SetTaskArchived", mock.Anything, uint(1), false).Return(nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.UnarchiveTask(rr, withTaskID(httptest.NewRequest("POST", "/tasks/1/unarchive", nil), "1", 10))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskHandler_ArchiveCompletedTasks(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	eventStore := &fakeEventStore{}
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, eventStore, &fakeAuditRecorder{}, TaskPolicy{})
+
+	archivedAt := time.Now()
+	mockRepo.On("ArchiveCompletedTasks", mock.Anything, uint(10)).Return([]models.Task{
+		{ID: 1, UserID: 10, Status: models.StatusCompleted, ArchivedAt: &archivedAt},
+		{ID: 2, UserID: 10, Status: models.StatusCompleted, ArchivedAt: &archivedAt},
+		{ID: 3, UserID: 10, Status: models.StatusCompleted, ArchivedAt: &archivedAt},
+	}, nil).Once()
+
+	req := httptest.NewRequest("POST", "/tasks/archive-completed", nil)
+	rr := httptest.NewRecorder()
+	handler.ArchiveCompletedTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp types.ArchiveCompletedResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, int64(3), resp.Archived)
+
+	require.Equal(t, []models.EventType{models.EventTaskUpdated, models.EventTaskUpdated, models.EventTaskUpdated}, eventStore.eventTypes())
+	payload, err := eventStore.events[0].TaskPayload()
+	require.NoError(t, err)
+	assert.Nil(t, payload.Before.ArchivedAt)
+	assert.NotNil(t, payload.After.ArchivedAt)
+}
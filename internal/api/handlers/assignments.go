@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+
+	"github.com/go-chi/render"
+)
+
+// AssignTask назначает исполнителя. Им может стать только тот, кто сам может редактировать задачу:
+// владелец, участник её пространства или пользователь с правом editor. Уведомление исполнителю
+// отправляет подписчик события task.assigned
+func (h *TaskHandler) AssignTask(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	task, ok := h.loadTask(w, r, models.AccessEdit)
+	if !ok {
+		return
+	}
+
+	var req types.AssignTaskRequest
+	err = render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+
+	if task.AssigneeID != nil && *task.AssigneeID == req.UserID {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	access, err := taskAccess(r.Context(), h.repo, task, req.UserID)
+	if err != nil {
+		slog.Error("Failed to check assignee access", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+	if access < models.AccessEdit {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Assignee can't edit this task"})
+		return
+	}
+
+	updated := task
+	updated.AssigneeID = &req.UserID
+
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		err := h.repo.UpdateTaskAssignee(ctx, task.ID, &req.UserID)
+		if err != nil {
+			return err
+		}
+		return h.recordTaskUpdate(ctx, r, userID, task, updated)
+	})
+	if err != nil {
+		slog.Error("Failed to assign task", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to assign task"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) UnassignTask(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	task, ok := h.loadTask(w, r, models.AccessEdit)
+	if !ok {
+		return
+	}
+
+	if task.AssigneeID == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	updated := task
+	updated.AssigneeID = nil
+
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		err := h.repo.UpdateTaskAssignee(ctx, task.ID, nil)
+		if err != nil {
+			return err
+		}
+		return h.recordTaskUpdate(ctx, r, userID, task, updated)
+	})
+	if err != nil {
+		slog.Error("Failed to unassign task", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to unassign task"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
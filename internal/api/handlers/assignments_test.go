@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTaskHandler_AssignTask(t *testing.T) {
+	workspaceID := uint(7)
+
+	testCases := []struct {
+		name         string
+		task         models.Task
+		requester    uint
+		assignee     uint
+		expectedCode int
+	}{
+		{"Owner Assigns Workspace Member", models.Task{ID: 1, UserID: 10, WorkspaceID: &workspaceID}, 10, 20, http.StatusNoContent},
+		{"Owner Assigns Editor", models.Task{ID: 1, UserID: 10}, 10, 30, http.StatusNoContent},
+		{"Owner Assigns Self", models.Task{ID: 1, UserID: 10}, 10, 10, http.StatusNoContent},
+		{"Viewer Can't Be Assignee", models.Task{ID: 1, UserID: 10}, 10, 40, http.StatusBadRequest},
+		{"Stranger Can't Be Assignee", models.Task{ID: 1, UserID: 10}, 10, 99, http.StatusBadRequest},
+		{"Viewer Can't Assign", models.Task{ID: 1, UserID: 10}, 40, 10, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			eventStore := &fakeEventStore{}
+			handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, eventStore, &fakeAuditRecorder{}, TaskPolicy{})
+
+			mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(tc.task, nil).Once()
+			mockRepo.On("GetWorkspaceRole", mock.Anything, workspaceID, uint(20)).Return(models.WorkspaceRoleMember, nil).Maybe()
+			mockRepo.On("GetTaskSharePermission", mock.Anything, uint(1), uint(30)).Return(models.ShareEditor, nil).Maybe()
+			mockRepo.On("GetTaskSharePermission", mock.Anything, uint(1), uint(40)).Return(models.ShareViewer, nil).Maybe()
+			mockRepo.On("GetTaskSharePermission", mock.Anything, uint(1), mock.Anything).Return(models.SharePermission(""), repository.ErrShareNotFound).Maybe()
+			mockRepo.On("UpdateTaskAssignee", mock.Anything, uint(1), &tc.assignee).Return(nil).Maybe()
+
+			body, _ := json.Marshal(types.AssignTaskRequest{UserID: tc.assignee})
+			rr := httptest.NewRecorder()
+			handler.AssignTask(rr, withTaskID(httptest.NewRequest("PUT", "/tasks/1/assignee", bytes.NewReader(body)), "1", tc.requester))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusNoContent {
+				mockRepo.AssertCalled(t, "UpdateTaskAssignee", mock.Anything, uint(1), &tc.assignee)
+				// Уведомление исполнителю отправляет подписчик task.assigned
+				assert.Equal(t, []models.EventType{models.EventTaskUpdated, models.EventTaskAssigned}, eventStore.eventTypes())
+			} else {
+				mockRepo.AssertNotCalled(t, "UpdateTaskAssignee", mock.Anything, mock.Anything, mock.Anything)
+				assert.Empty(t, eventStore.events)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_UnassignTask(t *testing.T) {
+	assignee := uint(20)
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10, AssigneeID: &assignee}, nil).Once()
+	mockRepo.On("UpdateTaskAssignee", mock.Anything, uint(1), (*uint)(nil)).Return(nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.UnassignTask(rr, withTaskID(httptest.NewRequest("DELETE", "/tasks/1/assignee", nil), "1", 10))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskHandler_GetUserTasks_AssignedToMe(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	mockRepo.On("GetTasksByUserID", mock.Anything, uint(10), mock.MatchedBy(func(f repository.TaskFilter) bool {
+		return f.AssignedToMe
+	})).Return([]models.Task{}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/tasks?assignee=me", nil)
+	rr := httptest.NewRecorder()
+	handler.GetUserTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockRepo.AssertExpectations(t)
+
+	req = httptest.NewRequest("GET", "/tasks?assignee=20", nil)
+	rr = httptest.NewRecorder()
+	handler.GetUserTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+	"to-do-list/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// multipartOverhead - запас на заголовки и границы multipart поверх размера самого файла
+const multipartOverhead = 1 << 20
+
+// AttachmentPolicy - ограничения на загружаемые файлы. Тип определяется по содержимому, а не по заголовку клиента
+type AttachmentPolicy struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+type AttachmentHandler struct {
+	repo   repository.AttachmentRepository
+	tasks  repository.TaskRepository
+	store  storage.BlobStore
+	policy AttachmentPolicy
+}
+
+func NewAttachmentHandler(repo repository.AttachmentRepository, tasks repository.TaskRepository, store storage.BlobStore, policy AttachmentPolicy) *AttachmentHandler {
+	return &AttachmentHandler{repo: repo, tasks: tasks, store: store, policy: policy}
+}
+
+func (h *AttachmentHandler) GetAttachments(w http.ResponseWriter, r *http.Request) {
+	task, ok := loadAuthorizedTask(w, r, h.tasks, models.AccessView)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.repo.GetAttachmentsByTaskID(r.Context(), task.ID)
+	if err != nil {
+		slog.Error("Failed to get task attachments", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get attachments"})
+
+		return
+	}
+
+	resp := make([]types.AttachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		resp = append(resp, toAttachmentResponse(attachment))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// UploadAttachment принимает multipart/form-data с файлом в поле "file"
+func (h *AttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	task, ok := loadAuthorizedTask(w, r, h.tasks, models.AccessEdit)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.policy.MaxSize+multipartOverhead)
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Expected multipart/form-data body"})
+		return
+	}
+
+	var fileName string
+	var data []byte
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			h.renderUploadError(w, r, err)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		fileName = part.FileName()
+		data, err = io.ReadAll(io.LimitReader(part, h.policy.MaxSize+1))
+		part.Close()
+		if err != nil {
+			h.renderUploadError(w, r, err)
+			return
+		}
+		break
+	}
+
+	if data == nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Missing file field"})
+		return
+	}
+	if int64(len(data)) > h.policy.MaxSize {
+		h.renderTooLarge(w, r)
+		return
+	}
+
+	contentType := http.DetectContentType(data)
+	if !models.ContentTypeAllowed(contentType, h.policy.AllowedTypes) {
+		render.Status(r, http.StatusUnsupportedMediaType)
+		render.JSON(w, r, map[string]string{"error": "File type " + contentType + " is not allowed"})
+		return
+	}
+
+	key, err := storage.NewKey(fmt.Sprintf("tasks/%d", task.ID))
+	if err != nil {
+		slog.Error("Failed to generate storage key", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	err = h.store.Put(r.Context(), key, bytes.NewReader(data), int64(len(data)), contentType)
+	if err != nil {
+		slog.Error("Failed to store attachment", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to store attachment"})
+
+		return
+	}
+
+	attachment := &models.TaskAttachment{
+		TaskID:      task.ID,
+		UserID:      userID,
+		FileName:    models.SanitizeFileName(fileName),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  key,
+	}
+
+	err = h.repo.CreateAttachment(r.Context(), attachment)
+	if err != nil {
+		slog.Error("Failed to create attachment", slog.Any("error", err))
+
+		// Без метаданных файл недостижим, поэтому сразу убираем его из хранилища
+		if delErr := h.store.Delete(r.Context(), key); delErr != nil {
+			slog.Error("Failed to delete orphaned blob", slog.String("key", key), slog.Any("error", delErr))
+		}
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to create attachment"})
+
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, toAttachmentResponse(*attachment))
+}
+
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	attachment, ok := h.loadAttachment(w, r, models.AccessView)
+	if !ok {
+		return
+	}
+
+	content, err := h.store.Get(r.Context(), attachment.StorageKey)
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		slog.Error("Attachment blob is missing", slog.String("key", attachment.StorageKey))
+
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Attachment not found"})
+
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to read attachment", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to read attachment"})
+
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	_, err = io.Copy(w, content)
+	if err != nil {
+		slog.Error("Failed to send attachment", slog.Any("error", err))
+	}
+}
+
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	attachment, ok := h.loadAttachment(w, r, models.AccessEdit)
+	if !ok {
+		return
+	}
+
+	err := h.repo.DeleteAttachment(r.Context(), attachment.ID)
+	if err != nil && !errors.Is(err, repository.ErrAttachmentNotFound) {
+		slog.Error("Failed to delete attachment", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to delete attachment"})
+
+		return
+	}
+
+	// Метаданные уже удалены, так что ошибка хранилища оставит только недостижимый файл
+	err = h.store.Delete(r.Context(), attachment.StorageKey)
+	if err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+		slog.Error("Failed to delete attachment blob", slog.String("key", attachment.StorageKey), slog.Any("error", err))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AttachmentHandler) loadAttachment(w http.ResponseWriter, r *http.Request, need models.TaskAccess) (models.TaskAttachment, bool) {
+	task, ok := loadAuthorizedTask(w, r, h.tasks, need)
+	if !ok {
+		return models.TaskAttachment{}, false
+	}
+
+	attachmentID, err := strconv.ParseUint(chi.URLParam(r, "attachmentID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid attachment ID"})
+		return models.TaskAttachment{}, false
+	}
+
+	attachment, err := h.repo.GetAttachmentByID(r.Context(), uint(attachmentID))
+	if errors.Is(err, repository.ErrAttachmentNotFound) || (err == nil && attachment.TaskID != task.ID) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Attachment not found"})
+		return models.TaskAttachment{}, false
+	}
+	if err != nil {
+		slog.Error("Failed to get attachment by ID", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.TaskAttachment{}, false
+	}
+
+	return attachment, true
+}
+
+func (h *AttachmentHandler) renderUploadError(w http.ResponseWriter, r *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		h.renderTooLarge(w, r)
+		return
+	}
+
+	slog.Error("Failed to read multipart body", slog.Any("error", err))
+
+	render.Status(r, http.StatusBadRequest)
+	render.JSON(w, r, map[string]string{"error": "Invalid multipart body"})
+}
+
+func (h *AttachmentHandler) renderTooLarge(w http.ResponseWriter, r *http.Request) {
+	render.Status(r, http.StatusRequestEntityTooLarge)
+	render.JSON(w, r, map[string]string{"error": fmt.Sprintf("File exceeds %d bytes", h.policy.MaxSize)})
+}
+
+func toAttachmentResponse(attachment models.TaskAttachment) types.AttachmentResponse {
+	return types.AttachmentResponse{
+		ID:          attachment.ID,
+		TaskID:      attachment.TaskID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) CreateAttachment(ctx context.Context, attachment *models.TaskAttachment) error {
+	args := m.Called(ctx, attachment)
+	attachment.ID = 1
+	attachment.CreatedAt = time.Now()
+	return args.Error(0)
+}
+func (m *MockAttachmentRepository) GetAttachmentByID(ctx context.Context, id uint) (models.TaskAttachment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.TaskAttachment), args.Error(1)
+}
+func (m *MockAttachmentRepository) GetAttachmentsByTaskID(ctx context.Context, taskID uint) ([]models.TaskAttachment, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]models.TaskAttachment), args.Error(1)
+}
+func (m *MockAttachmentRepository) DeleteAttachment(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func withAttachmentID(req *http.Request, taskID, attachmentID string, userID uint) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("taskID", taskID)
+	rctx.URLParams.Add("attachmentID", attachmentID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(withUserID(ctx, userID))
+}
+
+func newUploadRequest(t *testing.T, fileName string, content []byte) *http.Request {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest("POST", "/tasks/1/attachments", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return withTaskID(req, "1", 10)
+}
+
+func newAttachmentHandler(t *testing.T) (*AttachmentHandler, *MockAttachmentRepository, *MockTaskRepository, *storage.LocalStore) {
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	mockRepo := new(MockAttachmentRepository)
+	mockTasks := new(MockTaskRepository)
+	handler := NewAttachmentHandler(mockRepo, mockTasks, store, AttachmentPolicy{
+		MaxSize:      64,
+		AllowedTypes: []string{"image/*", "application/pdf"},
+	})
+	return handler, mockRepo, mockTasks, store
+}
+
+func TestAttachmentHandler_UploadAttachment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		handler, mockRepo, mockTasks, store := newAttachmentHandler(t)
+
+		var stored *models.TaskAttachment
+		mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+		mockRepo.On("CreateAttachment", mock.Anything, mock.AnythingOfType("*models.TaskAttachment")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*models.TaskAttachment) }).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UploadAttachment(rr, newUploadRequest(t, "../screen.png", pngHeader))
+
+		require.Equal(t, http.StatusCreated, rr.Code)
+		var resp types.AttachmentResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, "screen.png", resp.FileName)
+		assert.Equal(t, "image/png", resp.ContentType)
+		assert.Equal(t, int64(len(pngHeader)), resp.Size)
+
+		require.NotNil(t, stored)
+		assert.True(t, strings.HasPrefix(stored.StorageKey, "tasks/1/"))
+		rc, err := store.Get(context.Background(), stored.StorageKey)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		assert.Equal(t, pngHeader, data)
+	})
+
+	testCases := []struct {
+		name         string
+		content      []byte
+		expectedCode int
+	}{
+		{"Disallowed Type", []byte("#!/bin/sh\necho hi\n"), http.StatusUnsupportedMediaType},
+		{"Too Large", append(append([]byte{}, pngHeader...), make([]byte, 64)...), http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockRepo, mockTasks, _ := newAttachmentHandler(t)
+			mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+
+			rr := httptest.NewRecorder()
+			handler.UploadAttachment(rr, newUploadRequest(t, "file", tc.content))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			mockRepo.AssertNotCalled(t, "CreateAttachment")
+		})
+	}
+
+	t.Run("Not Multipart", func(t *testing.T) {
+		handler, _, mockTasks, _ := newAttachmentHandler(t)
+		mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UploadAttachment(rr, withTaskID(httptest.NewRequest("POST", "/tasks/1/attachments", strings.NewReader("{}")), "1", 10))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestAttachmentHandler_DownloadAttachment(t *testing.T) {
+	handler, mockRepo, mockTasks, store := newAttachmentHandler(t)
+	require.NoError(t, store.Put(context.Background(), "tasks/1/abc", bytes.NewReader(pngHeader), int64(len(pngHeader)), "image/png"))
+
+	attachment := models.TaskAttachment{ID: 5, TaskID: 1, UserID: 10, FileName: "скрин.png", ContentType: "image/png", Size: int64(len(pngHeader)), StorageKey: "tasks/1/abc"}
+	mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+	mockRepo.On("GetAttachmentByID", mock.Anything, uint(5)).Return(attachment, nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.DownloadAttachment(rr, withAttachmentID(httptest.NewRequest("GET", "/tasks/1/attachments/5", nil), "1", "5", 10))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, pngHeader, rr.Body.Bytes())
+	assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "attachment;")
+}
+
+func TestAttachmentHandler_DeleteAttachment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		handler, mockRepo, mockTasks, store := newAttachmentHandler(t)
+		require.NoError(t, store.Put(context.Background(), "tasks/1/abc", bytes.NewReader(pngHeader), int64(len(pngHeader)), "image/png"))
+
+		mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+		mockRepo.On("GetAttachmentByID", mock.Anything, uint(5)).Return(models.TaskAttachment{ID: 5, TaskID: 1, StorageKey: "tasks/1/abc"}, nil).Once()
+		mockRepo.On("DeleteAttachment", mock.Anything, uint(5)).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.DeleteAttachment(rr, withAttachmentID(httptest.NewRequest("DELETE", "/tasks/1/attachments/5", nil), "1", "5", 10))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		mockRepo.AssertExpectations(t)
+		_, err := store.Get(context.Background(), "tasks/1/abc")
+		assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+	})
+
+	t.Run("Attachment Of Another Task", func(t *testing.T) {
+		handler, mockRepo, mockTasks, _ := newAttachmentHandler(t)
+
+		mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+		mockRepo.On("GetAttachmentByID", mock.Anything, uint(5)).Return(models.TaskAttachment{ID: 5, TaskID: 2}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.DeleteAttachment(rr, withAttachmentID(httptest.NewRequest("DELETE", "/tasks/1/attachments/5", nil), "1", "5", 10))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockRepo.AssertNotCalled(t, "DeleteAttachment")
+	})
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/render"
+)
+
+type AuditRecorder interface {
+	Record(ctx context.Context, event models.AuditEvent)
+}
+
+type AuditHandler struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditHandler(repo repository.AuditRepository) *AuditHandler {
+	return &AuditHandler{repo: repo}
+}
+
+// GetMyAuditEvents - журнал только по текущему пользователю
+func (h *AuditHandler) GetMyAuditEvents(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
+	}
+	filter.UserID = &userID
+
+	h.renderEvents(w, r, filter)
+}
+
+// GetAuditEvents - журнал по всем пользователям, только для администраторов
+func (h *AuditHandler) GetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userIDStr := r.URL.Query().Get("user_id")
+	if userIDStr != "" {
+		parsedUserID, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, map[string]string{"error": "Invalid user_id"})
+			return
+		}
+		userID := uint(parsedUserID)
+		filter.UserID = &userID
+	}
+
+	h.renderEvents(w, r, filter)
+}
+
+func (h *AuditHandler) renderEvents(w http.ResponseWriter, r *http.Request, filter repository.AuditFilter) {
+	events, err := h.repo.GetAuditEvents(r.Context(), filter)
+	if err != nil {
+		slog.Error("Failed to get audit events", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get audit events"})
+
+		return
+	}
+
+	resp := make([]types.AuditEventResponse, 0, len(events))
+	for _, e := range events {
+		resp = append(resp, types.AuditEventResponse{
+			ID:         e.ID,
+			UserID:     e.UserID,
+			Action:     e.Action,
+			EntityType: e.EntityType,
+			EntityID:   e.EntityID,
+			RequestID:  e.RequestID,
+			IP:         e.IP,
+			Before:     e.Before,
+			After:      e.After,
+			Metadata:   e.Metadata,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func parseAuditFilter(r *http.Request) (repository.AuditFilter, error) {
+	limit, offset := parsePagination(r)
+	filter := repository.AuditFilter{
+		Action: models.AuditAction(r.URL.Query().Get("action")),
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	fromStr := r.URL.Query().Get("from")
+	if fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return repository.AuditFilter{}, errors.New("Invalid from parameter")
+		}
+		filter.From = &from
+	}
+
+	toStr := r.URL.Query().Get("to")
+	if toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return repository.AuditFilter{}, errors.New("Invalid to parameter")
+		}
+		filter.To = &to
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuditRecorder запоминает события, чтобы тесты могли их проверить
+type fakeAuditRecorder struct {
+	events []models.AuditEvent
+}
+
+func (f *fakeAuditRecorder) Record(ctx context.Context, event models.AuditEvent) {
+	f.events = append(f.events, event)
+}
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) GetAuditEvents(ctx context.Context, filter repository.AuditFilter) ([]models.AuditEvent, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.AuditEvent), args.Error(1)
+}
+
+func TestAuditHandler_GetMyAuditEvents(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	handler := NewAuditHandler(mockRepo)
+
+	userID := uint(10)
+	events := []models.AuditEvent{{ID: 1, UserID: &userID, Action: models.AuditLoginSuccess}}
+	mockRepo.On("GetAuditEvents", mock.Anything, mock.MatchedBy(func(f repository.AuditFilter) bool {
+		return f.UserID != nil && *f.UserID == userID && f.Action == models.AuditLoginSuccess && f.Limit == 10
+	})).Return(events, nil).Once()
+
+	req := httptest.NewRequest("GET", "/users/me/audit?action=login.success", nil)
+	ctx := withUserID(req.Context(), userID)
+	rr := httptest.NewRecorder()
+
+	handler.GetMyAuditEvents(rr, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp []types.AuditEventResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, models.AuditLoginSuccess, resp[0].Action)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditHandler_GetAuditEvents_InvalidFilter(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	handler := NewAuditHandler(mockRepo)
+
+	req := httptest.NewRequest("GET", "/admin/audit?from=yesterday", nil)
+	rr := httptest.NewRecorder()
+
+	handler.GetAuditEvents(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockRepo.AssertNotCalled(t, "GetAuditEvents")
+}
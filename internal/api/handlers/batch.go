@@ -0,0 +1,418 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/render"
+)
+
+// batchOpError - отказ в операции пакета с кодом и текстом, которые вернул бы одиночный запрос
+type batchOpError struct {
+	status  int
+	message string
+}
+
+func (e *batchOpError) Error() string {
+	return e.message
+}
+
+func batchFail(status int, message string) error {
+	return &batchOpError{status: status, message: message}
+}
+
+// batchAbort останавливает пакет в режиме atomic и откатывает транзакцию целиком
+type batchAbort struct {
+	index int
+	err   error
+}
+
+func (e *batchAbort) Error() string {
+	return fmt.Sprintf("batch operation %d: %v", e.index, e.err)
+}
+
+func (e *batchAbort) Unwrap() error {
+	return e.err
+}
+
+// batchState - общее для операций пакета: задачи с доступом, загруженные одним запросом
+// (после каждого удаления перечитываются), и уже проверенные проекты
+type batchState struct {
+	userID   uint
+	tasks    map[uint]models.AccessibleTask
+	projects map[uint]error
+}
+
+// BatchTasks выполняет до 100 операций create/update/delete в одной транзакции. Задачи из update и delete
+// загружаются и блокируются одним запросом вместе с доступом к ним. Каждая операция идёт в своей точке
+// сохранения: в режиме per_item неудачная откатывается одна, в режиме atomic - весь пакет.
+// Переносы между родителями и пространствами, подзадачи и повторяющиеся задачи - только одиночными запросами
+func (h *TaskHandler) BatchTasks(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	var req types.BatchTaskRequest
+	err = render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode batch request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Batch validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = types.BatchAtomic
+	}
+
+	ids, err := batchTaskIDs(req.Operations)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+
+		return
+	}
+
+	results := make([]types.BatchTaskResult, len(req.Operations))
+
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		state := batchState{userID: userID, tasks: map[uint]models.AccessibleTask{}, projects: map[uint]error{}}
+		if len(ids) > 0 {
+			tasks, err := h.repo.GetTasksWithAccess(ctx, userID, ids)
+			if err != nil {
+				return err
+			}
+			for _, t := range tasks {
+				state.tasks[t.Task.ID] = t
+			}
+		}
+
+		for i, op := range req.Operations {
+			var result types.BatchTaskResult
+
+			err := h.tx.WithinTx(ctx, func(ctx context.Context) error {
+				var err error
+				result, err = h.runBatchOperation(ctx, r, &state, op)
+				return err
+			})
+			result.Index, result.Op = i, op.Op
+
+			var opErr *batchOpError
+			switch {
+			case err == nil:
+			case req.Mode == types.BatchAtomic:
+				return &batchAbort{index: i, err: err}
+			case errors.As(err, &opErr):
+				result.TaskID, result.Status, result.Error = op.TaskID, opErr.status, opErr.message
+			default:
+				slog.Error("Failed to run batch operation", slog.Int("index", i), slog.Any("error", err))
+				result.TaskID, result.Status, result.Error = op.TaskID, http.StatusInternalServerError, "Internal server error"
+			}
+			results[i] = result
+		}
+		return nil
+	})
+
+	var abort *batchAbort
+	var opErr *batchOpError
+	if errors.As(err, &abort) && errors.As(abort.err, &opErr) {
+		render.Status(r, opErr.status)
+		render.JSON(w, r, types.BatchTaskErrorResponse{Error: opErr.message, Index: abort.index})
+
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to run task batch", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to run task batch"})
+
+		return
+	}
+
+	resp := types.BatchTaskResponse{Mode: req.Mode, Results: results}
+	for _, result := range results {
+		if result.Error == "" {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// batchTaskIDs собирает id задач из update и delete. Одна задача может встречаться в пакете только раз,
+// иначе исход зависел бы от порядка операций
+func batchTaskIDs(ops []types.BatchTaskOperation) ([]uint, error) {
+	ids := []uint{}
+	seen := map[uint]bool{}
+
+	for i, op := range ops {
+		if op.Op == types.BatchCreate {
+			continue
+		}
+		if op.TaskID == 0 {
+			return nil, fmt.Errorf("Operation %d: taskId is required", i)
+		}
+		if seen[op.TaskID] {
+			return nil, fmt.Errorf("Task %d appears in several operations", op.TaskID)
+		}
+		seen[op.TaskID] = true
+		ids = append(ids, op.TaskID)
+	}
+
+	return ids, nil
+}
+
+// runBatchOperation выполняет одну операцию внутри её точки сохранения
+func (h *TaskHandler) runBatchOperation(ctx context.Context, r *http.Request, state *batchState, op types.BatchTaskOperation) (types.BatchTaskResult, error) {
+	switch op.Op {
+	case types.BatchCreate:
+		task, err := h.batchCreate(ctx, r, state, *op.Create)
+		if err != nil {
+			return types.BatchTaskResult{}, err
+		}
+		resp := types.NewTaskResponse(*task)
+		return types.BatchTaskResult{TaskID: task.ID, Status: http.StatusCreated, Task: &resp}, nil
+	case types.BatchUpdate:
+		updated, err := h.batchUpdate(ctx, r, state, op.TaskID, *op.Update)
+		if err != nil {
+			return types.BatchTaskResult{}, err
+		}
+		resp := types.NewTaskResponse(updated)
+		return types.BatchTaskResult{TaskID: op.TaskID, Status: http.StatusOK, Task: &resp}, nil
+	default:
+		err := h.batchDelete(ctx, r, state, op.TaskID)
+		if err != nil {
+			return types.BatchTaskResult{}, err
+		}
+		return types.BatchTaskResult{TaskID: op.TaskID, Status: http.StatusNoContent}, nil
+	}
+}
+
+// batchCreate повторяет проверки CreateTask для задачи верхнего уровня
+func (h *TaskHandler) batchCreate(ctx context.Context, r *http.Request, state *batchState, req types.CreateTaskRequest) (*models.Task, error) {
+	if req.Recurrence != "" {
+		return nil, batchFail(http.StatusBadRequest, "Recurring tasks can't be created in a batch")
+	}
+
+	task, err := models.NewTask(req.Name, req.Description, req.Deadline)
+	if err != nil {
+		return nil, batchFail(http.StatusBadRequest, err.Error())
+	}
+	task.UserID = state.userID
+	if req.Priority != "" {
+		task.Priority = req.Priority
+	}
+	task.Tags = models.NormalizeTagNames(req.Tags)
+
+	if req.ProjectID != nil && *req.ProjectID != 0 {
+		err := h.batchCheckProject(ctx, state, *req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		task.ProjectID = req.ProjectID
+	}
+
+	if req.WorkspaceID != nil && *req.WorkspaceID != 0 {
+		_, err := h.repo.GetWorkspaceRole(ctx, *req.WorkspaceID, state.userID)
+		if errors.Is(err, repository.ErrNotWorkspaceMember) {
+			return nil, batchFail(http.StatusBadRequest, "Workspace not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		task.WorkspaceID = req.WorkspaceID
+	}
+
+	err = h.repo.CreateTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.events.AppendEvents(ctx, stampEvents(r, state.userID, models.NewTaskEvent(models.EventTaskCreated, nil, task))...)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// batchUpdate повторяет проверки UpdateTask, кроме переноса между родителями и пространствами
+func (h *TaskHandler) batchUpdate(ctx context.Context, r *http.Request, state *batchState, taskID uint, req types.UpdateTaskRequest) (models.Task, error) {
+	task, err := state.authorize(taskID, models.AccessEdit)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	if req.ParentID != nil || req.WorkspaceID != nil {
+		return models.Task{}, batchFail(http.StatusBadRequest, "Tasks can't be moved between parents or workspaces in a batch")
+	}
+
+	if req.Status != nil && *req.Status == task.Status {
+		req.Status = nil
+	}
+	if req.Status != nil && !task.Status.CanTransitionTo(*req.Status) {
+		return models.Task{}, batchFail(http.StatusConflict, "Status transition from '"+string(task.Status)+"' to '"+string(*req.Status)+"' is not allowed")
+	}
+
+	if req.Status != nil && *req.Status == models.StatusCompleted && h.policy.RequireSubtasksCompleted {
+		open, err := h.repo.CountOpenSubtasks(ctx, task.ID)
+		if err != nil {
+			return models.Task{}, err
+		}
+		if open > 0 {
+			return models.Task{}, batchFail(http.StatusConflict, "Task has open subtasks")
+		}
+	}
+
+	if req.Status != nil && *req.Status == models.StatusInProgress {
+		blockers, err := h.repo.CountOpenDependencies(ctx, task.ID)
+		if err != nil {
+			return models.Task{}, err
+		}
+		if blockers > 0 {
+			return models.Task{}, batchFail(http.StatusConflict, "Task is blocked by unfinished tasks")
+		}
+	}
+
+	var projectID *uint
+	if req.ProjectID != nil {
+		if task.UserID != state.userID {
+			return models.Task{}, batchFail(http.StatusForbidden, "Only the task owner can move it")
+		}
+		if *req.ProjectID != 0 {
+			err := h.batchCheckProject(ctx, state, *req.ProjectID)
+			if err != nil {
+				return models.Task{}, err
+			}
+			projectID = req.ProjectID
+		}
+	}
+
+	if req.Deadline != nil && req.Deadline.Before(time.Now()) {
+		return models.Task{}, batchFail(http.StatusBadRequest, "deadline can't be earlier than current time")
+	}
+
+	updated, err := h.applyTaskUpdate(ctx, task, req, projectID, nil, nil)
+	if errors.Is(err, repository.ErrInvalidStatusTransition) {
+		return models.Task{}, batchFail(http.StatusConflict, "Task status was changed concurrently, transition is not allowed")
+	}
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	err = h.recordTaskUpdate(ctx, r, state.userID, task, updated)
+	if err != nil {
+		return models.Task{}, err
+	}
+	err = h.recordRevision(ctx, state.userID, task, updated, nil)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	if req.Status != nil && updated.Status == models.StatusCompleted {
+		err = h.spawnNextOccurrence(ctx, updated)
+		if err != nil {
+			return models.Task{}, err
+		}
+	}
+
+	return updated, nil
+}
+
+// batchDelete, как и DeleteTask, доступен только владельцу и переносит задачу в корзину вместе с подзадачами
+func (h *TaskHandler) batchDelete(ctx context.Context, r *http.Request, state *batchState, taskID uint) error {
+	task, err := state.authorize(taskID, models.AccessOwner)
+	if err != nil {
+		return err
+	}
+
+	err = h.repo.DeleteTask(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+
+	err = h.events.AppendEvents(ctx, stampEvents(r, state.userID, models.NewTaskEvent(models.EventTaskDeleted, &task, nil))...)
+	if err != nil {
+		return err
+	}
+
+	return h.batchReload(ctx, state)
+}
+
+// batchReload перечитывает задачи пакета после удаления: вместе с задачей в корзину ушли её подзадачи,
+// и следующие операции над ними должны получить 404, как одиночные запросы
+func (h *TaskHandler) batchReload(ctx context.Context, state *batchState) error {
+	tasks, err := h.repo.GetTasksWithAccess(ctx, state.userID, slices.Sorted(maps.Keys(state.tasks)))
+	if err != nil {
+		return err
+	}
+
+	state.tasks = make(map[uint]models.AccessibleTask, len(tasks))
+	for _, t := range tasks {
+		state.tasks[t.Task.ID] = t
+	}
+	return nil
+}
+
+// batchCheckProject - checkTargetProject для пакета. Результат запоминается, чтобы не читать проект на каждую операцию
+func (h *TaskHandler) batchCheckProject(ctx context.Context, state *batchState, projectID uint) error {
+	if err, ok := state.projects[projectID]; ok {
+		return err
+	}
+
+	project, err := getOwnedProject(ctx, h.projects, state.userID, projectID)
+	switch {
+	case errors.Is(err, repository.ErrProjectNotFound):
+		err = batchFail(http.StatusBadRequest, "Project not found")
+	case err != nil:
+		return err
+	case project.Archived:
+		err = batchFail(http.StatusConflict, "Project is archived")
+	}
+
+	state.projects[projectID] = err
+	return err
+}
+
+// authorize проверяет доступ к задаче, загруженной вместе с пакетом. Как и authorizeTask,
+// задачу без доступа не отличить от несуществующей
+func (s *batchState) authorize(taskID uint, need models.TaskAccess) (models.Task, error) {
+	t, ok := s.tasks[taskID]
+	if !ok || t.Access == models.AccessNone {
+		return models.Task{}, batchFail(http.StatusNotFound, "Task not found")
+	}
+	if t.Access < need {
+		return models.Task{}, batchFail(http.StatusForbidden, "Not enough permissions for this task")
+	}
+
+	return t.Task, nil
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func batchRequest(t *testing.T, req types.BatchTaskRequest) *http.Request {
+	t.Helper()
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	r := httptest.NewRequest("POST", "/tasks/batch", bytes.NewReader(body))
+	return r.WithContext(withUserID(r.Context(), 10))
+}
+
+func TestTaskHandler_BatchTasks_Atomic(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	eventStore := &fakeEventStore{}
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, eventStore, &fakeAuditRecorder{}, TaskPolicy{})
+
+	mockRepo.On("GetTasksWithAccess", mock.Anything, uint(10), []uint{2, 3}).Return([]models.AccessibleTask{
+		{Task: models.Task{ID: 2, UserID: 10, Name: "Old", Status: models.StatusPending}, Access: models.AccessOwner},
+		{Task: models.Task{ID: 3, UserID: 10, Status: models.StatusPending}, Access: models.AccessOwner},
+	}, nil).Once()
+	mockRepo.On("CreateTask", mock.Anything, mock.Anything).Return(nil).Once()
+	mockRepo.On("UpdateTaskName", mock.Anything, uint(2), "New").Return(nil).Once()
+	mockRepo.On("DeleteTask", mock.Anything, uint(3)).Return(nil).Once()
+	mockRepo.On("GetTasksWithAccess", mock.Anything, uint(10), []uint{2, 3}).Return([]models.AccessibleTask{
+		{Task: models.Task{ID: 2, UserID: 10, Name: "New", Status: models.StatusPending}, Access: models.AccessOwner},
+	}, nil).Once()
+	mockRepo.On("CreateTaskRevision", mock.Anything, mock.Anything).Return(nil).Once()
+
+	name := "New"
+	rr := httptest.NewRecorder()
+	handler.BatchTasks(rr, batchRequest(t, types.BatchTaskRequest{Operations: []types.BatchTaskOperation{
+		{Op: types.BatchCreate, Create: &types.CreateTaskRequest{Name: "Created", Deadline: time.Now().Add(time.Hour)}},
+		{Op: types.BatchUpdate, TaskID: 2, Update: &types.UpdateTaskRequest{Name: &name}},
+		{Op: types.BatchDelete, TaskID: 3},
+	}}))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp types.BatchTaskResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, types.BatchAtomic, resp.Mode)
+	assert.Equal(t, 3, resp.Succeeded)
+	require.Len(t, resp.Results, 3)
+	assert.Equal(t, uint(1), resp.Results[0].TaskID)
+	assert.Equal(t, http.StatusCreated, resp.Results[0].Status)
+	assert.Equal(t, "New", resp.Results[1].Task.Name)
+	assert.Equal(t, http.StatusNoContent, resp.Results[2].Status)
+
+	var raw struct {
+		Results []struct {
+			Task json.RawMessage `json:"task"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &raw))
+	assertTaskJSONKeys(t, raw.Results[0].Task)
+	assertTaskJSONKeys(t, raw.Results[1].Task)
+
+	assert.Equal(t, []models.EventType{models.EventTaskCreated, models.EventTaskUpdated, models.EventTaskDeleted}, eventStore.eventTypes())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskHandler_BatchTasks_AtomicStopsOnFirstFailure(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	mockRepo.On("GetTasksWithAccess", mock.Anything, uint(10), []uint{1, 2, 3}).Return([]models.AccessibleTask{
+		{Task: models.Task{ID: 1, UserID: 10}, Access: models.AccessOwner},
+		{Task: models.Task{ID: 2, UserID: 20}, Access: models.AccessEdit},
+		{Task: models.Task{ID: 3, UserID: 10}, Access: models.AccessOwner},
+	}, nil).Once()
+	mockRepo.On("DeleteTask", mock.Anything, uint(1)).Return(nil).Once()
+	mockRepo.On("GetTasksWithAccess", mock.Anything, uint(10), []uint{1, 2, 3}).Return([]models.AccessibleTask{
+		{Task: models.Task{ID: 2, UserID: 20}, Access: models.AccessEdit},
+		{Task: models.Task{ID: 3, UserID: 10}, Access: models.AccessOwner},
+	}, nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.BatchTasks(rr, batchRequest(t, types.BatchTaskRequest{Operations: []types.BatchTaskOperation{
+		{Op: types.BatchDelete, TaskID: 1},
+		{Op: types.BatchDelete, TaskID: 2},
+		{Op: types.BatchDelete, TaskID: 3},
+	}}))
+
+	require.Equal(t, http.StatusForbidden, rr.Code)
+
+	var resp types.BatchTaskErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Index)
+	assert.Equal(t, "Not enough permissions for this task", resp.Error)
+	mockRepo.AssertNotCalled(t, "DeleteTask", mock.Anything, uint(3))
+}
+
+func TestTaskHandler_BatchTasks_PerItem(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	projects := new(MockProjectRepository)
+	handler := NewTaskHandler(mockRepo, projects, new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	// Задача 8 чужая и без доступа, поэтому в ответе её нет совсем
+	mockRepo.On("GetTasksWithAccess", mock.Anything, uint(10), []uint{1, 8, 2}).Return([]models.AccessibleTask{
+		{Task: models.Task{ID: 1, UserID: 10, Status: models.StatusCompleted}, Access: models.AccessOwner},
+		{Task: models.Task{ID: 2, UserID: 10}, Access: models.AccessOwner},
+	}, nil).Once()
+	projects.On("GetProjectByID", mock.Anything, uint(4)).Return(models.Project{ID: 4, UserID: 10, Archived: true}, nil).Once()
+	mockRepo.On("DeleteTask", mock.Anything, uint(2)).Return(nil).Once()
+	mockRepo.On("GetTasksWithAccess", mock.Anything, uint(10), []uint{1, 2}).Return([]models.AccessibleTask{
+		{Task: models.Task{ID: 1, UserID: 10, Status: models.StatusCompleted}, Access: models.AccessOwner},
+	}, nil).Once()
+
+	pending, project := models.StatusPending, uint(4)
+	rr := httptest.NewRecorder()
+	handler.BatchTasks(rr, batchRequest(t, types.BatchTaskRequest{Mode: types.BatchPerItem, Operations: []types.BatchTaskOperation{
+		{Op: types.BatchUpdate, TaskID: 1, Update: &types.UpdateTaskRequest{Status: &pending}},
+		{Op: types.BatchDelete, TaskID: 8},
+		{Op: types.BatchCreate, Create: &types.CreateTaskRequest{Name: "In archive", Deadline: time.Now().Add(time.Hour), ProjectID: &project}},
+		{Op: types.BatchCreate, Create: &types.CreateTaskRequest{Name: "Also archive", Deadline: time.Now().Add(time.Hour), ProjectID: &project}},
+		{Op: types.BatchDelete, TaskID: 2},
+	}}))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp types.BatchTaskResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Succeeded)
+	assert.Equal(t, 4, resp.Failed)
+
+	statuses := []int{}
+	for _, result := range resp.Results {
+		statuses = append(statuses, result.Status)
+	}
+	assert.Equal(t, []int{http.StatusConflict, http.StatusNotFound, http.StatusConflict, http.StatusConflict, http.StatusNoContent}, statuses)
+	assert.Equal(t, "Project is archived", resp.Results[3].Error)
+	// У неудавшихся операций над задачами в ответе есть их id
+	assert.Equal(t, uint(1), resp.Results[0].TaskID)
+	assert.Equal(t, uint(8), resp.Results[1].TaskID)
+
+	// Проект проверяется один раз на весь пакет
+	projects.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything)
+}
+
+// Удаление родителя уносит в корзину и подзадачу, поэтому следующая операция над ней получает 404
+func TestTaskHandler_BatchTasks_UpdateChildAfterParentDeleted(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	parentID := uint(2)
+	mockRepo.On("GetTasksWithAccess", mock.Anything, uint(10), []uint{2, 3}).Return([]models.AccessibleTask{
+		{Task: models.Task{ID: 2, UserID: 10}, Access: models.AccessOwner},
+		{Task: models.Task{ID: 3, UserID: 10, ParentID: &parentID}, Access: models.AccessOwner},
+	}, nil).Once()
+	mockRepo.On("DeleteTask", mock.Anything, uint(2)).Return(nil).Once()
+	mockRepo.On("GetTasksWithAccess", mock.Anything, uint(10), []uint{2, 3}).Return([]models.AccessibleTask{}, nil).Once()
+
+	name := "Renamed"
+	rr := httptest.NewRecorder()
+	handler.BatchTasks(rr, batchRequest(t, types.BatchTaskRequest{Mode: types.BatchPerItem, Operations: []types.BatchTaskOperation{
+		{Op: types.BatchDelete, TaskID: 2},
+		{Op: types.BatchUpdate, TaskID: 3, Update: &types.UpdateTaskRequest{Name: &name}},
+	}}))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp types.BatchTaskResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, http.StatusNoContent, resp.Results[0].Status)
+	assert.Equal(t, uint(3), resp.Results[1].TaskID)
+	assert.Equal(t, http.StatusNotFound, resp.Results[1].Status)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateTaskName", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTaskHandler_BatchTasks_InvalidRequest(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	tests := []struct {
+		name string
+		req  types.BatchTaskRequest
+	}{
+		{"empty", types.BatchTaskRequest{}},
+		{"unknown mode", types.BatchTaskRequest{Mode: "best_effort", Operations: []types.BatchTaskOperation{{Op: types.BatchDelete, TaskID: 1}}}},
+		{"update without body", types.BatchTaskRequest{Operations: []types.BatchTaskOperation{{Op: types.BatchUpdate, TaskID: 1}}}},
+		{"missing task id", types.BatchTaskRequest{Operations: []types.BatchTaskOperation{{Op: types.BatchDelete}}}},
+		{"duplicate task", types.BatchTaskRequest{Operations: []types.BatchTaskOperation{
+			{Op: types.BatchDelete, TaskID: 1},
+			{Op: types.BatchUpdate, TaskID: 1, Update: &types.UpdateTaskRequest{}},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler.BatchTasks(rr, batchRequest(t, tt.req))
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+
+	mockRepo.AssertNotCalled(t, "GetTasksWithAccess", mock.Anything, mock.Anything, mock.Anything)
+}
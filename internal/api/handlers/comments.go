@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CommentHandler ведёт заметки о ходе работы над задачей, не трогая её описание
+type CommentHandler struct {
+	repo  repository.CommentRepository
+	tasks repository.TaskRepository
+}
+
+func NewCommentHandler(repo repository.CommentRepository, tasks repository.TaskRepository) *CommentHandler {
+	return &CommentHandler{repo: repo, tasks: tasks}
+}
+
+func (h *CommentHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	task, ok := loadAuthorizedTask(w, r, h.tasks, models.AccessView)
+	if !ok {
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	comments, err := h.repo.GetCommentsByTaskID(r.Context(), task.ID, limit, offset)
+	if err != nil {
+		slog.Error("Failed to get task comments", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get comments"})
+
+		return
+	}
+
+	resp := make([]types.CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		resp = append(resp, toCommentResponse(comment))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// CreateComment: писать комментарии могут владелец и редакторы, зрители только читают
+func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	task, ok := loadAuthorizedTask(w, r, h.tasks, models.AccessEdit)
+	if !ok {
+		return
+	}
+
+	body, ok := decodeCommentBody(w, r)
+	if !ok {
+		return
+	}
+
+	comment := &models.TaskComment{
+		TaskID: task.ID,
+		UserID: userID,
+		Body:   body,
+	}
+
+	err = h.repo.CreateComment(r.Context(), comment)
+	if err != nil {
+		slog.Error("Failed to create comment", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to create comment"})
+
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, toCommentResponse(*comment))
+}
+
+// UpdateComment: править комментарий может только его автор
+func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	_, comment, ok := h.loadComment(w, r)
+	if !ok {
+		return
+	}
+
+	if comment.UserID != userID {
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, map[string]string{"error": "Only the author can edit a comment"})
+		return
+	}
+
+	body, ok := decodeCommentBody(w, r)
+	if !ok {
+		return
+	}
+
+	if body == comment.Body {
+		render.JSON(w, r, toCommentResponse(comment))
+		return
+	}
+
+	comment.Body = body
+	err = h.repo.UpdateCommentBody(r.Context(), &comment)
+	if errors.Is(err, repository.ErrCommentNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Comment not found"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to update comment", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to update comment"})
+
+		return
+	}
+
+	render.JSON(w, r, toCommentResponse(comment))
+}
+
+// DeleteComment: удалить комментарий может его автор или владелец задачи
+func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	task, comment, ok := h.loadComment(w, r)
+	if !ok {
+		return
+	}
+
+	if comment.UserID != userID && task.UserID != userID {
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, map[string]string{"error": "Only the author or the task owner can delete a comment"})
+		return
+	}
+
+	err = h.repo.DeleteComment(r.Context(), comment.ID)
+	if err != nil && !errors.Is(err, repository.ErrCommentNotFound) {
+		slog.Error("Failed to delete comment", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to delete comment"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadComment проверяет доступ к задаче и что комментарий относится именно к ней
+func (h *CommentHandler) loadComment(w http.ResponseWriter, r *http.Request) (models.Task, models.TaskComment, bool) {
+	task, ok := loadAuthorizedTask(w, r, h.tasks, models.AccessView)
+	if !ok {
+		return models.Task{}, models.TaskComment{}, false
+	}
+
+	commentID, err := strconv.ParseUint(chi.URLParam(r, "commentID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid comment ID"})
+		return models.Task{}, models.TaskComment{}, false
+	}
+
+	comment, err := h.repo.GetCommentByID(r.Context(), uint(commentID))
+	if errors.Is(err, repository.ErrCommentNotFound) || (err == nil && comment.TaskID != task.ID) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Comment not found"})
+		return models.Task{}, models.TaskComment{}, false
+	}
+	if err != nil {
+		slog.Error("Failed to get comment by ID", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.Task{}, models.TaskComment{}, false
+	}
+
+	return task, comment, true
+}
+
+func decodeCommentBody(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req types.CommentRequest
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode comment request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return "", false
+	}
+
+	body, err := models.NormalizeCommentBody(req.Body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+		return "", false
+	}
+
+	return body, true
+}
+
+func toCommentResponse(comment models.TaskComment) types.CommentResponse {
+	return types.CommentResponse{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		AuthorID:  comment.UserID,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		EditedAt:  comment.EditedAt,
+	}
+}
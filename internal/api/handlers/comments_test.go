@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) CreateComment(ctx context.Context, comment *models.TaskComment) error {
+	args := m.Called(ctx, comment)
+	comment.ID = 1
+	comment.CreatedAt = time.Now()
+	return args.Error(0)
+}
+func (m *MockCommentRepository) GetCommentByID(ctx context.Context, id uint) (models.TaskComment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.TaskComment), args.Error(1)
+}
+func (m *MockCommentRepository) GetCommentsByTaskID(ctx context.Context, taskID uint, limit, offset int) ([]models.TaskComment, error) {
+	args := m.Called(ctx, taskID, limit, offset)
+	return args.Get(0).([]models.TaskComment), args.Error(1)
+}
+func (m *MockCommentRepository) UpdateCommentBody(ctx context.Context, comment *models.TaskComment) error {
+	args := m.Called(ctx, comment)
+	editedAt := time.Now()
+	comment.EditedAt = &editedAt
+	return args.Error(0)
+}
+func (m *MockCommentRepository) DeleteComment(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func withCommentID(req *http.Request, taskID, commentID string, userID uint) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("taskID", taskID)
+	rctx.URLParams.Add("commentID", commentID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(withUserID(ctx, userID))
+}
+
+func commentBody(body string) *bytes.Reader {
+	data, _ := json.Marshal(types.CommentRequest{Body: body})
+	return bytes.NewReader(data)
+}
+
+func TestCommentHandler_GetComments(t *testing.T) {
+	mockRepo := new(MockCommentRepository)
+	mockTasks := new(MockTaskRepository)
+	handler := NewCommentHandler(mockRepo, mockTasks)
+
+	mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+	mockRepo.On("GetCommentsByTaskID", mock.Anything, uint(1), 5, 10).Return([]models.TaskComment{
+		{ID: 3, TaskID: 1, UserID: 10, Body: "first"},
+	}, nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.GetComments(rr, withTaskID(httptest.NewRequest("GET", "/tasks/1/comments?limit=5&offset=10", nil), "1", 10))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp []types.CommentResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, "first", resp[0].Body)
+	assert.Equal(t, uint(10), resp[0].AuthorID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCommentHandler_CreateComment(t *testing.T) {
+	testCases := []struct {
+		name         string
+		task         models.Task
+		share        models.SharePermission
+		body         string
+		expectCreate bool
+		expectedCode int
+	}{
+		{"Success", models.Task{ID: 1, UserID: 10}, "", "  *started* \r\n", true, http.StatusCreated},
+		{"Empty Body", models.Task{ID: 1, UserID: 10}, "", "   ", false, http.StatusBadRequest},
+		{"Too Long", models.Task{ID: 1, UserID: 10}, "", strings.Repeat("a", models.MaxCommentLength+1), false, http.StatusBadRequest},
+		{"Foreign Task", models.Task{ID: 1, UserID: 20}, "", "hi", false, http.StatusNotFound},
+		{"Shared With Viewer", models.Task{ID: 1, UserID: 20}, models.ShareViewer, "*started*", false, http.StatusForbidden},
+		{"Shared With Editor", models.Task{ID: 1, UserID: 20}, models.ShareEditor, "*started*", true, http.StatusCreated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockCommentRepository)
+			mockTasks := new(MockTaskRepository)
+			handler := NewCommentHandler(mockRepo, mockTasks)
+
+			mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(tc.task, nil).Once()
+			if tc.task.UserID != 10 {
+				shareErr := error(nil)
+				if tc.share == "" {
+					shareErr = repository.ErrShareNotFound
+				}
+				mockTasks.On("GetTaskSharePermission", mock.Anything, uint(1), uint(10)).Return(tc.share, shareErr).Once()
+			}
+			if tc.expectCreate {
+				mockRepo.On("CreateComment", mock.Anything, mock.MatchedBy(func(c *models.TaskComment) bool {
+					return c.TaskID == 1 && c.UserID == 10 && c.Body == "*started*"
+				})).Return(nil).Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.CreateComment(rr, withTaskID(httptest.NewRequest("POST", "/tasks/1/comments", commentBody(tc.body)), "1", 10))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			mockRepo.AssertExpectations(t)
+			if !tc.expectCreate {
+				mockRepo.AssertNotCalled(t, "CreateComment")
+			}
+		})
+	}
+}
+
+func TestCommentHandler_UpdateComment(t *testing.T) {
+	testCases := []struct {
+		name         string
+		comment      models.TaskComment
+		expectUpdate bool
+		expectedCode int
+	}{
+		{"Success", models.TaskComment{ID: 5, TaskID: 1, UserID: 10, Body: "old"}, true, http.StatusOK},
+		{"Comment Of Another Task", models.TaskComment{ID: 5, TaskID: 2, UserID: 10, Body: "old"}, false, http.StatusNotFound},
+		{"Not Author", models.TaskComment{ID: 5, TaskID: 1, UserID: 30, Body: "old"}, false, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockCommentRepository)
+			mockTasks := new(MockTaskRepository)
+			handler := NewCommentHandler(mockRepo, mockTasks)
+
+			mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+			mockRepo.On("GetCommentByID", mock.Anything, uint(5)).Return(tc.comment, nil).Once()
+			if tc.expectUpdate {
+				mockRepo.On("UpdateCommentBody", mock.Anything, mock.MatchedBy(func(c *models.TaskComment) bool {
+					return c.ID == 5 && c.Body == "new"
+				})).Return(nil).Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.UpdateComment(rr, withCommentID(httptest.NewRequest("PATCH", "/tasks/1/comments/5", commentBody("new")), "1", "5", 10))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			mockRepo.AssertExpectations(t)
+			if tc.expectUpdate {
+				var resp types.CommentResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, "new", resp.Body)
+				assert.NotNil(t, resp.EditedAt)
+			}
+		})
+	}
+}
+
+func TestCommentHandler_DeleteComment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockCommentRepository)
+		mockTasks := new(MockTaskRepository)
+		handler := NewCommentHandler(mockRepo, mockTasks)
+
+		mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+		mockRepo.On("GetCommentByID", mock.Anything, uint(5)).Return(models.TaskComment{ID: 5, TaskID: 1, UserID: 10}, nil).Once()
+		mockRepo.On("DeleteComment", mock.Anything, uint(5)).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.DeleteComment(rr, withCommentID(httptest.NewRequest("DELETE", "/tasks/1/comments/5", nil), "1", "5", 10))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockRepo := new(MockCommentRepository)
+		mockTasks := new(MockTaskRepository)
+		handler := NewCommentHandler(mockRepo, mockTasks)
+
+		mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+		mockRepo.On("GetCommentByID", mock.Anything, uint(5)).Return(models.TaskComment{}, repository.ErrCommentNotFound).Once()
+
+		rr := httptest.NewRecorder()
+		handler.DeleteComment(rr, withCommentID(httptest.NewRequest("DELETE", "/tasks/1/comments/5", nil), "1", "5", 10))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockRepo.AssertNotCalled(t, "DeleteComment")
+	})
+}
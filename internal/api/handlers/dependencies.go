@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// AddDependency делает задачу из URL заблокированной задачей из тела запроса
+func (h *TaskHandler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.loadTask(w, r, models.AccessOwner)
+	if !ok {
+		return
+	}
+
+	var req types.AddDependencyRequest
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+		return
+	}
+
+	if req.TaskID == task.ID {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Task can't depend on itself"})
+		return
+	}
+
+	blocker, err := authorizeTask(r.Context(), h.repo, task.UserID, req.TaskID, models.AccessOwner)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Blocking task not found"})
+		return
+	}
+
+	updated := task
+	updated.BlockedBy = append(slices.Clone(task.BlockedBy), blocker.ID)
+
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		err := h.repo.AddTaskDependency(ctx, task.UserID, task.ID, blocker.ID)
+		if err != nil {
+			return err
+		}
+		return h.recordTaskUpdate(ctx, r, task.UserID, task, updated)
+	})
+	if errors.Is(err, repository.ErrDependencyCycle) {
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "Dependency would create a cycle"})
+		return
+	}
+	if errors.Is(err, repository.ErrDependencyExists) {
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "Dependency already exists"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to add task dependency", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to add dependency"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.loadTask(w, r, models.AccessOwner)
+	if !ok {
+		return
+	}
+
+	dependsOnID, err := strconv.ParseUint(chi.URLParam(r, "dependsOnID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid task ID"})
+		return
+	}
+
+	updated := task
+	updated.BlockedBy = slices.DeleteFunc(slices.Clone(task.BlockedBy), func(id uint) bool { return id == uint(dependsOnID) })
+
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		err := h.repo.RemoveTaskDependency(ctx, task.ID, uint(dependsOnID))
+		if err != nil {
+			return err
+		}
+		return h.recordTaskUpdate(ctx, r, task.UserID, task, updated)
+	})
+	if errors.Is(err, repository.ErrDependencyNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Dependency not found"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to remove task dependency", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to remove dependency"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTaskHandler_AddDependency(t *testing.T) {
+	newRequest := func(dependsOnID uint) *http.Request {
+		body, _ := json.Marshal(types.AddDependencyRequest{TaskID: dependsOnID})
+		return withTaskID(httptest.NewRequest("POST", "/tasks/1/dependencies", bytes.NewReader(body)), "1", 10)
+	}
+	task := models.Task{ID: 1, UserID: 10, Status: models.StatusPending}
+
+	testCases := []struct {
+		name         string
+		dependsOnID  uint
+		blocker      models.Task
+		repoErr      error
+		expectedCode int
+	}{
+		{"Success", 2, models.Task{ID: 2, UserID: 10}, nil, http.StatusNoContent},
+		{"Cycle", 2, models.Task{ID: 2, UserID: 10}, repository.ErrDependencyCycle, http.StatusConflict},
+		{"Already Exists", 2, models.Task{ID: 2, UserID: 10}, repository.ErrDependencyExists, http.StatusConflict},
+		{"Another User's Task", 2, models.Task{ID: 2, UserID: 99}, nil, http.StatusBadRequest},
+		{"Self Dependency", 1, models.Task{}, nil, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			eventStore := &fakeEventStore{}
+			handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, eventStore, &fakeAuditRecorder{}, TaskPolicy{})
+
+			mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+			mockRepo.On("GetTaskByID", mock.Anything, tc.dependsOnID).Return(tc.blocker, nil).Maybe()
+			mockRepo.On("GetTaskSharePermission", mock.Anything, mock.Anything, uint(10)).Return(models.SharePermission(""), repository.ErrShareNotFound).Maybe()
+			mockRepo.On("AddTaskDependency", mock.Anything, uint(10), uint(1), tc.dependsOnID).Return(tc.repoErr).Maybe()
+
+			rr := httptest.NewRecorder()
+			handler.AddDependency(rr, newRequest(tc.dependsOnID))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusNoContent {
+				assert.Equal(t, []models.EventType{models.EventTaskUpdated}, eventStore.eventTypes())
+			} else {
+				assert.Empty(t, eventStore.events)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_RemoveDependency_NotFound(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+	mockRepo.On("RemoveTaskDependency", mock.Anything, uint(1), uint(2)).Return(repository.ErrDependencyNotFound).Once()
+
+	req := httptest.NewRequest("DELETE", "/tasks/1/dependencies/2", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("taskID", "1")
+	rctx.URLParams.Add("dependsOnID", "2")
+	req = req.WithContext(withUserID(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), 10))
+
+	rr := httptest.NewRecorder()
+	handler.RemoveDependency(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestTaskHandler_UpdateTask_Blocked(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10, Status: models.StatusPending}, nil).Once()
+	mockRepo.On("CountOpenDependencies", mock.Anything, uint(1)).Return(1, nil).Once()
+
+	status := models.StatusInProgress
+	body, _ := json.Marshal(types.UpdateTaskRequest{Status: &status})
+	rr := httptest.NewRecorder()
+	handler.UpdateTask(rr, withTaskID(httptest.NewRequest("PATCH", "/tasks/1", bytes.NewReader(body)), "1", 10))
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockRepo.AssertNotCalled(t, "UpdateTaskStatus")
+}
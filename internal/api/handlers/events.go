@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"to-do-list/internal/models"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// EventStore пишет доменные события в outbox. Вызывается внутри Transactor.WithinTx,
+// чтобы событие записалось вместе с изменением или не записалось вовсе
+type EventStore interface {
+	AppendEvents(ctx context.Context, events ...models.DomainEvent) error
+}
+
+// stampEvents дополняет события тем, что известно из запроса: кто их вызвал, request_id и адрес клиента
+func stampEvents(r *http.Request, actorID uint, events ...models.DomainEvent) []models.DomainEvent {
+	for i := range events {
+		events[i].ActorID = &actorID
+		events[i].RequestID = chimiddleware.GetReqID(r.Context())
+		events[i].IP = clientIP(r)
+	}
+	return events
+}
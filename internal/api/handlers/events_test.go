@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTransactor просто вызывает fn: транзакции проверяются на уровне репозитория
+type fakeTransactor struct{}
+
+type fakeTxKey struct{}
+
+func (fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, fakeTxKey{}, true))
+}
+
+// inFakeTx совпадает с контекстом, переданным внутрь fakeTransactor.WithinTx
+var inFakeTx = mock.MatchedBy(func(ctx context.Context) bool {
+	return ctx.Value(fakeTxKey{}) != nil
+})
+
+type fakeEventStore struct {
+	events []models.DomainEvent
+	err    error
+}
+
+func (f *fakeEventStore) AppendEvents(ctx context.Context, events ...models.DomainEvent) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.events = append(f.events, events...)
+	return nil
+}
+
+func (f *fakeEventStore) eventTypes() []models.EventType {
+	var eventTypes []models.EventType
+	for _, e := range f.events {
+		eventTypes = append(eventTypes, e.Type)
+	}
+	return eventTypes
+}
+
+func TestTaskHandler_AppendsTaskEvents(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	eventStore := &fakeEventStore{}
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, eventStore, &fakeAuditRecorder{}, TaskPolicy{})
+
+	task := models.Task{ID: 1, UserID: 10, Status: models.StatusPending}
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil)
+	mockRepo.On("CountOpenDependencies", mock.Anything, uint(1)).Return(0, nil)
+	mockRepo.On("UpdateTaskStatus", mock.Anything, uint(1), models.StatusInProgress).Return(nil).Once()
+	mockRepo.On("CreateTaskRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockRepo.On("DeleteTask", mock.Anything, uint(1)).Return(nil).Once()
+
+	status := models.StatusInProgress
+	body, _ := json.Marshal(types.UpdateTaskRequest{Status: &status})
+	req := withTaskID(httptest.NewRequest("PATCH", "/tasks/1", bytes.NewReader(body)), "1", 10)
+	req.RemoteAddr = "203.0.113.7:4242"
+	rr := httptest.NewRecorder()
+	handler.UpdateTask(rr, req)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	require.Equal(t, []models.EventType{models.EventTaskUpdated, models.EventTaskStatusChanged}, eventStore.eventTypes())
+	event := eventStore.events[1]
+	require.NotNil(t, event.ActorID)
+	assert.Equal(t, uint(10), *event.ActorID)
+	assert.Equal(t, "203.0.113.7", event.IP)
+	payload, err := event.TaskPayload()
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, payload.Before.Status)
+	assert.Equal(t, models.StatusInProgress, payload.After.Status)
+
+	eventStore.events = nil
+	rr = httptest.NewRecorder()
+	handler.DeleteTask(rr, withTaskID(httptest.NewRequest("DELETE", "/tasks/1", nil), "1", 10))
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	require.Equal(t, []models.EventType{models.EventTaskDeleted}, eventStore.eventTypes())
+	assert.Equal(t, uint(1), eventStore.events[0].AggregateID)
+}
+
+func TestTaskHandler_FailsWhenEventIsNotStored(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	eventStore := &fakeEventStore{err: errors.New("outbox is down")}
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, eventStore, &fakeAuditRecorder{}, TaskPolicy{})
+
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+	mockRepo.On("DeleteTask", mock.Anything, uint(1)).Return(nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.DeleteTask(rr, withTaskID(httptest.NewRequest("DELETE", "/tasks/1", nil), "1", 10))
+
+	// Без события изменение откатывается вместе с транзакцией
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/auth"
+	"to-do-list/internal/middleware"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+	"to-do-list/internal/stream"
+
+	"github.com/go-chi/render"
+	"golang.org/x/net/websocket"
+)
+
+// LivePolicy: сервер шлёт ping каждые PingInterval и закрывает соединение, если за PingInterval+PongTimeout
+// от клиента ничего не пришло. AuthTimeout - сколько ждать сообщения auth, если токена не было в заголовке.
+// AccessTTL - сколько соединение помнит доступ к задаче, прежде чем проверить его заново
+type LivePolicy struct {
+	PingInterval   time.Duration
+	PongTimeout    time.Duration
+	AuthTimeout    time.Duration
+	WriteTimeout   time.Duration
+	MaxMessageSize int
+	AccessTTL      time.Duration
+}
+
+// LiveHandler - WebSocket для совместной работы: клиент подписывается на задачи и проекты
+// и получает их изменения
+type LiveHandler struct {
+	rooms    *stream.Rooms
+	tm       *auth.TokenManager
+	sessions middleware.SessionChecker
+	tasks    repository.TaskRepository
+	projects repository.ProjectRepository
+	policy   LivePolicy
+}
+
+func NewLiveHandler(rooms *stream.Rooms, tm *auth.TokenManager, sessions middleware.SessionChecker,
+	tasks repository.TaskRepository, projects repository.ProjectRepository, policy LivePolicy) *LiveHandler {
+	return &LiveHandler{rooms: rooms, tm: tm, sessions: sessions, tasks: tasks, projects: projects, policy: policy}
+}
+
+// Serve принимает соединение. Токен берётся из Authorization, а если его нет (браузерный WebSocket
+// не умеет слать заголовки) - из первого сообщения {"type":"auth","token":"..."}. Раз токен не
+// приходит в cookie, подключиться с чужого сайта от имени пользователя нельзя, поэтому Origin не проверяется
+func (h *LiveHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	var userID uint
+	if header := r.Header.Get("Authorization"); header != "" {
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, map[string]string{"error": "Invalid Authorization header format"})
+			return
+		}
+
+		claims, err := middleware.Authenticate(r.Context(), h.tm, h.sessions, token)
+		if errors.Is(err, middleware.ErrInvalidToken) {
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, map[string]string{"error": "Invalid token"})
+			return
+		}
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Internal server error"})
+			return
+		}
+		userID = claims.UserID
+	}
+
+	server := websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			config.Origin, _ = websocket.Origin(config, r)
+			return nil
+		},
+		Handler: func(conn *websocket.Conn) {
+			h.serveConn(conn, userID)
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+func (h *LiveHandler) serveConn(conn *websocket.Conn, userID uint) {
+	defer conn.Close()
+	conn.MaxPayloadBytes = h.policy.MaxMessageSize
+	ctx := conn.Request().Context()
+
+	if userID == 0 {
+		var ok bool
+		userID, ok = h.authenticateConn(ctx, conn)
+		if !ok {
+			return
+		}
+	}
+
+	member, err := h.rooms.Connect(userID)
+	if errors.Is(err, stream.ErrTooManyConnections) {
+		h.send(conn, types.LiveServerMessage{Type: types.LiveError, Error: "Too many connections"})
+		return
+	}
+	if err != nil {
+		h.send(conn, types.LiveServerMessage{Type: types.LiveError, Error: "Server is shutting down"})
+		return
+	}
+	defer h.rooms.Disconnect(member)
+
+	if h.send(conn, types.LiveServerMessage{Type: types.LiveReady, UserID: userID}) != nil {
+		return
+	}
+
+	// Писать в соединение может только эта горутина, поэтому ответы читающей горутины идут через replies
+	replies := make(chan types.LiveServerMessage, 16)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		h.readLoop(ctx, conn, member, userID, replies, stop)
+	}()
+
+	ping := time.NewTicker(h.policy.PingInterval)
+	defer ping.Stop()
+
+	access := make(map[uint]liveAccess)
+
+	for {
+		var msg types.LiveServerMessage
+
+		select {
+		case <-readerDone:
+			return
+		case <-member.Done():
+			reason := "Server is shutting down"
+			if errors.Is(member.Err(), stream.ErrLagging) {
+				reason = "Client is not keeping up, reconnect"
+			}
+			h.send(conn, types.LiveServerMessage{Type: types.LiveError, Error: reason})
+			return
+		case e := <-member.Events():
+			if !h.canReceive(ctx, access, userID, e.Event.Task) {
+				// Подписка на задачу без доступа бесполезна, а про проект решаем по каждой его задаче
+				kind, _, _ := stream.ParseTopic(string(e.Topic))
+				if kind != "task" {
+					continue
+				}
+				h.rooms.Leave(member, e.Topic)
+				msg = types.LiveServerMessage{Type: types.LiveError, Topic: string(e.Topic), Error: "Access to the topic was revoked"}
+				break
+			}
+			msg = types.LiveServerMessage{Type: types.LiveEvent, Topic: string(e.Topic), Event: &types.TaskStreamEvent{
+				ID:         e.Event.ID,
+				Type:       e.Event.Type,
+				Task:       types.NewTaskResponse(e.Event.Task),
+				OccurredAt: e.Event.OccurredAt,
+			}}
+		case msg = <-replies:
+		case <-ping.C:
+			msg = types.LiveServerMessage{Type: types.LivePing}
+		}
+
+		if h.send(conn, msg) != nil {
+			return
+		}
+	}
+}
+
+// liveAccess - доступ соединения к задаче и то, от чего он зависел в момент проверки
+type liveAccess struct {
+	ownerID     uint
+	workspaceID *uint
+	access      models.TaskAccess
+	checkedAt   time.Time
+}
+
+// canReceive проверяет доступ к задаче перед отправкой события: доступ могли отозвать или исключить
+// пользователя из пространства уже после подписки. Проверка запоминается на AccessTTL, а смена владельца
+// или пространства задачи сбрасывает её сразу. При ошибке базы событие не отправляется
+func (h *LiveHandler) canReceive(ctx context.Context, cache map[uint]liveAccess, userID uint, task models.Task) bool {
+	if task.UserID == userID {
+		return true
+	}
+
+	cached, ok := cache[task.ID]
+	if ok && cached.ownerID == task.UserID && sameWorkspace(cached.workspaceID, task.WorkspaceID) &&
+		time.Since(cached.checkedAt) < h.policy.AccessTTL {
+		return cached.access >= models.AccessView
+	}
+
+	access, err := taskAccess(ctx, h.tasks, task, userID)
+	if err != nil {
+		slog.Error("Failed to check live event access", slog.Any("taskID", task.ID), slog.Any("error", err))
+		return false
+	}
+
+	cache[task.ID] = liveAccess{ownerID: task.UserID, workspaceID: task.WorkspaceID, access: access, checkedAt: time.Now()}
+	return access >= models.AccessView
+}
+
+// authenticateConn ждёт сообщения auth и проверяет токен из него, при ошибке сам отвечает клиенту
+func (h *LiveHandler) authenticateConn(ctx context.Context, conn *websocket.Conn) (uint, bool) {
+	_ = conn.SetReadDeadline(time.Now().Add(h.policy.AuthTimeout))
+
+	var msg types.LiveClientMessage
+	err := websocket.JSON.Receive(conn, &msg)
+	if err != nil || msg.Type != types.LiveAuth {
+		h.send(conn, types.LiveServerMessage{Type: types.LiveError, Error: "Authentication required"})
+		return 0, false
+	}
+
+	claims, err := middleware.Authenticate(ctx, h.tm, h.sessions, msg.Token)
+	if errors.Is(err, middleware.ErrInvalidToken) {
+		h.send(conn, types.LiveServerMessage{Type: types.LiveError, Error: "Invalid token"})
+		return 0, false
+	}
+	if err != nil {
+		h.send(conn, types.LiveServerMessage{Type: types.LiveError, Error: "Internal server error"})
+		return 0, false
+	}
+
+	return claims.UserID, true
+}
+
+// readLoop читает сообщения клиента, пока соединение живо. Любое сообщение продлевает срок ожидания
+func (h *LiveHandler) readLoop(ctx context.Context, conn *websocket.Conn, member *stream.Member, userID uint,
+	replies chan<- types.LiveServerMessage, stop <-chan struct{}) {
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(h.policy.PingInterval + h.policy.PongTimeout))
+
+		var data []byte
+		err := websocket.Message.Receive(conn, &data)
+		if err != nil {
+			return
+		}
+
+		var msg types.LiveClientMessage
+		reply, ok := types.LiveServerMessage{Type: types.LiveError, Error: "Invalid message"}, true
+		if json.Unmarshal(data, &msg) == nil {
+			reply, ok = h.handleMessage(ctx, member, userID, msg)
+		}
+		if !ok {
+			continue
+		}
+
+		select {
+		case replies <- reply:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (h *LiveHandler) handleMessage(ctx context.Context, member *stream.Member, userID uint, msg types.LiveClientMessage) (types.LiveServerMessage, bool) {
+	switch msg.Type {
+	case types.LivePing:
+		return types.LiveServerMessage{Type: types.LivePong}, true
+	case types.LivePong:
+		return types.LiveServerMessage{}, false
+	case types.LiveSubscribe:
+		topic, reason := h.authorizeTopic(ctx, userID, msg.Topic)
+		if reason != "" {
+			return types.LiveServerMessage{Type: types.LiveError, Topic: msg.Topic, Error: reason}, true
+		}
+
+		err := h.rooms.Join(member, topic)
+		if errors.Is(err, stream.ErrTooManyTopics) {
+			return types.LiveServerMessage{Type: types.LiveError, Topic: msg.Topic, Error: "Too many subscriptions"}, true
+		}
+		if err != nil {
+			return types.LiveServerMessage{}, false
+		}
+		return types.LiveServerMessage{Type: types.LiveSubscribed, Topic: msg.Topic}, true
+	case types.LiveUnsubscribe:
+		kind, id, err := stream.ParseTopic(msg.Topic)
+		if err != nil {
+			return types.LiveServerMessage{Type: types.LiveError, Topic: msg.Topic, Error: "Invalid topic"}, true
+		}
+		h.rooms.Leave(member, liveTopic(kind, id))
+		return types.LiveServerMessage{Type: types.LiveUnsubscribed, Topic: msg.Topic}, true
+	default:
+		return types.LiveServerMessage{Type: types.LiveError, Error: "Unknown message type"}, true
+	}
+}
+
+// authorizeTopic проверяет, что пользователь может следить за темой: задачей - если видит её,
+// проектом - если он его владелец. Возвращает причину отказа для клиента или пустую строку
+func (h *LiveHandler) authorizeTopic(ctx context.Context, userID uint, value string) (stream.Topic, string) {
+	kind, id, err := stream.ParseTopic(value)
+	if err != nil {
+		return "", "Invalid topic"
+	}
+
+	switch kind {
+	case "task":
+		_, err = authorizeTask(ctx, h.tasks, userID, id, models.AccessView)
+		if errors.Is(err, repository.ErrTaskNotFound) {
+			return "", "Task not found"
+		}
+	case "project":
+		_, err = getOwnedProject(ctx, h.projects, userID, id)
+		if errors.Is(err, repository.ErrProjectNotFound) {
+			return "", "Project not found"
+		}
+	}
+	if err != nil {
+		slog.Error("Failed to authorize live subscription", slog.String("topic", value), slog.Any("error", err))
+		return "", "Internal server error"
+	}
+
+	return liveTopic(kind, id), ""
+}
+
+func liveTopic(kind string, id uint) stream.Topic {
+	if kind == "project" {
+		return stream.ProjectTopic(id)
+	}
+	return stream.TaskTopic(id)
+}
+
+func (h *LiveHandler) send(conn *websocket.Conn, msg types.LiveServerMessage) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(h.policy.WriteTimeout))
+	return websocket.JSON.Send(conn, msg)
+}
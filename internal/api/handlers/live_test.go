@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/auth"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+	"to-do-list/internal/stream"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+type liveTestServer struct {
+	url      string
+	rooms    *stream.Rooms
+	tm       *auth.TokenManager
+	tasks    *MockTaskRepository
+	projects *MockProjectRepository
+}
+
+func newLiveTestServer(t *testing.T, rooms *stream.Rooms) *liveTestServer {
+	t.Helper()
+
+	s := &liveTestServer{
+		rooms:    rooms,
+		tm:       auth.NewTokenManager("secret", time.Hour),
+		tasks:    new(MockTaskRepository),
+		projects: new(MockProjectRepository),
+	}
+	handler := NewLiveHandler(rooms, s.tm, nil, s.tasks, s.projects, LivePolicy{
+		PingInterval:   time.Hour,
+		PongTimeout:    time.Minute,
+		AuthTimeout:    time.Second,
+		WriteTimeout:   time.Second,
+		MaxMessageSize: 4096,
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(handler.Serve))
+	t.Cleanup(server.Close)
+	s.url = "ws" + strings.TrimPrefix(server.URL, "http")
+	return s
+}
+
+func (s *liveTestServer) token(t *testing.T, userID uint) string {
+	t.Helper()
+	token, err := s.tm.GenerateToken(models.User{ID: userID})
+	require.NoError(t, err)
+	return token
+}
+
+// dial подключается с токеном в заголовке; пустой token - без заголовка
+func (s *liveTestServer) dial(t *testing.T, token string) *websocket.Conn {
+	t.Helper()
+
+	config, err := websocket.NewConfig(s.url, "http://localhost")
+	require.NoError(t, err)
+	if token != "" {
+		config.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	conn, err := websocket.DialConfig(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func liveSend(t *testing.T, conn *websocket.Conn, msg types.LiveClientMessage) {
+	t.Helper()
+	require.NoError(t, websocket.JSON.Send(conn, msg))
+}
+
+func liveReceive(t *testing.T, conn *websocket.Conn) types.LiveServerMessage {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	var msg types.LiveServerMessage
+	require.NoError(t, websocket.JSON.Receive(conn, &msg))
+	return msg
+}
+
+func testLiveRooms() *stream.Rooms {
+	return stream.NewRooms(stream.RoomsConfig{MaxConnsPerUser: 2, MaxTopics: 10, Buffer: 8})
+}
+
+func TestLiveHandler_SubscribeAndReceiveEvents(t *testing.T) {
+	s := newLiveTestServer(t, testLiveRooms())
+	s.tasks.On("GetTaskByID", mock.Anything, uint(5)).Return(models.Task{ID: 5, UserID: 10}, nil).Once()
+
+	conn := s.dial(t, s.token(t, 10))
+	ready := liveReceive(t, conn)
+	assert.Equal(t, types.LiveReady, ready.Type)
+	assert.Equal(t, uint(10), ready.UserID)
+
+	liveSend(t, conn, types.LiveClientMessage{Type: types.LiveSubscribe, Topic: "task:5"})
+	assert.Equal(t, types.LiveServerMessage{Type: types.LiveSubscribed, Topic: "task:5"}, liveReceive(t, conn))
+
+	event := models.NewTaskEvent(models.EventTaskUpdated, &models.Task{ID: 5, UserID: 10}, &models.Task{ID: 5, UserID: 10, Name: "Renamed"})
+	event.ID = 77
+	s.rooms.Publish(event)
+
+	msg := liveReceive(t, conn)
+	assert.Equal(t, types.LiveEvent, msg.Type)
+	assert.Equal(t, "task:5", msg.Topic)
+	require.NotNil(t, msg.Event)
+	assert.Equal(t, uint(77), msg.Event.ID)
+	assert.Equal(t, "Renamed", msg.Event.Task.Name)
+
+	liveSend(t, conn, types.LiveClientMessage{Type: types.LivePing})
+	assert.Equal(t, types.LivePong, liveReceive(t, conn).Type)
+
+	liveSend(t, conn, types.LiveClientMessage{Type: types.LiveUnsubscribe, Topic: "task:5"})
+	assert.Equal(t, types.LiveUnsubscribed, liveReceive(t, conn).Type)
+	s.tasks.AssertExpectations(t)
+}
+
+func TestLiveHandler_StopsDeliveringAfterAccessRevoked(t *testing.T) {
+	s := newLiveTestServer(t, testLiveRooms())
+	shared := models.Task{ID: 5, UserID: 20}
+	s.tasks.On("GetTaskByID", mock.Anything, uint(5)).Return(shared, nil).Once()
+	// Доступ проверяется при подписке и перед первым событием, потом его отзывают
+	s.tasks.On("GetTaskSharePermission", mock.Anything, uint(5), uint(10)).Return(models.ShareViewer, nil).Twice()
+	s.tasks.On("GetTaskSharePermission", mock.Anything, uint(5), uint(10)).Return(models.SharePermission(""), repository.ErrShareNotFound).Once()
+
+	conn := s.dial(t, s.token(t, 10))
+	liveReceive(t, conn)
+
+	liveSend(t, conn, types.LiveClientMessage{Type: types.LiveSubscribe, Topic: "task:5"})
+	assert.Equal(t, types.LiveSubscribed, liveReceive(t, conn).Type)
+
+	renamed := shared
+	renamed.Name = "Renamed"
+	first := models.NewTaskEvent(models.EventTaskUpdated, &shared, &renamed)
+	first.ID = 1
+	s.rooms.Publish(first)
+	assert.Equal(t, types.LiveEvent, liveReceive(t, conn).Type)
+
+	second := models.NewTaskEvent(models.EventTaskUpdated, &renamed, &shared)
+	second.ID = 2
+	s.rooms.Publish(second)
+	assert.Equal(t, types.LiveServerMessage{Type: types.LiveError, Topic: "task:5", Error: "Access to the topic was revoked"}, liveReceive(t, conn))
+
+	// Соединение отписано от задачи, следующие события до него не доходят
+	s.rooms.Publish(first)
+	liveSend(t, conn, types.LiveClientMessage{Type: types.LivePing})
+	assert.Equal(t, types.LivePong, liveReceive(t, conn).Type)
+	s.tasks.AssertExpectations(t)
+}
+
+func TestLiveHandler_AuthenticatesWithFirstMessage(t *testing.T) {
+	s := newLiveTestServer(t, testLiveRooms())
+
+	conn := s.dial(t, "")
+	liveSend(t, conn, types.LiveClientMessage{Type: types.LiveAuth, Token: s.token(t, 10)})
+	assert.Equal(t, types.LiveReady, liveReceive(t, conn).Type)
+
+	rejected := s.dial(t, "")
+	liveSend(t, rejected, types.LiveClientMessage{Type: types.LiveSubscribe, Topic: "task:5"})
+	assert.Equal(t, types.LiveServerMessage{Type: types.LiveError, Error: "Authentication required"}, liveReceive(t, rejected))
+
+	invalid := s.dial(t, "")
+	liveSend(t, invalid, types.LiveClientMessage{Type: types.LiveAuth, Token: "garbage"})
+	assert.Equal(t, types.LiveServerMessage{Type: types.LiveError, Error: "Invalid token"}, liveReceive(t, invalid))
+}
+
+func TestLiveHandler_RejectsForeignTopics(t *testing.T) {
+	s := newLiveTestServer(t, testLiveRooms())
+	s.projects.On("GetProjectByID", mock.Anything, uint(3)).Return(models.Project{ID: 3, UserID: 99}, nil).Once()
+	s.tasks.On("GetTaskByID", mock.Anything, uint(8)).Return(models.Task{}, repository.ErrTaskNotFound).Once()
+
+	conn := s.dial(t, s.token(t, 10))
+	liveReceive(t, conn)
+
+	liveSend(t, conn, types.LiveClientMessage{Type: types.LiveSubscribe, Topic: "project:3"})
+	assert.Equal(t, types.LiveServerMessage{Type: types.LiveError, Topic: "project:3", Error: "Project not found"}, liveReceive(t, conn))
+
+	liveSend(t, conn, types.LiveClientMessage{Type: types.LiveSubscribe, Topic: "task:8"})
+	assert.Equal(t, "Task not found", liveReceive(t, conn).Error)
+
+	liveSend(t, conn, types.LiveClientMessage{Type: types.LiveSubscribe, Topic: "board:1"})
+	assert.Equal(t, "Invalid topic", liveReceive(t, conn).Error)
+
+	require.NoError(t, websocket.Message.Send(conn, "not json"))
+	assert.Equal(t, "Invalid message", liveReceive(t, conn).Error)
+}
+
+func TestLiveHandler_ConnectionLimit(t *testing.T) {
+	s := newLiveTestServer(t, stream.NewRooms(stream.RoomsConfig{MaxConnsPerUser: 1, MaxTopics: 10, Buffer: 8}))
+	token := s.token(t, 10)
+
+	first := s.dial(t, token)
+	assert.Equal(t, types.LiveReady, liveReceive(t, first).Type)
+
+	second := s.dial(t, token)
+	assert.Equal(t, types.LiveServerMessage{Type: types.LiveError, Error: "Too many connections"}, liveReceive(t, second))
+}
+
+func TestLiveHandler_ClosesOnShutdown(t *testing.T) {
+	s := newLiveTestServer(t, testLiveRooms())
+
+	conn := s.dial(t, s.token(t, 10))
+	liveReceive(t, conn)
+
+	s.rooms.Close()
+
+	assert.Equal(t, types.LiveServerMessage{Type: types.LiveError, Error: "Server is shutting down"}, liveReceive(t, conn))
+
+	var msg types.LiveServerMessage
+	assert.Error(t, websocket.JSON.Receive(conn, &msg))
+}
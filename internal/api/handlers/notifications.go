@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// NotificationHandler - входящие уведомления пользователя в приложении
+type NotificationHandler struct {
+	repo repository.NotificationRepository
+}
+
+func NewNotificationHandler(repo repository.NotificationRepository) *NotificationHandler {
+	return &NotificationHandler{repo: repo}
+}
+
+// GetNotifications отдаёт уведомления новыми первыми вместе со счётчиком непрочитанных. ?unread=true - только непрочитанные
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	unreadOnly := false
+	if unreadStr := r.URL.Query().Get("unread"); unreadStr != "" {
+		unreadOnly, err = strconv.ParseBool(unreadStr)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, map[string]string{"error": "Invalid unread value"})
+			return
+		}
+	}
+
+	limit, offset := parsePagination(r)
+
+	notifications, err := h.repo.GetNotifications(r.Context(), userID, unreadOnly, limit, offset)
+	if err != nil {
+		slog.Error("Failed to get notifications", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get notifications"})
+
+		return
+	}
+
+	unread, err := h.repo.CountUnreadNotifications(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to count unread notifications", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get notifications"})
+
+		return
+	}
+
+	resp := types.NotificationListResponse{
+		Notifications: make([]types.NotificationResponse, 0, len(notifications)),
+		Unread:        unread,
+	}
+	for _, n := range notifications {
+		resp.Notifications = append(resp.Notifications, toNotificationResponse(n))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (h *NotificationHandler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	notificationID, err := strconv.ParseUint(chi.URLParam(r, "notificationID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid notification ID"})
+		return
+	}
+
+	n, err := h.repo.MarkNotificationRead(r.Context(), userID, uint(notificationID))
+	if errors.Is(err, repository.ErrNotificationNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Notification not found"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to mark notification read", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to mark notification read"})
+
+		return
+	}
+
+	render.JSON(w, r, toNotificationResponse(n))
+}
+
+func (h *NotificationHandler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	marked, err := h.repo.MarkAllNotificationsRead(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to mark notifications read", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to mark notifications read"})
+
+		return
+	}
+
+	render.JSON(w, r, types.MarkAllReadResponse{Marked: marked})
+}
+
+func toNotificationResponse(n models.Notification) types.NotificationResponse {
+	return types.NotificationResponse{
+		ID:        n.ID,
+		Kind:      n.Kind,
+		TaskID:    n.TaskID,
+		Title:     n.Title,
+		Body:      n.Body,
+		Read:      n.ReadAt != nil,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) CreateNotification(ctx context.Context, n *models.Notification) error {
+	args := m.Called(ctx, n)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetNotifications(ctx context.Context, userID uint, unreadOnly bool, limit, offset int) ([]models.Notification, error) {
+	args := m.Called(ctx, userID, unreadOnly, limit, offset)
+	return args.Get(0).([]models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) CountUnreadNotifications(ctx context.Context, userID uint) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkNotificationRead(ctx context.Context, userID, id uint) (models.Notification, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Get(0).(models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkAllNotificationsRead(ctx context.Context, userID uint) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func withNotificationID(req *http.Request, notificationID string, userID uint) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("notificationID", notificationID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(withUserID(ctx, userID))
+}
+
+func TestNotificationHandler_GetNotifications(t *testing.T) {
+	mockRepo := new(MockNotificationRepository)
+	handler := NewNotificationHandler(mockRepo)
+
+	readAt := time.Now()
+	mockRepo.On("GetNotifications", mock.Anything, uint(10), true, 20, 40).Return([]models.Notification{
+		{ID: 7, UserID: 10, Kind: models.NotificationTaskReminder, Title: "Deadline is approaching"},
+		{ID: 6, UserID: 10, Kind: models.NotificationTaskShared, ReadAt: &readAt},
+	}, nil).Once()
+	mockRepo.On("CountUnreadNotifications", mock.Anything, uint(10)).Return(3, nil).Once()
+
+	req := httptest.NewRequest("GET", "/users/me/notifications?unread=true&limit=20&offset=40", nil)
+	rr := httptest.NewRecorder()
+	handler.GetNotifications(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp types.NotificationListResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.Unread)
+	require.Len(t, resp.Notifications, 2)
+	assert.False(t, resp.Notifications[0].Read)
+	assert.True(t, resp.Notifications[1].Read)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNotificationHandler_GetNotifications_InvalidUnread(t *testing.T) {
+	mockRepo := new(MockNotificationRepository)
+	handler := NewNotificationHandler(mockRepo)
+
+	req := httptest.NewRequest("GET", "/users/me/notifications?unread=maybe", nil)
+	rr := httptest.NewRecorder()
+	handler.GetNotifications(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockRepo.AssertNotCalled(t, "GetNotifications", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotificationHandler_MarkNotificationRead(t *testing.T) {
+	testCases := []struct {
+		name         string
+		id           string
+		repoErr      error
+		expectedCode int
+	}{
+		{"Success", "7", nil, http.StatusOK},
+		{"Someone Else's", "8", repository.ErrNotificationNotFound, http.StatusNotFound},
+		{"Invalid ID", "abc", nil, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockNotificationRepository)
+			handler := NewNotificationHandler(mockRepo)
+
+			readAt := time.Now()
+			mockRepo.On("MarkNotificationRead", mock.Anything, uint(10), mock.Anything).
+				Return(models.Notification{ID: 7, UserID: 10, ReadAt: &readAt}, tc.repoErr).Maybe()
+
+			rr := httptest.NewRecorder()
+			handler.MarkNotificationRead(rr, withNotificationID(httptest.NewRequest("POST", "/users/me/notifications/"+tc.id+"/read", nil), tc.id, 10))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusOK {
+				var resp types.NotificationResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.True(t, resp.Read)
+			}
+		})
+	}
+}
+
+func TestNotificationHandler_MarkAllNotificationsRead(t *testing.T) {
+	mockRepo := new(MockNotificationRepository)
+	handler := NewNotificationHandler(mockRepo)
+
+	mockRepo.On("MarkAllNotificationsRead", mock.Anything, uint(10)).Return(int64(4), nil).Once()
+
+	req := httptest.NewRequest("POST", "/users/me/notifications/read-all", nil)
+	rr := httptest.NewRecorder()
+	handler.MarkAllNotificationsRead(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"marked":4}`, rr.Body.String())
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ProjectHandler: удаление проекта меняет его задачи, поэтому, как и TaskHandler, пишет события о них в outbox
+type ProjectHandler struct {
+	repo   repository.ProjectRepository
+	tasks  repository.TaskRepository
+	tx     repository.Transactor
+	events EventStore
+}
+
+func NewProjectHandler(repo repository.ProjectRepository, tasks repository.TaskRepository, transactor repository.Transactor, events EventStore) *ProjectHandler {
+	return &ProjectHandler{repo: repo, tasks: tasks, tx: transactor, events: events}
+}
+
+// getOwnedProject возвращает ErrProjectNotFound и для чужих проектов, чтобы не раскрывать их существование
+func getOwnedProject(ctx context.Context, repo repository.ProjectRepository, userID, projectID uint) (models.Project, error) {
+	project, err := repo.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return models.Project{}, err
+	}
+	if project.UserID != userID {
+		return models.Project{}, repository.ErrProjectNotFound
+	}
+	return project, nil
+}
+
+func (h *ProjectHandler) GetProjects(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	includeArchived := r.URL.Query().Get("archived") == "true"
+
+	projects, err := h.repo.GetProjectsByUserID(r.Context(), userID, includeArchived)
+	if err != nil {
+		slog.Error("Failed to get user projects", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get projects"})
+
+		return
+	}
+
+	resp := make([]types.ProjectResponse, 0, len(projects))
+	for _, project := range projects {
+		resp = append(resp, toProjectResponse(project))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	var req types.CreateProjectRequest
+	err = render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+
+	project := &models.Project{UserID: userID, Name: req.Name, Color: req.Color}
+	err = h.repo.CreateProject(r.Context(), project)
+	if err != nil {
+		slog.Error("Failed to create project", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to create project"})
+
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, toProjectResponse(*project))
+}
+
+func (h *ProjectHandler) GetProject(w http.ResponseWriter, r *http.Request) {
+	project, ok := h.loadProject(w, r)
+	if !ok {
+		return
+	}
+
+	render.JSON(w, r, toProjectResponse(project))
+}
+
+func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	project, ok := h.loadProject(w, r)
+	if !ok {
+		return
+	}
+
+	var req types.UpdateProjectRequest
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode update request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Update validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+
+	if req.Name != nil {
+		project.Name = *req.Name
+	}
+	if req.Color != nil {
+		project.Color = *req.Color
+	}
+	if req.Archived != nil {
+		project.Archived = *req.Archived
+	}
+
+	err = h.repo.UpdateProject(r.Context(), &project)
+	if err != nil {
+		slog.Error("Failed to update project", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to update project"})
+
+		return
+	}
+
+	render.JSON(w, r, toProjectResponse(project))
+}
+
+// DeleteProject: по умолчанию задачи проекта остаются без проекта, ?tasks=delete удаляет их вместе с ним
+func (h *ProjectHandler) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	mode := r.URL.Query().Get("tasks")
+	if mode != "" && mode != "keep" && mode != "delete" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid tasks mode, expected keep or delete"})
+		return
+	}
+
+	project, ok := h.loadProject(w, r)
+	if !ok {
+		return
+	}
+
+	// Задачи блокируются до удаления: события описывают ровно те задачи, которые ушли в корзину
+	// или остались без проекта через ON DELETE SET NULL
+	err := h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		tasks, err := h.tasks.LockProjectTasks(ctx, project.ID)
+		if err != nil {
+			return err
+		}
+
+		err = h.repo.DeleteProject(ctx, project.ID, mode == "delete")
+		if err != nil {
+			return err
+		}
+
+		var events []models.DomainEvent
+		for i, before := range tasks {
+			if mode == "delete" {
+				events = append(events, models.NewTaskEvent(models.EventTaskDeleted, &tasks[i], nil))
+				continue
+			}
+			after := before
+			after.ProjectID = nil
+			events = append(events, models.TaskUpdateEvents(before, after)...)
+		}
+		return h.events.AppendEvents(ctx, stampEvents(r, project.UserID, events...)...)
+	})
+	if err != nil && !errors.Is(err, repository.ErrProjectNotFound) {
+		slog.Error("Failed to delete project", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to delete project"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProjectHandler) GetProjectTasks(w http.ResponseWriter, r *http.Request) {
+	project, ok := h.loadProject(w, r)
+	if !ok {
+		return
+	}
+
+	filter, err := parseTaskFilter(r)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
+	}
+	filter.ProjectID = &project.ID
+
+	tasks, err := h.tasks.GetTasksByUserID(r.Context(), project.UserID, filter)
+	if err != nil {
+		slog.Error("Failed to get project tasks", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get tasks"})
+
+		return
+	}
+
+	render.JSON(w, r, types.NewTaskResponses(tasks))
+}
+
+// loadProject достаёт проект текущего пользователя из URL, при ошибке сам пишет ответ
+func (h *ProjectHandler) loadProject(w http.ResponseWriter, r *http.Request) (models.Project, bool) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.Project{}, false
+	}
+
+	projectID, err := strconv.ParseUint(chi.URLParam(r, "projectID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid project ID"})
+		return models.Project{}, false
+	}
+
+	project, err := getOwnedProject(r.Context(), h.repo, userID, uint(projectID))
+	if errors.Is(err, repository.ErrProjectNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Project not found"})
+		return models.Project{}, false
+	}
+	if err != nil {
+		slog.Error("Failed to get project by ID", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.Project{}, false
+	}
+
+	return project, true
+}
+
+func toProjectResponse(project models.Project) types.ProjectResponse {
+	return types.ProjectResponse{
+		ID:        project.ID,
+		Name:      project.Name,
+		Color:     project.Color,
+		Archived:  project.Archived,
+		CreatedAt: project.CreatedAt,
+		UpdatedAt: project.UpdatedAt,
+	}
+}
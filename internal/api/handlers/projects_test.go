@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockProjectRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectRepository) CreateProject(ctx context.Context, project *models.Project) error {
+	args := m.Called(ctx, project)
+	project.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) GetProjectByID(ctx context.Context, id uint) (models.Project, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetProjectsByUserID(ctx context.Context, userID uint, includeArchived bool) ([]models.Project, error) {
+	args := m.Called(ctx, userID, includeArchived)
+	return args.Get(0).([]models.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) UpdateProject(ctx context.Context, project *models.Project) error {
+	args := m.Called(ctx, project)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) DeleteProject(ctx context.Context, id uint, deleteTasks bool) error {
+	args := m.Called(ctx, id, deleteTasks)
+	return args.Error(0)
+}
+
+func withProjectID(req *http.Request, projectID string, userID uint) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("projectID", projectID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(withUserID(ctx, userID))
+}
+
+func TestProjectHandler_CreateProject(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	handler := NewProjectHandler(mockRepo, new(MockTaskRepository), fakeTransactor{}, &fakeEventStore{})
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("CreateProject", mock.Anything, &models.Project{UserID: 10, Name: "Home", Color: "#ff0000"}).Return(nil).Once()
+
+		body, _ := json.Marshal(types.CreateProjectRequest{Name: "Home", Color: "#ff0000"})
+		req := httptest.NewRequest("POST", "/projects", bytes.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.CreateProject(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		var resp types.ProjectResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, "Home", resp.Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Color", func(t *testing.T) {
+		body, _ := json.Marshal(types.CreateProjectRequest{Name: "Home", Color: "red"})
+		req := httptest.NewRequest("POST", "/projects", bytes.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.CreateProject(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestProjectHandler_DeleteProject(t *testing.T) {
+	projectID := uint(2)
+	projectTasks := []models.Task{{ID: 5, UserID: 10, ProjectID: &projectID}, {ID: 6, UserID: 10, ProjectID: &projectID}}
+
+	t.Run("Delete With Tasks", func(t *testing.T) {
+		mockRepo := new(MockProjectRepository)
+		mockTasks := new(MockTaskRepository)
+		eventStore := &fakeEventStore{}
+		handler := NewProjectHandler(mockRepo, mockTasks, fakeTransactor{}, eventStore)
+
+		mockRepo.On("GetProjectByID", mock.Anything, uint(2)).Return(models.Project{ID: 2, UserID: 10}, nil).Once()
+		mockTasks.On("LockProjectTasks", mock.Anything, uint(2)).Return(projectTasks, nil).Once()
+		mockRepo.On("DeleteProject", mock.Anything, uint(2), true).Return(nil).Once()
+
+		req := httptest.NewRequest("DELETE", "/projects/2?tasks=delete", nil)
+		rr := httptest.NewRecorder()
+		handler.DeleteProject(rr, withProjectID(req, "2", 10))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, []models.EventType{models.EventTaskDeleted, models.EventTaskDeleted}, eventStore.eventTypes())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Keep Tasks", func(t *testing.T) {
+		mockRepo := new(MockProjectRepository)
+		mockTasks := new(MockTaskRepository)
+		eventStore := &fakeEventStore{}
+		handler := NewProjectHandler(mockRepo, mockTasks, fakeTransactor{}, eventStore)
+
+		mockRepo.On("GetProjectByID", mock.Anything, uint(2)).Return(models.Project{ID: 2, UserID: 10}, nil).Once()
+		mockTasks.On("LockProjectTasks", mock.Anything, uint(2)).Return(projectTasks, nil).Once()
+		mockRepo.On("DeleteProject", mock.Anything, uint(2), false).Return(nil).Once()
+
+		req := httptest.NewRequest("DELETE", "/projects/2", nil)
+		rr := httptest.NewRecorder()
+		handler.DeleteProject(rr, withProjectID(req, "2", 10))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		require.Equal(t, []models.EventType{models.EventTaskUpdated, models.EventTaskUpdated}, eventStore.eventTypes())
+		payload, err := eventStore.events[0].TaskPayload()
+		require.NoError(t, err)
+		assert.Equal(t, &projectID, payload.Before.ProjectID)
+		assert.Nil(t, payload.After.ProjectID)
+	})
+
+	t.Run("Another User Project", func(t *testing.T) {
+		mockRepo := new(MockProjectRepository)
+		handler := NewProjectHandler(mockRepo, new(MockTaskRepository), fakeTransactor{}, &fakeEventStore{})
+
+		mockRepo.On("GetProjectByID", mock.Anything, uint(2)).Return(models.Project{ID: 2, UserID: 99}, nil).Once()
+
+		req := httptest.NewRequest("DELETE", "/projects/2", nil)
+		rr := httptest.NewRecorder()
+		handler.DeleteProject(rr, withProjectID(req, "2", 10))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockRepo.AssertNotCalled(t, "DeleteProject")
+	})
+}
+
+func TestProjectHandler_GetProjectTasks(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	mockTasks := new(MockTaskRepository)
+	handler := NewProjectHandler(mockRepo, mockTasks, fakeTransactor{}, &fakeEventStore{})
+
+	mockRepo.On("GetProjectByID", mock.Anything, uint(2)).Return(models.Project{ID: 2, UserID: 10}, nil).Once()
+	mockTasks.On("GetTasksByUserID", mock.Anything, uint(10), mock.MatchedBy(func(f repository.TaskFilter) bool {
+		return f.ProjectID != nil && *f.ProjectID == 2
+	})).Return([]models.Task{{ID: 5, UserID: 10}}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/projects/2/tasks", nil)
+	rr := httptest.NewRecorder()
+	handler.GetProjectTasks(rr, withProjectID(req, "2", 10))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockTasks.AssertExpectations(t)
+}
+
+func TestTaskHandler_MoveTaskBetweenProjects(t *testing.T) {
+	newRequest := func(projectID uint) *http.Request {
+		body, _ := json.Marshal(types.UpdateTaskRequest{ProjectID: &projectID})
+		req := httptest.NewRequest("PATCH", "/tasks/1", bytes.NewReader(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("taskID", "1")
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		return req.WithContext(withUserID(ctx, 10))
+	}
+	task := models.Task{ID: 1, UserID: 10, Status: models.StatusPending, Deadline: time.Now().Add(time.Hour)}
+
+	t.Run("Move To Project", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockProjects := new(MockProjectRepository)
+		handler := NewTaskHandler(mockRepo, mockProjects, new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+		mockProjects.On("GetProjectByID", mock.Anything, uint(3)).Return(models.Project{ID: 3, UserID: 10}, nil).Once()
+		mockRepo.On("UpdateTaskProject", mock.Anything, uint(1), mock.MatchedBy(func(id *uint) bool { return id != nil && *id == 3 })).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest(3))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Remove From Project", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+		mockRepo.On("UpdateTaskProject", mock.Anything, uint(1), (*uint)(nil)).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest(0))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Archived Project", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockProjects := new(MockProjectRepository)
+		handler := NewTaskHandler(mockRepo, mockProjects, new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+		mockProjects.On("GetProjectByID", mock.Anything, uint(3)).Return(models.Project{ID: 3, UserID: 10, Archived: true}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest(3))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		mockRepo.AssertNotCalled(t, "UpdateTaskProject")
+	})
+}
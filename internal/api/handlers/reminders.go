@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/render"
+)
+
+// ReminderHandler настраивает напоминания о дедлайнах: свои у задачи и по умолчанию у пользователя
+type ReminderHandler struct {
+	repo  repository.ReminderRepository
+	tasks repository.TaskRepository
+}
+
+func NewReminderHandler(repo repository.ReminderRepository, tasks repository.TaskRepository) *ReminderHandler {
+	return &ReminderHandler{repo: repo, tasks: tasks}
+}
+
+func (h *ReminderHandler) GetTaskReminders(w http.ResponseWriter, r *http.Request) {
+	task, ok := loadAuthorizedTask(w, r, h.tasks, models.AccessView)
+	if !ok {
+		return
+	}
+
+	offsets, err := h.repo.GetTaskReminders(r.Context(), task.ID)
+	if err != nil {
+		slog.Error("Failed to get task reminders", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get reminders"})
+
+		return
+	}
+
+	render.JSON(w, r, types.TaskRemindersResponse{Before: models.FormatReminderOffsets(offsets)})
+}
+
+// SetTaskReminders заменяет напоминания задачи целиком. Менять их могут владелец и редакторы
+func (h *ReminderHandler) SetTaskReminders(w http.ResponseWriter, r *http.Request) {
+	task, ok := loadAuthorizedTask(w, r, h.tasks, models.AccessEdit)
+	if !ok {
+		return
+	}
+
+	var req types.TaskRemindersRequest
+	if !decodeReminderRequest(w, r, &req) {
+		return
+	}
+
+	offsets, ok := parseReminderOffsets(w, r, req.Before)
+	if !ok {
+		return
+	}
+
+	err := h.repo.SetTaskReminders(r.Context(), task.ID, offsets)
+	if err != nil {
+		slog.Error("Failed to set task reminders", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to set reminders"})
+
+		return
+	}
+
+	render.JSON(w, r, types.TaskRemindersResponse{Before: models.FormatReminderOffsets(offsets)})
+}
+
+func (h *ReminderHandler) GetReminderPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	prefs, err := h.repo.GetReminderPreferences(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get reminder preferences", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get reminder preferences"})
+
+		return
+	}
+
+	render.JSON(w, r, toReminderPreferencesResponse(prefs))
+}
+
+func (h *ReminderHandler) UpdateReminderPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	var req types.ReminderPreferencesRequest
+	if !decodeReminderRequest(w, r, &req) {
+		return
+	}
+
+	offsets, ok := parseReminderOffsets(w, r, req.Before)
+	if !ok {
+		return
+	}
+
+	channels := slices.Clone(req.Channels)
+	slices.Sort(channels)
+
+	prefs := &models.ReminderPreferences{UserID: userID, Offsets: offsets, Channels: slices.Compact(channels)}
+	err = h.repo.SetReminderPreferences(r.Context(), prefs)
+	if err != nil {
+		slog.Error("Failed to set reminder preferences", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to set reminder preferences"})
+
+		return
+	}
+
+	render.JSON(w, r, toReminderPreferencesResponse(*prefs))
+}
+
+// decodeReminderRequest читает и валидирует тело запроса, при ошибке сам пишет ответ
+func decodeReminderRequest(w http.ResponseWriter, r *http.Request, req any) bool {
+	err := render.DecodeJSON(r.Body, req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return false
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return false
+	}
+
+	return true
+}
+
+// parseReminderOffsets разбирает сроки из запроса, при ошибке сам пишет ответ
+func parseReminderOffsets(w http.ResponseWriter, r *http.Request, values []string) ([]models.ReminderOffset, bool) {
+	offsets, err := models.ParseReminderOffsets(values)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return nil, false
+	}
+	return offsets, true
+}
+
+func toReminderPreferencesResponse(prefs models.ReminderPreferences) types.ReminderPreferencesResponse {
+	resp := types.ReminderPreferencesResponse{
+		Before:   models.FormatReminderOffsets(prefs.Offsets),
+		Channels: prefs.Channels,
+	}
+	if resp.Channels == nil {
+		resp.Channels = []models.NotificationChannelName{}
+	}
+	if !prefs.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &prefs.UpdatedAt
+	}
+	return resp
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReminderRepository struct {
+	mock.Mock
+}
+
+func (m *MockReminderRepository) GetTaskReminders(ctx context.Context, taskID uint) ([]models.ReminderOffset, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]models.ReminderOffset), args.Error(1)
+}
+
+func (m *MockReminderRepository) SetTaskReminders(ctx context.Context, taskID uint, offsets []models.ReminderOffset) error {
+	args := m.Called(ctx, taskID, offsets)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) GetReminderPreferences(ctx context.Context, userID uint) (models.ReminderPreferences, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(models.ReminderPreferences), args.Error(1)
+}
+
+func (m *MockReminderRepository) SetReminderPreferences(ctx context.Context, prefs *models.ReminderPreferences) error {
+	args := m.Called(ctx, prefs)
+	prefs.UpdatedAt = time.Now()
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) ClaimDueReminders(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]models.DueReminder), args.Error(1)
+}
+
+func TestReminderHandler_SetTaskReminders(t *testing.T) {
+	testCases := []struct {
+		name         string
+		requester    uint
+		before       []string
+		expectedCode int
+		expected     []string
+	}{
+		{"Owner Sets Reminders", 10, []string{"1h", "1d", "60m"}, http.StatusOK, []string{"1d", "1h"}},
+		{"Clear Reminders", 10, []string{}, http.StatusOK, []string{}},
+		{"Invalid Offset", 10, []string{"1w"}, http.StatusBadRequest, nil},
+		{"Too Many", 10, []string{"1m", "2m", "3m", "4m", "5m", "6m"}, http.StatusBadRequest, nil},
+		{"Viewer Can't Set", 40, []string{"1h"}, http.StatusForbidden, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockReminderRepository)
+			mockTasks := new(MockTaskRepository)
+			handler := NewReminderHandler(mockRepo, mockTasks)
+
+			mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+			mockTasks.On("GetTaskSharePermission", mock.Anything, uint(1), uint(40)).Return(models.ShareViewer, nil).Maybe()
+			mockRepo.On("SetTaskReminders", mock.Anything, uint(1), mock.Anything).Return(nil).Maybe()
+
+			body, _ := json.Marshal(types.TaskRemindersRequest{Before: tc.before})
+			rr := httptest.NewRecorder()
+			handler.SetTaskReminders(rr, withTaskID(httptest.NewRequest("PUT", "/tasks/1/reminders", bytes.NewReader(body)), "1", tc.requester))
+
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode != http.StatusOK {
+				mockRepo.AssertNotCalled(t, "SetTaskReminders", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			var resp types.TaskRemindersResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tc.expected, resp.Before)
+		})
+	}
+}
+
+func TestReminderHandler_GetTaskReminders_NotFound(t *testing.T) {
+	mockTasks := new(MockTaskRepository)
+	handler := NewReminderHandler(new(MockReminderRepository), mockTasks)
+
+	mockTasks.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{}, repository.ErrTaskNotFound).Once()
+
+	rr := httptest.NewRecorder()
+	handler.GetTaskReminders(rr, withTaskID(httptest.NewRequest("GET", "/tasks/1/reminders", nil), "1", 10))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestReminderHandler_UpdateReminderPreferences(t *testing.T) {
+	newRequest := func(req types.ReminderPreferencesRequest) *http.Request {
+		body, _ := json.Marshal(req)
+		r := httptest.NewRequest("PUT", "/users/me/reminders", bytes.NewReader(body))
+		return r.WithContext(withUserID(r.Context(), 10))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockReminderRepository)
+		handler := NewReminderHandler(mockRepo, new(MockTaskRepository))
+
+		mockRepo.On("SetReminderPreferences", mock.Anything, mock.MatchedBy(func(p *models.ReminderPreferences) bool {
+			return p.UserID == 10 && len(p.Offsets) == 2 &&
+				assert.ObjectsAreEqual([]models.NotificationChannelName{models.ChannelEmail, models.ChannelInApp}, p.Channels)
+		})).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateReminderPreferences(rr, newRequest(types.ReminderPreferencesRequest{
+			Before:   []string{"1h", "1d"},
+			Channels: []models.NotificationChannelName{models.ChannelInApp, models.ChannelEmail, models.ChannelInApp},
+		}))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp types.ReminderPreferencesResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, []string{"1d", "1h"}, resp.Before)
+		assert.NotNil(t, resp.UpdatedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Channel", func(t *testing.T) {
+		mockRepo := new(MockReminderRepository)
+		handler := NewReminderHandler(mockRepo, new(MockTaskRepository))
+
+		rr := httptest.NewRecorder()
+		handler.UpdateReminderPreferences(rr, newRequest(types.ReminderPreferencesRequest{
+			Channels: []models.NotificationChannelName{"sms"},
+		}))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockRepo.AssertNotCalled(t, "SetReminderPreferences", mock.Anything, mock.Anything)
+	})
+}
+
+func TestReminderHandler_GetReminderPreferences_Defaults(t *testing.T) {
+	mockRepo := new(MockReminderRepository)
+	handler := NewReminderHandler(mockRepo, new(MockTaskRepository))
+
+	mockRepo.On("GetReminderPreferences", mock.Anything, uint(10)).Return(models.ReminderPreferences{UserID: 10}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/users/me/reminders", nil)
+	rr := httptest.NewRecorder()
+	handler.GetReminderPreferences(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"before":[],"channels":[]}`, rr.Body.String())
+}
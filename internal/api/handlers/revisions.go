@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+func (h *TaskHandler) GetTaskHistory(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.loadTask(w, r, models.AccessView)
+	if !ok {
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	revisions, err := h.repo.GetTaskRevisions(r.Context(), task.ID, limit, offset)
+	if err != nil {
+		slog.Error("Failed to get task revisions", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get task history"})
+
+		return
+	}
+
+	resp := make([]types.RevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		resp = append(resp, toRevisionResponse(revision))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// RevertTask возвращает содержимое задачи к состоянию после указанной ревизии.
+// Откат сам записывается новой ревизией, поэтому его тоже можно отменить
+func (h *TaskHandler) RevertTask(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	task, ok := h.loadTask(w, r, models.AccessEdit)
+	if !ok {
+		return
+	}
+
+	revisionID, err := strconv.ParseUint(chi.URLParam(r, "revisionID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid revision ID"})
+		return
+	}
+
+	// Задача перечитывается с блокировкой, а ревизии - внутри той же транзакции: правка, пришедшая
+	// между чтением и записью, иначе не попала бы в отменяемые
+	var target models.Task
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		task, err := h.repo.LockTask(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+
+		newer, err := h.repo.GetTaskRevisionsAfter(ctx, task.ID, uint(revisionID))
+		if err != nil {
+			return err
+		}
+
+		target = models.UndoRevisions(task, newer)
+		if target.Name != task.Name {
+			err := h.repo.UpdateTaskName(ctx, task.ID, target.Name)
+			if err != nil {
+				return err
+			}
+		}
+		if target.Description != task.Description {
+			err := h.repo.UpdateTaskDescription(ctx, task.ID, target.Description)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = h.recordTaskUpdate(ctx, r, userID, task, target)
+		if err != nil {
+			return err
+		}
+
+		revertedTo := uint(revisionID)
+		return h.recordRevision(ctx, userID, task, target, &revertedTo)
+	})
+	if errors.Is(err, repository.ErrRevisionNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Revision not found"})
+		return
+	}
+	if errors.Is(err, repository.ErrTaskNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Task not found"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to revert task", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to update task"})
+
+		return
+	}
+
+	render.JSON(w, r, types.NewTaskResponse(target))
+}
+
+// recordRevision сохраняет изменения содержимого задачи в историю. Вызывается внутри WithinTx вместе
+// с самим изменением: правка без ревизии сломала бы последующие откаты
+func (h *TaskHandler) recordRevision(ctx context.Context, userID uint, before, after models.Task, revertedTo *uint) error {
+	changes := models.DiffTaskContent(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	revision := &models.TaskRevision{
+		TaskID:       before.ID,
+		UserID:       &userID,
+		Changes:      changes,
+		RevertedToID: revertedTo,
+	}
+
+	return h.repo.CreateTaskRevision(ctx, revision)
+}
+
+func toRevisionResponse(revision models.TaskRevision) types.RevisionResponse {
+	return types.RevisionResponse{
+		ID:         revision.ID,
+		UserID:     revision.UserID,
+		Changes:    revision.Changes,
+		RevertedTo: revision.RevertedToID,
+		CreatedAt:  revision.CreatedAt,
+	}
+}
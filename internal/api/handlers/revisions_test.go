@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func withRevisionID(req *http.Request, taskID, revisionID string, userID uint) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("taskID", taskID)
+	rctx.URLParams.Add("revisionID", revisionID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(withUserID(ctx, userID))
+}
+
+func TestTaskHandler_GetTaskHistory(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	actor := uint(10)
+	revisions := []models.TaskRevision{
+		{ID: 2, TaskID: 1, UserID: &actor, Changes: map[string]models.FieldChange{models.RevisionFieldName: {From: "B", To: "C"}}},
+		{ID: 1, TaskID: 1, UserID: &actor, Changes: map[string]models.FieldChange{models.RevisionFieldName: {From: "A", To: "B"}}},
+	}
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+	mockRepo.On("GetTaskRevisions", mock.Anything, uint(1), 10, 0).Return(revisions, nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.GetTaskHistory(rr, withTaskID(httptest.NewRequest("GET", "/tasks/1/history", nil), "1", 10))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp []types.RevisionResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp, 2)
+	assert.Equal(t, uint(2), resp[0].ID)
+	assert.Equal(t, "C", resp[0].Changes[models.RevisionFieldName].To)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskHandler_RevertTask(t *testing.T) {
+	actor := uint(10)
+	task := models.Task{ID: 1, UserID: 10, Name: "C", Description: "new"}
+	newer := []models.TaskRevision{
+		{ID: 3, TaskID: 1, UserID: &actor, Changes: map[string]models.FieldChange{models.RevisionFieldDescription: {From: "old", To: "new"}}},
+		{ID: 2, TaskID: 1, UserID: &actor, Changes: map[string]models.FieldChange{models.RevisionFieldName: {From: "B", To: "C"}}},
+	}
+
+	t.Run("Reverts Newer Changes", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+		mockRepo.On("LockTask", inFakeTx, uint(1)).Return(task, nil).Once()
+		mockRepo.On("GetTaskRevisionsAfter", inFakeTx, uint(1), uint(1)).Return(newer, nil).Once()
+		mockRepo.On("UpdateTaskName", mock.Anything, uint(1), "B").Return(nil).Once()
+		mockRepo.On("UpdateTaskDescription", mock.Anything, uint(1), "old").Return(nil).Once()
+		mockRepo.On("CreateTaskRevision", inFakeTx, mock.MatchedBy(func(rev *models.TaskRevision) bool {
+			return rev.RevertedToID != nil && *rev.RevertedToID == 1 && len(rev.Changes) == 2
+		})).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.RevertTask(rr, withRevisionID(httptest.NewRequest("POST", "/tasks/1/history/1/revert", nil), "1", "1", 10))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp types.TaskResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, "B", resp.Name)
+		assert.Equal(t, "old", resp.Description)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Latest Revision Is No-op", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+		mockRepo.On("LockTask", inFakeTx, uint(1)).Return(task, nil).Once()
+		mockRepo.On("GetTaskRevisionsAfter", inFakeTx, uint(1), uint(3)).Return([]models.TaskRevision{}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.RevertTask(rr, withRevisionID(httptest.NewRequest("POST", "/tasks/1/history/3/revert", nil), "1", "3", 10))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "CreateTaskRevision")
+	})
+
+	t.Run("Unknown Revision", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+		mockRepo.On("LockTask", inFakeTx, uint(1)).Return(task, nil).Once()
+		mockRepo.On("GetTaskRevisionsAfter", inFakeTx, uint(1), uint(99)).Return([]models.TaskRevision(nil), repository.ErrRevisionNotFound).Once()
+
+		rr := httptest.NewRecorder()
+		handler.RevertTask(rr, withRevisionID(httptest.NewRequest("POST", "/tasks/1/history/99/revert", nil), "1", "99", 10))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Revision Failure Fails Revert", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+		mockRepo.On("LockTask", inFakeTx, uint(1)).Return(task, nil).Once()
+		mockRepo.On("GetTaskRevisionsAfter", inFakeTx, uint(1), uint(1)).Return(newer, nil).Once()
+		mockRepo.On("UpdateTaskName", inFakeTx, uint(1), "B").Return(nil).Once()
+		mockRepo.On("UpdateTaskDescription", inFakeTx, uint(1), "old").Return(nil).Once()
+		mockRepo.On("CreateTaskRevision", inFakeTx, mock.Anything).Return(errors.New("connection reset")).Once()
+
+		rr := httptest.NewRecorder()
+		handler.RevertTask(rr, withRevisionID(httptest.NewRequest("POST", "/tasks/1/history/1/revert", nil), "1", "1", 10))
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// Правка без ревизии сломала бы откаты, поэтому ошибка записи ревизии откатывает и саму правку
+func TestTaskHandler_UpdateTask_RevisionFailureFailsUpdate(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10, Name: "Old"}, nil).Once()
+	mockRepo.On("UpdateTaskName", inFakeTx, uint(1), "New").Return(nil).Once()
+	mockRepo.On("CreateTaskRevision", inFakeTx, mock.Anything).Return(errors.New("connection reset")).Once()
+
+	name := "New"
+	body, _ := json.Marshal(types.UpdateTaskRequest{Name: &name})
+	rr := httptest.NewRecorder()
+	handler.UpdateTask(rr, withTaskID(httptest.NewRequest("PATCH", "/tasks/1", bytes.NewReader(body)), "1", 10))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/recurrence"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// SeriesHandler управляет сериями повторяющихся задач. Новое правило действует
+// на следующие экземпляры, уже созданные задачи не меняются
+type SeriesHandler struct {
+	repo repository.SeriesRepository
+}
+
+func NewSeriesHandler(repo repository.SeriesRepository) *SeriesHandler {
+	return &SeriesHandler{repo: repo}
+}
+
+func (h *SeriesHandler) GetSeries(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	series, err := h.repo.GetSeriesByUserID(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get user task series", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get series"})
+
+		return
+	}
+
+	resp := make([]types.SeriesResponse, 0, len(series))
+	for _, s := range series {
+		resp = append(resp, toSeriesResponse(s))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (h *SeriesHandler) GetSeriesByID(w http.ResponseWriter, r *http.Request) {
+	series, ok := h.loadSeries(w, r)
+	if !ok {
+		return
+	}
+
+	render.JSON(w, r, toSeriesResponse(series))
+}
+
+func (h *SeriesHandler) UpdateSeries(w http.ResponseWriter, r *http.Request) {
+	series, ok := h.loadSeries(w, r)
+	if !ok {
+		return
+	}
+
+	var req types.UpdateSeriesRequest
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode update request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Update validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+
+	rule, err := recurrence.Parse(req.Rule)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if !series.Active {
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "Series is stopped"})
+		return
+	}
+
+	series.Rule = rule.String()
+	h.saveSeries(w, r, series)
+}
+
+// StopSeries останавливает серию: текущие экземпляры остаются, новые больше не создаются
+func (h *SeriesHandler) StopSeries(w http.ResponseWriter, r *http.Request) {
+	series, ok := h.loadSeries(w, r)
+	if !ok {
+		return
+	}
+
+	series.Active = false
+	h.saveSeries(w, r, series)
+}
+
+func (h *SeriesHandler) saveSeries(w http.ResponseWriter, r *http.Request, series models.TaskSeries) {
+	err := h.repo.UpdateSeries(r.Context(), &series)
+	if err != nil {
+		slog.Error("Failed to update task series", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to update series"})
+
+		return
+	}
+
+	render.JSON(w, r, toSeriesResponse(series))
+}
+
+// loadSeries достаёт серию из URL. Чужая серия выглядит как несуществующая
+func (h *SeriesHandler) loadSeries(w http.ResponseWriter, r *http.Request) (models.TaskSeries, bool) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.TaskSeries{}, false
+	}
+
+	seriesID, err := strconv.ParseUint(chi.URLParam(r, "seriesID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid series ID"})
+		return models.TaskSeries{}, false
+	}
+
+	series, err := h.repo.GetSeriesByID(r.Context(), uint(seriesID))
+	if errors.Is(err, repository.ErrSeriesNotFound) || (err == nil && series.UserID != userID) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Series not found"})
+		return models.TaskSeries{}, false
+	}
+	if err != nil {
+		slog.Error("Failed to get task series by ID", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.TaskSeries{}, false
+	}
+
+	return series, true
+}
+
+func toSeriesResponse(series models.TaskSeries) types.SeriesResponse {
+	return types.SeriesResponse{
+		ID:        series.ID,
+		Rule:      series.Rule,
+		Active:    series.Active,
+		CreatedAt: series.CreatedAt,
+		UpdatedAt: series.UpdatedAt,
+	}
+}
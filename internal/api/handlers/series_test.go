@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSeriesRepository struct {
+	mock.Mock
+}
+
+func (m *MockSeriesRepository) CreateSeriesTask(ctx context.Context, series *models.TaskSeries, task *models.Task) error {
+	args := m.Called(ctx, series, task)
+	series.ID = 1
+	task.SeriesID = &series.ID
+	return args.Error(0)
+}
+func (m *MockSeriesRepository) CreateOccurrence(ctx context.Context, task *models.Task) (bool, error) {
+	args := m.Called(ctx, task)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockSeriesRepository) GetSeriesByID(ctx context.Context, id uint) (models.TaskSeries, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.TaskSeries), args.Error(1)
+}
+func (m *MockSeriesRepository) GetSeriesByUserID(ctx context.Context, userID uint) ([]models.TaskSeries, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.TaskSeries), args.Error(1)
+}
+func (m *MockSeriesRepository) UpdateSeries(ctx context.Context, series *models.TaskSeries) error {
+	args := m.Called(ctx, series)
+	return args.Error(0)
+}
+
+func withSeriesID(req *http.Request, seriesID string, userID uint) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("seriesID", seriesID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(withUserID(ctx, userID))
+}
+
+func TestTaskHandler_CreateRecurringTask(t *testing.T) {
+	newRequest := func(recurrence string) *http.Request {
+		body, _ := json.Marshal(types.CreateTaskRequest{Name: "Chores", Deadline: time.Now().Add(time.Hour), Recurrence: recurrence})
+		req := httptest.NewRequest("POST", "/tasks", bytes.NewReader(body))
+		return req.WithContext(withUserID(req.Context(), 10))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockSeries := new(MockSeriesRepository)
+		handler := NewTaskHandler(new(MockTaskRepository), new(MockProjectRepository), mockSeries, fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockSeries.On("CreateSeriesTask", mock.Anything,
+			mock.MatchedBy(func(s *models.TaskSeries) bool { return s.Rule == "FREQ=WEEKLY;BYDAY=MO,WE" && s.Active }),
+			mock.MatchedBy(func(task *models.Task) bool { return task.Occurrence == 1 }),
+		).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.CreateTask(rr, newRequest("RRULE:FREQ=WEEKLY;BYDAY=WE,MO"))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		mockSeries.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Rule", func(t *testing.T) {
+		mockSeries := new(MockSeriesRepository)
+		handler := NewTaskHandler(new(MockTaskRepository), new(MockProjectRepository), mockSeries, fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		rr := httptest.NewRecorder()
+		handler.CreateTask(rr, newRequest("FREQ=HOURLY"))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockSeries.AssertNotCalled(t, "CreateSeriesTask")
+	})
+}
+
+func TestTaskHandler_CompleteRecurringTask(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	mockSeries := new(MockSeriesRepository)
+	eventStore := &fakeEventStore{}
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), mockSeries, fakeTransactor{}, eventStore, &fakeAuditRecorder{}, TaskPolicy{})
+
+	seriesID := uint(5)
+	deadline := time.Now().Add(time.Hour)
+	task := models.Task{ID: 1, UserID: 10, SeriesID: &seriesID, Occurrence: 1, Status: models.StatusInProgress, Deadline: deadline}
+
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+	mockRepo.On("UpdateTaskStatus", mock.Anything, uint(1), models.StatusCompleted).Return(nil).Once()
+	mockSeries.On("GetSeriesByID", inFakeTx, seriesID).Return(models.TaskSeries{ID: seriesID, UserID: 10, Rule: "FREQ=DAILY", Active: true}, nil).Once()
+	// Следующий экземпляр создаётся в той же транзакции, что и завершение
+	mockSeries.On("CreateOccurrence", inFakeTx, mock.MatchedBy(func(next *models.Task) bool {
+		return next.Occurrence == 2 && next.Deadline.Equal(deadline.AddDate(0, 0, 1)) && next.Status == models.StatusPending
+	})).Return(true, nil).Once()
+
+	status := models.StatusCompleted
+	body, _ := json.Marshal(types.UpdateTaskRequest{Status: &status})
+	rr := httptest.NewRecorder()
+	handler.UpdateTask(rr, withTaskID(httptest.NewRequest("PATCH", "/tasks/1", bytes.NewReader(body)), "1", 10))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	mockSeries.AssertExpectations(t)
+	// task.created для нового экземпляра пишет сам CreateOccurrence
+	assert.Equal(t, []models.EventType{models.EventTaskUpdated, models.EventTaskStatusChanged}, eventStore.eventTypes())
+}
+
+func TestTaskHandler_CompleteRecurringTask_SpawnFailureFailsUpdate(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	mockSeries := new(MockSeriesRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), mockSeries, fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	seriesID := uint(5)
+	task := models.Task{ID: 1, UserID: 10, SeriesID: &seriesID, Occurrence: 1, Status: models.StatusInProgress, Deadline: time.Now().Add(time.Hour)}
+
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+	mockRepo.On("UpdateTaskStatus", mock.Anything, uint(1), models.StatusCompleted).Return(nil).Once()
+	mockSeries.On("GetSeriesByID", inFakeTx, seriesID).Return(models.TaskSeries{ID: seriesID, UserID: 10, Rule: "FREQ=DAILY", Active: true}, nil).Once()
+	mockSeries.On("CreateOccurrence", inFakeTx, mock.Anything).Return(false, errors.New("connection reset")).Once()
+
+	status := models.StatusCompleted
+	body, _ := json.Marshal(types.UpdateTaskRequest{Status: &status})
+	rr := httptest.NewRecorder()
+	handler.UpdateTask(rr, withTaskID(httptest.NewRequest("PATCH", "/tasks/1", bytes.NewReader(body)), "1", 10))
+
+	// Ошибка откатывает и завершение задачи, ревизия не пишется
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	mockSeries.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "CreateTaskRevision", mock.Anything, mock.Anything)
+}
+
+func TestSeriesHandler_UpdateSeries(t *testing.T) {
+	newRequest := func(rule string) *http.Request {
+		body, _ := json.Marshal(types.UpdateSeriesRequest{Rule: rule})
+		return withSeriesID(httptest.NewRequest("PATCH", "/series/1", bytes.NewReader(body)), "1", 10)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockSeries := new(MockSeriesRepository)
+		handler := NewSeriesHandler(mockSeries)
+
+		mockSeries.On("GetSeriesByID", mock.Anything, uint(1)).Return(models.TaskSeries{ID: 1, UserID: 10, Rule: "FREQ=DAILY", Active: true}, nil).Once()
+		mockSeries.On("UpdateSeries", mock.Anything, mock.MatchedBy(func(s *models.TaskSeries) bool { return s.Rule == "FREQ=MONTHLY" })).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateSeries(rr, newRequest("monthly"))
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp types.SeriesResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, "FREQ=MONTHLY", resp.Rule)
+	})
+
+	t.Run("Stopped Series", func(t *testing.T) {
+		mockSeries := new(MockSeriesRepository)
+		handler := NewSeriesHandler(mockSeries)
+
+		mockSeries.On("GetSeriesByID", mock.Anything, uint(1)).Return(models.TaskSeries{ID: 1, UserID: 10, Rule: "FREQ=DAILY"}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateSeries(rr, newRequest("weekly"))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		mockSeries.AssertNotCalled(t, "UpdateSeries")
+	})
+
+	t.Run("Another User's Series", func(t *testing.T) {
+		mockSeries := new(MockSeriesRepository)
+		handler := NewSeriesHandler(mockSeries)
+
+		mockSeries.On("GetSeriesByID", mock.Anything, uint(1)).Return(models.TaskSeries{ID: 1, UserID: 99, Active: true}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateSeries(rr, newRequest("weekly"))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestSeriesHandler_StopSeries(t *testing.T) {
+	mockSeries := new(MockSeriesRepository)
+	handler := NewSeriesHandler(mockSeries)
+
+	mockSeries.On("GetSeriesByID", mock.Anything, uint(1)).Return(models.TaskSeries{ID: 1, UserID: 10, Rule: "FREQ=DAILY", Active: true}, nil).Once()
+	mockSeries.On("UpdateSeries", mock.Anything, mock.MatchedBy(func(s *models.TaskSeries) bool { return !s.Active })).Return(nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.StopSeries(rr, withSeriesID(httptest.NewRequest("POST", "/series/1/stop", nil), "1", 10))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockSeries.AssertExpectations(t)
+}
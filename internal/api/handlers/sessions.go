@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/middleware"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type SessionHandler struct {
+	repo repository.SessionRepository
+}
+
+func NewSessionHandler(repo repository.SessionRepository) *SessionHandler {
+	return &SessionHandler{repo: repo}
+}
+
+func (h *SessionHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	sessions, err := h.repo.GetActiveSessionsByUserID(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get user sessions", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get sessions"})
+
+		return
+	}
+
+	currentTokenID, _ := r.Context().Value(middleware.TokenIDKey).(string)
+
+	resp := make([]types.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		resp = append(resp, types.SessionResponse{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			ExpiresAt:  s.ExpiresAt,
+			Current:    currentTokenID != "" && s.TokenID == currentTokenID,
+		})
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (h *SessionHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	sessionIDStr := chi.URLParam(r, "sessionID")
+	sessionID, err := strconv.ParseUint(sessionIDStr, 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid session ID"})
+		return
+	}
+
+	err = h.repo.RevokeSession(r.Context(), userID, uint(sessionID))
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Session not found"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to revoke session", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to revoke session"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
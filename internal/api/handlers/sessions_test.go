@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/middleware"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionHandler_GetSessions(t *testing.T) {
+	mockRepo := new(MockSessionRepository)
+	handler := NewSessionHandler(mockRepo)
+
+	userID := uint(10)
+	sessions := []models.Session{
+		{ID: 1, UserID: userID, TokenID: "current", UserAgent: "curl", IP: "127.0.0.1", LastSeenAt: time.Now()},
+		{ID: 2, UserID: userID, TokenID: "other", UserAgent: "firefox", IP: "10.0.0.1", LastSeenAt: time.Now()},
+	}
+	mockRepo.On("GetActiveSessionsByUserID", mock.Anything, userID).Return(sessions, nil).Once()
+
+	req := httptest.NewRequest("GET", "/users/me/sessions", nil)
+	ctx := withUserID(req.Context(), userID)
+	ctx = context.WithValue(ctx, middleware.TokenIDKey, "current")
+	rr := httptest.NewRecorder()
+
+	handler.GetSessions(rr, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp []types.SessionResponse
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	require.Len(t, resp, 2)
+	assert.True(t, resp[0].Current)
+	assert.False(t, resp[1].Current)
+	assert.Equal(t, "firefox", resp[1].UserAgent)
+}
+
+func TestSessionHandler_DeleteSession(t *testing.T) {
+	mockRepo := new(MockSessionRepository)
+	handler := NewSessionHandler(mockRepo)
+
+	newRequest := func(sessionID string) *http.Request {
+		req := httptest.NewRequest("DELETE", "/users/me/sessions/"+sessionID, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("sessionID", sessionID)
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		ctx = withUserID(ctx, 10)
+		return req.WithContext(ctx)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("RevokeSession", mock.Anything, uint(10), uint(5)).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.DeleteSession(rr, newRequest("5"))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockRepo.On("RevokeSession", mock.Anything, uint(10), uint(6)).Return(repository.ErrSessionNotFound).Once()
+
+		rr := httptest.NewRecorder()
+		handler.DeleteSession(rr, newRequest("6"))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.DeleteSession(rr, newRequest("abc"))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/audit"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// GetSharedTasks - задачи других пользователей, к которым текущему выдали доступ
+func (h *TaskHandler) GetSharedTasks(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	shared, err := h.repo.GetSharedTasks(r.Context(), userID, limit, offset)
+	if err != nil {
+		slog.Error("Failed to get shared tasks", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get shared tasks"})
+
+		return
+	}
+
+	resp := make([]types.SharedTaskResponse, 0, len(shared))
+	for _, s := range shared {
+		resp = append(resp, types.SharedTaskResponse{Permission: s.Permission, Task: types.NewTaskResponse(s.Task)})
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (h *TaskHandler) GetTaskShares(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.loadTask(w, r, models.AccessOwner)
+	if !ok {
+		return
+	}
+
+	shares, err := h.repo.GetTaskShares(r.Context(), task.ID)
+	if err != nil {
+		slog.Error("Failed to get task shares", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get task shares"})
+
+		return
+	}
+
+	resp := make([]types.ShareResponse, 0, len(shares))
+	for _, share := range shares {
+		resp = append(resp, toShareResponse(share))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// ShareTask выдаёт доступ к задаче. Повторный вызов для того же пользователя меняет право
+func (h *TaskHandler) ShareTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.loadTask(w, r, models.AccessOwner)
+	if !ok {
+		return
+	}
+
+	var req types.ShareTaskRequest
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+		return
+	}
+
+	var share models.TaskShare
+	var created bool
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		share, created, err = h.repo.ShareTask(ctx, task.ID, task.UserID, req.User, req.Permission)
+		if err != nil || !created {
+			return err
+		}
+		// Получателю сообщает подписчик task.shared; смена права уведомления не требует
+		return h.events.AppendEvents(ctx, stampEvents(r, task.UserID, models.NewTaskShareEvent(task, share))...)
+	})
+	if errors.Is(err, repository.ErrShareUserNotFound) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "User not found"})
+		return
+	}
+	if errors.Is(err, repository.ErrShareWithOwner) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Task can't be shared with its owner"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to share task", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to share task"})
+
+		return
+	}
+
+	event := audit.NewEvent(r, models.AuditTaskShared)
+	event.UserID = &task.UserID
+	event.EntityType = "task"
+	event.EntityID = &task.ID
+	event.After = audit.Snapshot(map[string]any{"userId": share.UserID, "permission": share.Permission})
+	h.audit.Record(r.Context(), event)
+
+	if created {
+		render.Status(r, http.StatusCreated)
+	}
+	render.JSON(w, r, toShareResponse(share))
+}
+
+// RemoveTaskShare отзывает доступ. Получатель может и сам отказаться от доступа к чужой задаче
+func (h *TaskHandler) RemoveTaskShare(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	shareUserID, err := strconv.ParseUint(chi.URLParam(r, "userID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid user ID"})
+		return
+	}
+
+	need := models.AccessOwner
+	if uint(shareUserID) == userID {
+		need = models.AccessView
+	}
+
+	task, ok := h.loadTask(w, r, need)
+	if !ok {
+		return
+	}
+
+	err = h.repo.RemoveTaskShare(r.Context(), task.ID, uint(shareUserID))
+	if errors.Is(err, repository.ErrShareNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Share not found"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to remove task share", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to remove task share"})
+
+		return
+	}
+
+	event := audit.NewEvent(r, models.AuditTaskUnshared)
+	event.UserID = &userID
+	event.EntityType = "task"
+	event.EntityID = &task.ID
+	event.Before = audit.Snapshot(map[string]uint64{"userId": shareUserID})
+	h.audit.Record(r.Context(), event)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toShareResponse(share models.TaskShare) types.ShareResponse {
+	return types.ShareResponse{
+		UserID:     share.UserID,
+		Username:   share.Username,
+		Permission: share.Permission,
+		CreatedAt:  share.CreatedAt,
+	}
+}
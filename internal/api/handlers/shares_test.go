@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func withShareUserID(req *http.Request, taskID, shareUserID string, userID uint) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("taskID", taskID)
+	rctx.URLParams.Add("userID", shareUserID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(withUserID(ctx, userID))
+}
+
+func TestTaskHandler_ShareTask(t *testing.T) {
+	testCases := []struct {
+		name         string
+		requester    uint
+		share        models.TaskShare
+		created      bool
+		repoErr      error
+		expectedCode int
+	}{
+		{"New Share", 10, models.TaskShare{UserID: 30, Username: "bob", Permission: models.ShareEditor}, true, nil, http.StatusCreated},
+		{"Permission Changed", 10, models.TaskShare{UserID: 30, Username: "bob", Permission: models.ShareEditor}, false, nil, http.StatusOK},
+		{"Unknown User", 10, models.TaskShare{}, false, repository.ErrShareUserNotFound, http.StatusBadRequest},
+		{"Share With Owner", 10, models.TaskShare{}, false, repository.ErrShareWithOwner, http.StatusBadRequest},
+		{"Editor Can't Share", 30, models.TaskShare{}, false, nil, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			auditRecorder := &fakeAuditRecorder{}
+			eventStore := &fakeEventStore{}
+			handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, eventStore, auditRecorder, TaskPolicy{})
+
+			mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+			mockRepo.On("GetTaskSharePermission", mock.Anything, uint(1), uint(30)).Return(models.ShareEditor, nil).Maybe()
+			mockRepo.On("ShareTask", mock.Anything, uint(1), uint(10), "bob@example.com", models.ShareEditor).
+				Return(tc.share, tc.created, tc.repoErr).Maybe()
+
+			body, _ := json.Marshal(types.ShareTaskRequest{User: "bob@example.com", Permission: models.ShareEditor})
+			rr := httptest.NewRecorder()
+			handler.ShareTask(rr, withTaskID(httptest.NewRequest("POST", "/tasks/1/shares", bytes.NewReader(body)), "1", tc.requester))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if rr.Code == http.StatusOK || rr.Code == http.StatusCreated {
+				var resp types.ShareResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, "bob", resp.Username)
+				require.Len(t, auditRecorder.events, 1)
+				assert.Equal(t, models.AuditTaskShared, auditRecorder.events[0].Action)
+			} else {
+				assert.Empty(t, auditRecorder.events)
+			}
+			if tc.created {
+				assert.Equal(t, []models.EventType{models.EventTaskShared}, eventStore.eventTypes())
+			} else {
+				assert.Empty(t, eventStore.events)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_ShareTask_InvalidPermission(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+
+	body, _ := json.Marshal(types.ShareTaskRequest{User: "bob", Permission: "owner"})
+	rr := httptest.NewRecorder()
+	handler.ShareTask(rr, withTaskID(httptest.NewRequest("POST", "/tasks/1/shares", bytes.NewReader(body)), "1", 10))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockRepo.AssertNotCalled(t, "ShareTask")
+}
+
+func TestTaskHandler_RemoveTaskShare(t *testing.T) {
+	testCases := []struct {
+		name         string
+		requester    uint
+		shareUserID  string
+		expectRemove bool
+		expectedCode int
+	}{
+		{"Owner Revokes", 10, "30", true, http.StatusNoContent},
+		{"Grantee Leaves", 30, "30", true, http.StatusNoContent},
+		{"Grantee Can't Revoke Others", 30, "40", false, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+			mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+			mockRepo.On("GetTaskSharePermission", mock.Anything, uint(1), uint(30)).Return(models.ShareViewer, nil).Maybe()
+			if tc.expectRemove {
+				mockRepo.On("RemoveTaskShare", mock.Anything, uint(1), uint(30)).Return(nil).Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.RemoveTaskShare(rr, withShareUserID(httptest.NewRequest("DELETE", "/tasks/1/shares/"+tc.shareUserID, nil), "1", tc.shareUserID, tc.requester))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			mockRepo.AssertExpectations(t)
+			if !tc.expectRemove {
+				mockRepo.AssertNotCalled(t, "RemoveTaskShare")
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetSharedTasks(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	mockRepo.On("GetSharedTasks", mock.Anything, uint(30), 10, 0).Return([]models.SharedTask{
+		{Task: models.Task{ID: 1, UserID: 10, Name: "Shared"}, Permission: models.ShareViewer},
+	}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/tasks/shared", nil)
+	rr := httptest.NewRecorder()
+	handler.GetSharedTasks(rr, req.WithContext(withUserID(req.Context(), 30)))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp []types.SharedTaskResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, models.ShareViewer, resp[0].Permission)
+	assert.Equal(t, "Shared", resp[0].Task.Name)
+}
+
+// Права из выданного доступа: зритель только читает, редактор меняет содержимое, но не переносит и не удаляет
+func TestTaskHandler_SharedAccess(t *testing.T) {
+	task := models.Task{ID: 1, UserID: 10, Name: "Shared", Status: models.StatusPending}
+	name := "Renamed"
+	projectID := uint(4)
+
+	testCases := []struct {
+		name         string
+		permission   models.SharePermission
+		call         func(h *TaskHandler, w http.ResponseWriter, r *http.Request)
+		body         any
+		setup        func(m *MockTaskRepository)
+		expectedCode int
+	}{
+		{"Viewer Reads", models.ShareViewer, (*TaskHandler).GetTaskByID, nil, nil, http.StatusOK},
+		{"Viewer Can't Update", models.ShareViewer, (*TaskHandler).UpdateTask, types.UpdateTaskRequest{Name: &name}, nil, http.StatusForbidden},
+		{"Editor Updates", models.ShareEditor, (*TaskHandler).UpdateTask, types.UpdateTaskRequest{Name: &name}, func(m *MockTaskRepository) {
+			m.On("UpdateTaskName", mock.Anything, uint(1), name).Return(nil).Once()
+			m.On("CreateTaskRevision", mock.Anything, mock.MatchedBy(func(rev *models.TaskRevision) bool {
+				return rev.UserID != nil && *rev.UserID == 30
+			})).Return(nil).Once()
+		}, http.StatusNoContent},
+		{"Editor Can't Move", models.ShareEditor, (*TaskHandler).UpdateTask, types.UpdateTaskRequest{ProjectID: &projectID}, nil, http.StatusForbidden},
+		{"Editor Can't Delete", models.ShareEditor, (*TaskHandler).DeleteTask, nil, nil, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+			mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+			mockRepo.On("GetTaskSharePermission", mock.Anything, uint(1), uint(30)).Return(tc.permission, nil).Once()
+			if tc.setup != nil {
+				tc.setup(mockRepo)
+			}
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+			rr := httptest.NewRecorder()
+			tc.call(handler, rr, withTaskID(httptest.NewRequest("PATCH", "/tasks/1", &body), "1", 30))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/stream"
+
+	"github.com/go-chi/render"
+)
+
+// TaskEventHistory отдаёт события задач из outbox, пропущенные клиентом потока
+type TaskEventHistory interface {
+	GetTaskEventsForUser(ctx context.Context, userID, afterID uint, limit int) ([]models.DomainEvent, error)
+}
+
+// StreamPolicy: Heartbeat - как часто слать комментарий, чтобы прокси не закрывали тихое соединение;
+// ReplayLimit - сколько пропущенных событий отдать при переподключении; WriteTimeout - сколько ждать
+// записи одного события, прежде чем счесть клиента отвалившимся
+type StreamPolicy struct {
+	Heartbeat    time.Duration
+	ReplayLimit  int
+	WriteTimeout time.Duration
+}
+
+// StreamHandler отдаёт изменения задач пользователя через Server-Sent Events
+type StreamHandler struct {
+	hub     *stream.Hub
+	history TaskEventHistory
+	policy  StreamPolicy
+}
+
+func NewStreamHandler(hub *stream.Hub, history TaskEventHistory, policy StreamPolicy) *StreamHandler {
+	return &StreamHandler{hub: hub, history: history, policy: policy}
+}
+
+// StreamTasks держит соединение и пишет события task.created, task.updated и task.deleted по задачам,
+// где пользователь владелец или исполнитель. id события - id в outbox: переподключившись с заголовком
+// Last-Event-ID (или ?lastEventId=), клиент сначала получит пропущенное. Браузерный EventSource
+// не умеет слать Authorization, поэтому клиенту нужен fetch-совместимый полифилл
+func (h *StreamHandler) StreamTasks(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid Last-Event-ID"})
+		return
+	}
+
+	// Подписка до чтения истории: событие, записанное между ними, не потеряется, а повтор отсеется ниже.
+	// Хаб отказывает в подписке, только когда сервер останавливается
+	sub, err := h.hub.Subscribe(userID)
+	if err != nil {
+		render.Status(r, http.StatusServiceUnavailable)
+		render.JSON(w, r, map[string]string{"error": "Server is shutting down"})
+		return
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	var replayed []models.DomainEvent
+	if lastEventID > 0 {
+		replayed, err = h.history.GetTaskEventsForUser(r.Context(), userID, lastEventID, h.policy.ReplayLimit)
+		if err != nil {
+			slog.Error("Failed to get missed task events", slog.Any("error", err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Failed to get missed events"})
+
+			return
+		}
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(frame string) bool {
+		// Общий WriteTimeout сервера оборвал бы поток, поэтому срок ставится на каждую запись.
+		// ResponseRecorder в тестах дедлайны не поддерживает, это не ошибка
+		_ = rc.SetWriteDeadline(time.Now().Add(h.policy.WriteTimeout))
+		_, err := fmt.Fprint(w, frame)
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			slog.Debug("Task stream write failed", slog.Any("userID", userID), slog.Any("error", err))
+			return false
+		}
+		return true
+	}
+
+	if !send(": connected\n\n") {
+		return
+	}
+
+	// Транзакции коммитятся не в порядке id, поэтому живые события отсеиваются только по тем, что уже
+	// ушли из истории, а не по сравнению с последним id
+	sent := make(map[uint]struct{}, len(replayed))
+	for _, e := range replayed {
+		event, _, ok := stream.FromDomainEvent(e)
+		if !ok {
+			continue
+		}
+		if !send(formatStreamEvent(event)) {
+			return
+		}
+		sent[event.ID] = struct{}{}
+	}
+
+	heartbeat := time.NewTicker(h.policy.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sub.Done():
+			// Клиент не успевал читать или сервер останавливается: он переподключится с Last-Event-ID
+			return
+		case event := <-sub.Events():
+			if _, ok := sent[event.ID]; ok {
+				continue
+			}
+			if !send(formatStreamEvent(event)) {
+				return
+			}
+		case <-heartbeat.C:
+			if !send(": ping\n\n") {
+				return
+			}
+		}
+	}
+}
+
+func parseLastEventID(r *http.Request) (uint, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	return uint(id), err
+}
+
+func formatStreamEvent(event stream.Event) string {
+	// Task состоит из простых полей, Marshal для него не возвращает ошибку
+	data, _ := json.Marshal(types.TaskStreamEvent{
+		ID:         event.ID,
+		Type:       event.Type,
+		Task:       types.NewTaskResponse(event.Task),
+		OccurredAt: event.OccurredAt,
+	})
+	return fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/stream"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTaskEventHistory struct {
+	events  []models.DomainEvent
+	afterID uint
+}
+
+func (f *fakeTaskEventHistory) GetTaskEventsForUser(ctx context.Context, userID, afterID uint, limit int) ([]models.DomainEvent, error) {
+	f.afterID = afterID
+	return f.events, nil
+}
+
+type sseFrame struct {
+	id, event, data string
+	comment         string
+}
+
+// openTaskStream подключается к потоку пользователя userID и возвращает кадры по мере их прихода
+func openTaskStream(t *testing.T, handler *StreamHandler, userID uint, lastEventID string) <-chan sseFrame {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.StreamTasks(w, r.WithContext(withUserID(r.Context(), userID)))
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	frames := make(chan sseFrame, 16)
+	go func() {
+		defer close(frames)
+		scanner := bufio.NewScanner(resp.Body)
+		var frame sseFrame
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				frames <- frame
+				frame = sseFrame{}
+			case strings.HasPrefix(line, ":"):
+				frame.comment = strings.TrimSpace(line[1:])
+			case strings.HasPrefix(line, "id: "):
+				frame.id = line[len("id: "):]
+			case strings.HasPrefix(line, "event: "):
+				frame.event = line[len("event: "):]
+			case strings.HasPrefix(line, "data: "):
+				frame.data = line[len("data: "):]
+			}
+		}
+	}()
+
+	require.Equal(t, "connected", nextFrame(t, frames).comment)
+	return frames
+}
+
+func nextFrame(t *testing.T, frames <-chan sseFrame) sseFrame {
+	t.Helper()
+	select {
+	case frame, ok := <-frames:
+		require.True(t, ok, "stream closed")
+		return frame
+	case <-time.After(2 * time.Second):
+		t.Fatal("no frame received")
+		return sseFrame{}
+	}
+}
+
+func streamTaskEvent(id uint, eventType models.EventType, task models.Task) models.DomainEvent {
+	event := models.NewTaskEvent(eventType, nil, &task)
+	event.ID = id
+	return event
+}
+
+func testStreamPolicy() StreamPolicy {
+	return StreamPolicy{Heartbeat: time.Hour, ReplayLimit: 100, WriteTimeout: time.Second}
+}
+
+func TestStreamHandler_StreamsOwnTaskEvents(t *testing.T) {
+	hub := stream.NewHub(8)
+	handler := NewStreamHandler(hub, &fakeTaskEventHistory{}, testStreamPolicy())
+
+	frames := openTaskStream(t, handler, 10, "")
+
+	hub.Publish(streamTaskEvent(41, models.EventTaskCreated, models.Task{ID: 3, UserID: 20, Name: "Not mine"}))
+	hub.Publish(streamTaskEvent(42, models.EventTaskCreated, models.Task{ID: 1, UserID: 10, Name: "Mine"}))
+
+	frame := nextFrame(t, frames)
+	assert.Equal(t, "42", frame.id)
+	assert.Equal(t, "task.created", frame.event)
+
+	var payload types.TaskStreamEvent
+	require.NoError(t, json.Unmarshal([]byte(frame.data), &payload))
+	assert.Equal(t, uint(1), payload.Task.ID)
+	assert.Equal(t, "Mine", payload.Task.Name)
+	assert.Contains(t, frame.data, `"task":{"id":1,"userId":10,`)
+}
+
+func TestStreamHandler_ReplaysMissedEventsWithoutDuplicates(t *testing.T) {
+	hub := stream.NewHub(8)
+	history := &fakeTaskEventHistory{events: []models.DomainEvent{
+		streamTaskEvent(5, models.EventTaskCreated, models.Task{ID: 1, UserID: 10}),
+		streamTaskEvent(6, models.EventTaskUpdated, models.Task{ID: 1, UserID: 10}),
+	}}
+	handler := NewStreamHandler(hub, history, testStreamPolicy())
+
+	frames := openTaskStream(t, handler, 10, "4")
+	assert.Equal(t, uint(4), history.afterID)
+	assert.Equal(t, "5", nextFrame(t, frames).id)
+	assert.Equal(t, "6", nextFrame(t, frames).id)
+
+	// Событие 6 уже пришло из истории, повторно из хаба его не шлём
+	hub.Publish(streamTaskEvent(6, models.EventTaskUpdated, models.Task{ID: 1, UserID: 10}))
+	hub.Publish(streamTaskEvent(7, models.EventTaskDeleted, models.Task{ID: 1, UserID: 10}))
+	assert.Equal(t, "7", nextFrame(t, frames).id)
+}
+
+func TestStreamHandler_SendsHeartbeats(t *testing.T) {
+	policy := testStreamPolicy()
+	policy.Heartbeat = 10 * time.Millisecond
+	handler := NewStreamHandler(stream.NewHub(8), &fakeTaskEventHistory{}, policy)
+
+	frames := openTaskStream(t, handler, 10, "")
+
+	assert.Equal(t, "ping", nextFrame(t, frames).comment)
+}
+
+func TestStreamHandler_ClosesWhenHubCloses(t *testing.T) {
+	hub := stream.NewHub(8)
+	handler := NewStreamHandler(hub, &fakeTaskEventHistory{}, testStreamPolicy())
+
+	frames := openTaskStream(t, handler, 10, "")
+	hub.Close()
+
+	select {
+	case _, ok := <-frames:
+		assert.False(t, ok)
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream was not closed")
+	}
+}
+
+func TestStreamHandler_InvalidLastEventID(t *testing.T) {
+	handler := NewStreamHandler(stream.NewHub(8), &fakeTaskEventHistory{}, testStreamPolicy())
+
+	req := httptest.NewRequest("GET", "/tasks/stream?lastEventId=abc", nil)
+	rr := httptest.NewRecorder()
+	handler.StreamTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+
+	"github.com/go-chi/render"
+)
+
+func (h *TaskHandler) GetSubtasks(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.loadTask(w, r, models.AccessView)
+	if !ok {
+		return
+	}
+
+	subtasks, err := h.repo.GetSubtasks(r.Context(), task.ID)
+	if err != nil {
+		slog.Error("Failed to get subtasks", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get subtasks"})
+
+		return
+	}
+
+	render.JSON(w, r, types.NewTaskResponses(subtasks))
+}
+
+func (h *TaskHandler) CreateSubtask(w http.ResponseWriter, r *http.Request) {
+	parent, ok := h.loadTask(w, r, models.AccessOwner)
+	if !ok {
+		return
+	}
+
+	var req types.CreateTaskRequest
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+		return
+	}
+
+	h.createTask(w, r, parent.UserID, req, &parent)
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func withTaskID(req *http.Request, taskID string, userID uint) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("taskID", taskID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(withUserID(ctx, userID))
+}
+
+func TestTaskHandler_CreateSubtask(t *testing.T) {
+	projectID := uint(4)
+	parent := models.Task{ID: 1, UserID: 10, ProjectID: &projectID, Status: models.StatusPending}
+	newRequest := func() *http.Request {
+		body, _ := json.Marshal(types.CreateTaskRequest{Name: "Step", Deadline: time.Now().Add(time.Hour)})
+		return withTaskID(httptest.NewRequest("POST", "/tasks/1/subtasks", bytes.NewReader(body)), "1", 10)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(parent, nil).Once()
+		mockRepo.On("GetTaskAncestorIDs", mock.Anything, uint(1)).Return([]uint(nil), nil).Once()
+		mockRepo.On("CreateTask", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+			return task.ParentID != nil && *task.ParentID == 1 && task.ProjectID != nil && *task.ProjectID == projectID
+		})).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.CreateSubtask(rr, newRequest())
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Too Deep", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(parent, nil).Once()
+		mockRepo.On("GetTaskAncestorIDs", mock.Anything, uint(1)).Return([]uint{7, 8, 9}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.CreateSubtask(rr, newRequest())
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		mockRepo.AssertNotCalled(t, "CreateTask")
+	})
+
+	t.Run("Another User's Task", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 99}, nil).Once()
+		mockRepo.On("GetTaskSharePermission", mock.Anything, mock.Anything, uint(10)).Return(models.SharePermission(""), repository.ErrShareNotFound).Once()
+
+		rr := httptest.NewRecorder()
+		handler.CreateSubtask(rr, newRequest())
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestTaskHandler_GetSubtasks(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	parentID := uint(1)
+	subtasks := []models.Task{{ID: 2, UserID: 10, ParentID: &parentID, Name: "Step"}}
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Once()
+	mockRepo.On("GetSubtasks", mock.Anything, uint(1)).Return(subtasks, nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.GetSubtasks(rr, withTaskID(httptest.NewRequest("GET", "/tasks/1/subtasks", nil), "1", 10))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var got []types.TaskResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, subtasks[0].ID, got[0].ID)
+	assert.Equal(t, parentID, *got[0].ParentID)
+}
+
+func TestTaskHandler_UpdateTask_Parent(t *testing.T) {
+	task := models.Task{ID: 1, UserID: 10, Status: models.StatusPending, Deadline: time.Now().Add(time.Hour)}
+	newRequest := func(parentID uint) *http.Request {
+		body, _ := json.Marshal(types.UpdateTaskRequest{ParentID: &parentID})
+		return withTaskID(httptest.NewRequest("PATCH", "/tasks/1", bytes.NewReader(body)), "1", 10)
+	}
+
+	t.Run("Move Under Own Subtask", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+		mockRepo.On("GetTaskByID", mock.Anything, uint(3)).Return(models.Task{ID: 3, UserID: 10}, nil).Once()
+		mockRepo.On("GetTaskAncestorIDs", mock.Anything, uint(3)).Return([]uint{2, 1}, nil).Once()
+		mockRepo.On("GetSubtreeHeight", mock.Anything, uint(1)).Return(2, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest(3))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		mockRepo.AssertNotCalled(t, "UpdateTaskParent")
+	})
+
+	t.Run("Detach", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+		mockRepo.On("UpdateTaskParent", mock.Anything, uint(1), (*uint)(nil)).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest(0))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTaskHandler_UpdateTask_OpenSubtasks(t *testing.T) {
+	task := models.Task{ID: 1, UserID: 10, Status: models.StatusInProgress, Deadline: time.Now().Add(time.Hour)}
+	newRequest := func() *http.Request {
+		status := models.StatusCompleted
+		body, _ := json.Marshal(types.UpdateTaskRequest{Status: &status})
+		return withTaskID(httptest.NewRequest("PATCH", "/tasks/1", bytes.NewReader(body)), "1", 10)
+	}
+
+	t.Run("Rule Enabled", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{RequireSubtasksCompleted: true})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+		mockRepo.On("CountOpenSubtasks", mock.Anything, uint(1)).Return(2, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest())
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		mockRepo.AssertNotCalled(t, "UpdateTaskStatus")
+	})
+
+	t.Run("Rule Disabled", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil).Once()
+		mockRepo.On("UpdateTaskStatus", mock.Anything, uint(1), models.StatusCompleted).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest())
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		mockRepo.AssertNotCalled(t, "CountOpenSubtasks")
+	})
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type TagHandler struct {
+	repo repository.TagRepository
+}
+
+func NewTagHandler(repo repository.TagRepository) *TagHandler {
+	return &TagHandler{repo: repo}
+}
+
+func (h *TagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	tags, err := h.repo.GetTagsByUserID(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get user tags", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get tags"})
+
+		return
+	}
+
+	resp := make([]types.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		resp = append(resp, toTagResponse(tag))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (h *TagHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	name, ok := decodeTagName(w, r)
+	if !ok {
+		return
+	}
+
+	tag := &models.Tag{UserID: userID, Name: name}
+	err = h.repo.CreateTag(r.Context(), tag)
+	if errors.Is(err, repository.ErrTagExists) {
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "Tag already exists"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to create tag", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to create tag"})
+
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, toTagResponse(*tag))
+}
+
+func (h *TagHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	tagID, err := strconv.ParseUint(chi.URLParam(r, "tagID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid tag ID"})
+		return
+	}
+
+	name, ok := decodeTagName(w, r)
+	if !ok {
+		return
+	}
+
+	err = h.repo.RenameTag(r.Context(), userID, uint(tagID), name)
+	switch {
+	case errors.Is(err, repository.ErrTagNotFound):
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Tag not found"})
+		return
+	case errors.Is(err, repository.ErrTagExists):
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "Tag already exists"})
+		return
+	case err != nil:
+		slog.Error("Failed to rename tag", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to update tag"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TagHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	tagID, err := strconv.ParseUint(chi.URLParam(r, "tagID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid tag ID"})
+		return
+	}
+
+	err = h.repo.DeleteTag(r.Context(), userID, uint(tagID))
+	if errors.Is(err, repository.ErrTagNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Tag not found"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to delete tag", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to delete tag"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeTagName читает и валидирует тело запроса, при ошибке сам пишет ответ
+func decodeTagName(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req types.TagRequest
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return "", false
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return "", false
+	}
+
+	return req.Name, true
+}
+
+func toTagResponse(tag models.Tag) types.TagResponse {
+	return types.TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		CreatedAt: tag.CreatedAt,
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) CreateTag(ctx context.Context, tag *models.Tag) error {
+	args := m.Called(ctx, tag)
+	tag.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetTagsByUserID(ctx context.Context, userID uint) ([]models.Tag, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) RenameTag(ctx context.Context, userID, id uint, name string) error {
+	args := m.Called(ctx, userID, id, name)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) DeleteTag(ctx context.Context, userID, id uint) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func TestTagHandler_CreateTag(t *testing.T) {
+	newRequest := func(name string) *http.Request {
+		body, _ := json.Marshal(types.TagRequest{Name: name})
+		req := httptest.NewRequest("POST", "/tags", bytes.NewReader(body))
+		return req.WithContext(withUserID(req.Context(), 10))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockTagRepository)
+		handler := NewTagHandler(mockRepo)
+
+		mockRepo.On("CreateTag", mock.Anything, &models.Tag{UserID: 10, Name: "work"}).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.CreateTag(rr, newRequest("  work "))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		var resp types.TagResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, "work", resp.Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		mockRepo := new(MockTagRepository)
+		handler := NewTagHandler(mockRepo)
+
+		mockRepo.On("CreateTag", mock.Anything, mock.Anything).Return(repository.ErrTagExists).Once()
+
+		rr := httptest.NewRecorder()
+		handler.CreateTag(rr, newRequest("work"))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("Blank Name", func(t *testing.T) {
+		mockRepo := new(MockTagRepository)
+		handler := NewTagHandler(mockRepo)
+
+		rr := httptest.NewRecorder()
+		handler.CreateTag(rr, newRequest("   "))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockRepo.AssertNotCalled(t, "CreateTag")
+	})
+}
+
+func TestTagHandler_DeleteTag_NotFound(t *testing.T) {
+	mockRepo := new(MockTagRepository)
+	handler := NewTagHandler(mockRepo)
+
+	mockRepo.On("DeleteTag", mock.Anything, uint(10), uint(3)).Return(repository.ErrTagNotFound).Once()
+
+	req := httptest.NewRequest("DELETE", "/tags/3", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("tagID", "3")
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	rr := httptest.NewRecorder()
+
+	handler.DeleteTag(rr, req.WithContext(withUserID(ctx, 10)))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockRepo.AssertExpectations(t)
+}
@@ -5,22 +5,38 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
+	"time"
 	"to-do-list/internal/api/types"
 	"to-do-list/internal/middleware"
 	"to-do-list/internal/models"
+	"to-do-list/internal/recurrence"
 	"to-do-list/internal/repository"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 )
 
+// TaskPolicy - настраиваемые правила работы с задачами
+type TaskPolicy struct {
+	// RequireSubtasksCompleted запрещает завершать задачу, пока у неё есть открытые подзадачи
+	RequireSubtasksCompleted bool
+}
+
+// TaskHandler меняет задачи и в той же транзакции пишет события об этом в outbox. Аудит изменений,
+// вебхуки и уведомления получают их оттуда; audit нужен только для действий, которые не меняют саму задачу
 type TaskHandler struct {
-	repo repository.TaskRepository
+	repo     repository.TaskRepository
+	projects repository.ProjectRepository
+	series   repository.SeriesRepository
+	tx       repository.Transactor
+	events   EventStore
+	audit    AuditRecorder
+	policy   TaskPolicy
 }
 
-func NewTaskHandler(repo repository.TaskRepository) *TaskHandler {
-	return &TaskHandler{repo: repo}
+func NewTaskHandler(repo repository.TaskRepository, projects repository.ProjectRepository, series repository.SeriesRepository, transactor repository.Transactor, events EventStore, auditRecorder AuditRecorder, policy TaskPolicy) *TaskHandler {
+	return &TaskHandler{repo: repo, projects: projects, series: series, tx: transactor, events: events, audit: auditRecorder, policy: policy}
 }
 
 func getUserIDFromCtx(ctx context.Context) (uint, error) {
@@ -31,6 +47,124 @@ func getUserIDFromCtx(ctx context.Context) (uint, error) {
 	return userID, nil
 }
 
+// parsePagination читает limit/offset, некорректные значения заменяются значениями по умолчанию
+func parsePagination(r *http.Request) (int, int) {
+	limitStr := r.URL.Query().Get("limit")
+	offsetStr := r.URL.Query().Get("offset")
+
+	limit := 10
+	offset := 0
+
+	if limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	if offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	return limit, offset
+}
+
+// parseTaskFilter разбирает фильтры и сортировку списка задач: ?project=1&archived=all&priority=high&tag=a&tag=b&tag_mode=all&sort=priority
+func parseTaskFilter(r *http.Request) (repository.TaskFilter, error) {
+	limit, offset := parsePagination(r)
+	filter := repository.TaskFilter{
+		Limit:  limit,
+		Offset: offset,
+		Sort:   repository.SortByCreatedAt,
+	}
+
+	// workspace=personal - только личные задачи
+	workspaceStr := r.URL.Query().Get("workspace")
+	switch workspaceStr {
+	case "":
+	case "personal":
+		personal := uint(0)
+		filter.WorkspaceID = &personal
+	default:
+		workspaceID, err := strconv.ParseUint(workspaceStr, 10, 32)
+		if err != nil || workspaceID == 0 {
+			return repository.TaskFilter{}, errors.New("Invalid workspace")
+		}
+		id := uint(workspaceID)
+		filter.WorkspaceID = &id
+	}
+
+	// assignee=me - задачи, назначенные на текущего пользователя
+	switch r.URL.Query().Get("assignee") {
+	case "":
+	case "me":
+		filter.AssignedToMe = true
+	default:
+		return repository.TaskFilter{}, errors.New("Invalid assignee")
+	}
+
+	// project=none - задачи без проекта
+	projectStr := r.URL.Query().Get("project")
+	switch projectStr {
+	case "":
+	case "none":
+		noProject := uint(0)
+		filter.ProjectID = &noProject
+	default:
+		projectID, err := strconv.ParseUint(projectStr, 10, 32)
+		if err != nil || projectID == 0 {
+			return repository.TaskFilter{}, errors.New("Invalid project")
+		}
+		id := uint(projectID)
+		filter.ProjectID = &id
+	}
+
+	// По умолчанию архивные задачи в список не попадают
+	notArchived := false
+	filter.Archived = &notArchived
+	switch r.URL.Query().Get("archived") {
+	case "", "false":
+	case "true":
+		archived := true
+		filter.Archived = &archived
+	case "all":
+		filter.Archived = nil
+	default:
+		return repository.TaskFilter{}, errors.New("Invalid archived")
+	}
+
+	priority := models.Priority(r.URL.Query().Get("priority"))
+	if priority != "" {
+		if !priority.IsValid() {
+			return repository.TaskFilter{}, errors.New("Invalid priority")
+		}
+		filter.Priority = priority
+	}
+
+	filter.Tags = models.NormalizeTagNames(r.URL.Query()["tag"])
+	tagMatch := repository.TagMatch(r.URL.Query().Get("tag_mode"))
+	switch tagMatch {
+	case "", repository.TagMatchAny:
+		filter.TagMatch = repository.TagMatchAny
+	case repository.TagMatchAll:
+		filter.TagMatch = repository.TagMatchAll
+	default:
+		return repository.TaskFilter{}, errors.New("Invalid tag_mode")
+	}
+
+	sort := repository.TaskSort(r.URL.Query().Get("sort"))
+	switch sort {
+	case "":
+	case repository.SortByCreatedAt, repository.SortByPriority, repository.SortByDeadline:
+		filter.Sort = sort
+	default:
+		return repository.TaskFilter{}, errors.New("Invalid sort")
+	}
+
+	return filter, nil
+}
+
 func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 	userID, err := getUserIDFromCtx(r.Context())
 	if err != nil {
@@ -57,6 +191,12 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.createTask(w, r, userID, req, nil)
+}
+
+// createTask создаёт задачу из запроса. Если передан parent, задача становится его подзадачей,
+// по умолчанию попадает в тот же проект и всегда - в то же пространство
+func (h *TaskHandler) createTask(w http.ResponseWriter, r *http.Request, userID uint, req types.CreateTaskRequest, parent *models.Task) {
 	task, err := models.NewTask(req.Name, req.Description, req.Deadline)
 	if err != nil {
 		render.Status(r, http.StatusBadRequest)
@@ -65,8 +205,73 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	task.UserID = userID
+	if req.Priority != "" {
+		task.Priority = req.Priority
+	}
+	task.Tags = models.NormalizeTagNames(req.Tags)
+
+	if req.ProjectID != nil && *req.ProjectID != 0 {
+		if !h.checkTargetProject(w, r, userID, *req.ProjectID) {
+			return
+		}
+		task.ProjectID = req.ProjectID
+	}
+
+	if req.WorkspaceID != nil && *req.WorkspaceID != 0 {
+		if parent == nil && !h.checkTargetWorkspace(w, r, userID, *req.WorkspaceID) {
+			return
+		}
+		task.WorkspaceID = req.WorkspaceID
+	}
 
-	err = h.repo.CreateTask(r.Context(), task)
+	if parent != nil {
+		if !sameWorkspace(task.WorkspaceID, parent.WorkspaceID) && req.WorkspaceID != nil {
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, map[string]string{"error": "Subtask must be in the parent's workspace"})
+			return
+		}
+		if !h.checkTargetParent(w, r, 0, *parent) {
+			return
+		}
+		task.ParentID = &parent.ID
+		task.WorkspaceID = parent.WorkspaceID
+		if req.ProjectID == nil {
+			task.ProjectID = parent.ProjectID
+		}
+	}
+
+	var series *models.TaskSeries
+	if req.Recurrence != "" {
+		if parent != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, map[string]string{"error": "Subtasks can't be recurring"})
+			return
+		}
+
+		rule, err := recurrence.Parse(req.Recurrence)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, map[string]string{"error": err.Error()})
+			return
+		}
+
+		series = &models.TaskSeries{UserID: userID, Rule: rule.String(), Active: true}
+		task.Occurrence = 1
+	}
+
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		var err error
+		if series != nil {
+			err = h.series.CreateSeriesTask(ctx, series, task)
+		} else {
+			err = h.repo.CreateTask(ctx, task)
+		}
+		if err != nil {
+			return err
+		}
+
+		return h.events.AppendEvents(ctx, stampEvents(r, userID, models.NewTaskEvent(models.EventTaskCreated, nil, task))...)
+	})
 	if err != nil {
 		slog.Error("Failed to create task", slog.Any("error", err))
 		render.Status(r, http.StatusInternalServerError)
@@ -75,7 +280,7 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	render.Status(r, http.StatusCreated)
-	render.JSON(w, r, task)
+	render.JSON(w, r, types.NewTaskResponse(*task))
 }
 
 func (h *TaskHandler) GetUserTasks(w http.ResponseWriter, r *http.Request) {
@@ -87,26 +292,14 @@ func (h *TaskHandler) GetUserTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	limit := 10 
-	offset := 0 
-
-	if limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
-			limit = parsedLimit
-		}
-	}
-
-	if offsetStr != "" {
-		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
-		}
+	filter, err := parseTaskFilter(r)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
 	}
 
-	tasks, err := h.repo.GetTasksByUserID(r.Context(), userID, limit, offset)
+	tasks, err := h.repo.GetTasksByUserID(r.Context(), userID, filter)
 	if err != nil {
 		slog.Error("Failed to get user tasks", slog.Any("error", err))
 
@@ -116,10 +309,19 @@ func (h *TaskHandler) GetUserTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	render.JSON(w, r, tasks)
+	render.JSON(w, r, types.NewTaskResponses(tasks))
 }
 
 func (h *TaskHandler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.loadTask(w, r, models.AccessView)
+	if !ok {
+		return
+	}
+
+	render.JSON(w, r, types.NewTaskResponse(task))
+}
+
+func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	userID, err := getUserIDFromCtx(r.Context())
 	if err != nil {
 		slog.Error("Failed to get user ID from context", slog.Any("error", err))
@@ -130,127 +332,250 @@ func (h *TaskHandler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	taskIDStr := chi.URLParam(r, "taskID")
-	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	task, ok := h.loadTask(w, r, models.AccessEdit)
+	if !ok {
+		return
+	}
+
+	var req types.UpdateTaskRequest
+	err = render.DecodeJSON(r.Body, &req)
 	if err != nil {
+		slog.Error("Failed to decode update request body", slog.Any("error", err))
+
 		render.Status(r, http.StatusBadRequest)
-		render.JSON(w, r, map[string]string{"error": "Invalid task ID"})
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
 		return
 	}
 
-	task, err := h.repo.GetTaskByID(r.Context(), uint(taskID))
+	err = validate.Struct(req)
 	if err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Task not found"})
+		slog.Error("Update validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
 		return
 	}
 
-	if task.UserID != userID {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Task not found"})
+	// Статус проверяем до любых изменений, чтобы недопустимый переход не оставил задачу обновлённой наполовину
+	if req.Status != nil && *req.Status == task.Status {
+		req.Status = nil
+	}
+	if req.Status != nil && !task.Status.CanTransitionTo(*req.Status) {
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "Status transition from '" + string(task.Status) + "' to '" + string(*req.Status) + "' is not allowed"})
+
 		return
 	}
 
-	render.JSON(w, r, task)
-}
+	if req.Status != nil && *req.Status == models.StatusCompleted && h.policy.RequireSubtasksCompleted {
+		open, err := h.repo.CountOpenSubtasks(r.Context(), task.ID)
+		if err != nil {
+			slog.Error("Failed to count open subtasks", slog.Any("error", err))
 
-func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
-	userID, err := getUserIDFromCtx(r.Context())
-	if err != nil {
-		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Internal server error"})
 
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+			return
+		}
+		if open > 0 {
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, map[string]string{"error": "Task has open subtasks"})
 
-		return
+			return
+		}
 	}
 
-	taskIDStr := chi.URLParam(r, "taskID")
-	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
-	if err != nil {
-		slog.Error("Failed to parse task ID", slog.Any("error", err))
+	if req.Status != nil && *req.Status == models.StatusInProgress {
+		blockers, err := h.repo.CountOpenDependencies(r.Context(), task.ID)
+		if err != nil {
+			slog.Error("Failed to count open dependencies", slog.Any("error", err))
 
-		render.Status(r, http.StatusBadRequest)
-		render.JSON(w, r, map[string]string{"error": "Invalid task ID"})
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Internal server error"})
 
-		return
-	}
+			return
+		}
+		if blockers > 0 {
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, map[string]string{"error": "Task is blocked by unfinished tasks"})
 
-	task, err := h.repo.GetTaskByID(r.Context(), uint(taskID))
-	if err != nil {
-		slog.Error("Failed to get task by ID", slog.Any("error", err))
+			return
+		}
+	}
 
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Task not found"})
+	// Проекты и родители принадлежат владельцу, поэтому переносить задачу может только он
+	if (req.ProjectID != nil || req.ParentID != nil || req.WorkspaceID != nil) && task.UserID != userID {
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, map[string]string{"error": "Only the task owner can move it"})
 
 		return
 	}
 
-	if task.UserID != userID {
-		slog.Error("User attempted to update another user's task", slog.Any("taskID", taskID), slog.Any("userID", userID))
+	var projectID *uint
+	if req.ProjectID != nil && *req.ProjectID != 0 {
+		if !h.checkTargetProject(w, r, userID, *req.ProjectID) {
+			return
+		}
+		projectID = req.ProjectID
+	}
 
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Task not found"})
+	// Подзадача всегда живёт в пространстве родителя, переносится только корень вместе с поддеревом
+	var workspaceID *uint
+	if req.WorkspaceID != nil {
+		subtask := task.ParentID != nil
+		if req.ParentID != nil {
+			subtask = *req.ParentID != 0
+		}
+		if subtask {
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, map[string]string{"error": "Subtask must be in the parent's workspace"})
 
-		return
+			return
+		}
+		if *req.WorkspaceID != 0 {
+			if !h.checkTargetWorkspace(w, r, userID, *req.WorkspaceID) {
+				return
+			}
+			workspaceID = req.WorkspaceID
+		}
 	}
 
-	var req types.UpdateTaskRequest
-	err = render.DecodeJSON(r.Body, &req)
-	if err != nil {
-		slog.Error("Failed to decode update request body", slog.Any("error", err))
+	var parentID *uint
+	if req.ParentID != nil && *req.ParentID != 0 {
+		parent, ok := h.getTargetParent(w, r, userID, *req.ParentID)
+		if !ok || !h.checkTargetParent(w, r, task.ID, parent) {
+			return
+		}
+		if !sameWorkspace(task.WorkspaceID, parent.WorkspaceID) {
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, map[string]string{"error": "Subtask must be in the parent's workspace"})
+
+			return
+		}
+		parentID = req.ParentID
+	}
 
+	if req.Deadline != nil && req.Deadline.Before(time.Now()) {
 		render.Status(r, http.StatusBadRequest)
-		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+		render.JSON(w, r, map[string]string{"error": "deadline can't be earlier than current time"})
 
 		return
 	}
 
-	err = validate.Struct(req)
+	var updated models.Task
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		var err error
+		updated, err = h.applyTaskUpdate(ctx, task, req, projectID, parentID, workspaceID)
+		if err != nil {
+			return err
+		}
+		err = h.recordTaskUpdate(ctx, r, userID, task, updated)
+		if err != nil {
+			return err
+		}
+		err = h.recordRevision(ctx, userID, task, updated, nil)
+		if err != nil {
+			return err
+		}
+
+		if req.Status != nil && updated.Status == models.StatusCompleted {
+			return h.spawnNextOccurrence(ctx, updated)
+		}
+		return nil
+	})
+	if errors.Is(err, repository.ErrInvalidStatusTransition) {
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "Task status was changed concurrently, transition is not allowed"})
+
+		return
+	}
 	if err != nil {
-		slog.Error("Update validation failed", slog.Any("error", err))
+		slog.Error("Failed to update task", slog.Any("error", err))
 
-		render.Status(r, http.StatusBadRequest)
-		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to update task"})
 
 		return
 	}
 
-	if req.Name != nil {
-		err = h.repo.UpdateTaskName(r.Context(), uint(taskID), *req.Name)
-		if err != nil {
-			slog.Error("Failed to update task name", slog.Any("error", err))
+	w.WriteHeader(http.StatusNoContent)
+}
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, map[string]string{"error": "Failed to update task name"})
+// applyTaskUpdate записывает поля из запроса и возвращает новое состояние задачи. projectID, parentID
+// и workspaceID уже проверены вызывающим, nil в них при заданном поле запроса означает "убрать"
+func (h *TaskHandler) applyTaskUpdate(ctx context.Context, task models.Task, req types.UpdateTaskRequest, projectID, parentID, workspaceID *uint) (models.Task, error) {
+	updated := task
 
-			return
+	if req.Name != nil {
+		err := h.repo.UpdateTaskName(ctx, task.ID, *req.Name)
+		if err != nil {
+			return models.Task{}, err
 		}
+		updated.Name = *req.Name
 	}
 	if req.Description != nil {
-		err = h.repo.UpdateTaskDescription(r.Context(), uint(taskID), *req.Description)
+		err := h.repo.UpdateTaskDescription(ctx, task.ID, *req.Description)
 		if err != nil {
-			slog.Error("Failed to update task description", slog.Any("error", err))
-
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, map[string]string{"error": "Failed to update task description"})
-
-			return
+			return models.Task{}, err
 		}
+		updated.Description = *req.Description
+	}
+	if req.Deadline != nil {
+		err := h.repo.UpdateTaskDeadline(ctx, task.ID, *req.Deadline)
+		if err != nil {
+			return models.Task{}, err
+		}
+		updated.Deadline = *req.Deadline
 	}
 	if req.Status != nil {
-		err = h.repo.UpdateTaskStatus(r.Context(), uint(taskID), *req.Status)
+		err := h.repo.UpdateTaskStatus(ctx, task.ID, *req.Status)
 		if err != nil {
-			slog.Error("Failed to update task status", slog.Any("error", err))
-
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, map[string]string{"error": "Failed to update task status"})
+			return models.Task{}, err
+		}
+		updated.Status = *req.Status
+	}
+	if req.Priority != nil {
+		err := h.repo.UpdateTaskPriority(ctx, task.ID, *req.Priority)
+		if err != nil {
+			return models.Task{}, err
+		}
+		updated.Priority = *req.Priority
+	}
 
-			return
+	if req.ProjectID != nil {
+		err := h.repo.UpdateTaskProject(ctx, task.ID, projectID)
+		if err != nil {
+			return models.Task{}, err
+		}
+		updated.ProjectID = projectID
+	}
+	if req.ParentID != nil {
+		err := h.repo.UpdateTaskParent(ctx, task.ID, parentID)
+		if err != nil {
+			return models.Task{}, err
+		}
+		updated.ParentID = parentID
+	}
+	if req.WorkspaceID != nil {
+		err := h.repo.UpdateTaskWorkspace(ctx, task.ID, workspaceID)
+		if err != nil {
+			return models.Task{}, err
 		}
+		updated.WorkspaceID = workspaceID
+	}
+	if req.Tags != nil {
+		tags := models.NormalizeTagNames(*req.Tags)
+		err := h.repo.SetTaskTags(ctx, task.ID, task.UserID, tags)
+		if err != nil {
+			return models.Task{}, err
+		}
+		// В задаче из репозитория теги отсортированы, сортируем и здесь, чтобы в событие не попала перестановка
+		updated.Tags = slices.Sorted(slices.Values(tags))
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	return updated, nil
 }
 
 func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
@@ -264,45 +589,153 @@ func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	taskIDStr := chi.URLParam(r, "taskID")
-	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	task, ok := h.loadTask(w, r, models.AccessOwner)
+	if !ok {
+		return
+	}
+
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		err := h.repo.DeleteTask(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		return h.events.AppendEvents(ctx, stampEvents(r, userID, models.NewTaskEvent(models.EventTaskDeleted, &task, nil))...)
+	})
 	if err != nil {
-		slog.Error("Failed to parse task ID", slog.Any("error", err))
+		slog.Error("Failed to delete task", slog.Any("error", err))
 
-		render.Status(r, http.StatusBadRequest)
-		render.JSON(w, r, map[string]string{"error": "Invalid task ID"})
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to delete task"})
 
 		return
 	}
 
-	task, err := h.repo.GetTaskByID(r.Context(), uint(taskID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkTargetProject проверяет, что в проект можно положить задачу: он принадлежит пользователю и не в архиве
+func (h *TaskHandler) checkTargetProject(w http.ResponseWriter, r *http.Request, userID, projectID uint) bool {
+	project, err := getOwnedProject(r.Context(), h.projects, userID, projectID)
+	if errors.Is(err, repository.ErrProjectNotFound) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Project not found"})
+		return false
+	}
 	if err != nil {
-		slog.Error("Failed to get task by ID", slog.Any("error", err))
+		slog.Error("Failed to get project by ID", slog.Any("error", err))
 
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Task not found"})
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
 
-		return
+		return false
 	}
 
-	if task.UserID != userID {
-		slog.Error("User attempted to delete another user's task", slog.Any("taskID", taskID), slog.Any("userID", userID))
+	if project.Archived {
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "Project is archived"})
+		return false
+	}
 
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Task not found"})
+	return true
+}
 
-		return
+// checkTargetWorkspace проверяет, что пользователь состоит в пространстве, куда кладёт задачу
+func (h *TaskHandler) checkTargetWorkspace(w http.ResponseWriter, r *http.Request, userID, workspaceID uint) bool {
+	_, err := h.repo.GetWorkspaceRole(r.Context(), workspaceID, userID)
+	if errors.Is(err, repository.ErrNotWorkspaceMember) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Workspace not found"})
+		return false
+	}
+	if err != nil {
+		slog.Error("Failed to get workspace role", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return false
+	}
+
+	return true
+}
+
+func sameWorkspace(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
+	return *a == *b
+}
 
-	err = h.repo.DeleteTask(r.Context(), uint(taskID))
+// getTargetParent загружает будущего родителя задачи. Чужая задача выглядит как несуществующая
+func (h *TaskHandler) getTargetParent(w http.ResponseWriter, r *http.Request, userID, parentID uint) (models.Task, bool) {
+	parent, err := authorizeTask(r.Context(), h.repo, userID, parentID, models.AccessOwner)
 	if err != nil {
-		slog.Error("Failed to delete task", slog.Any("error", err))
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Parent task not found"})
+		return models.Task{}, false
+	}
+
+	return parent, true
+}
+
+// checkTargetParent проверяет, что задачу taskID можно вложить в parent без цикла и превышения глубины.
+// Для новой задачи taskID = 0
+func (h *TaskHandler) checkTargetParent(w http.ResponseWriter, r *http.Request, taskID uint, parent models.Task) bool {
+	ancestors, err := h.repo.GetTaskAncestorIDs(r.Context(), parent.ID)
+	if err != nil {
+		slog.Error("Failed to get task ancestors", slog.Any("error", err))
 
 		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete task"})
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
 
-		return
+		return false
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	height := 0
+	if taskID != 0 {
+		height, err = h.repo.GetSubtreeHeight(r.Context(), taskID)
+		if err != nil {
+			slog.Error("Failed to get subtree height", slog.Any("error", err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+			return false
+		}
+	}
+
+	err = models.ValidateParent(taskID, parent.ID, ancestors, height)
+	if err != nil {
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return false
+	}
+
+	return true
+}
+
+// loadTask достаёт задачу из URL, если у текущего пользователя есть доступ не ниже need
+func (h *TaskHandler) loadTask(w http.ResponseWriter, r *http.Request, need models.TaskAccess) (models.Task, bool) {
+	return loadAuthorizedTask(w, r, h.repo, need)
+}
+
+// spawnNextOccurrence создаёт следующий экземпляр повторяющейся задачи, событие о нём пишет сам репозиторий.
+// Вызывается внутри WithinTx вместе с завершением задачи: если экземпляр не создался, завершение тоже откатывается
+func (h *TaskHandler) spawnNextOccurrence(ctx context.Context, task models.Task) error {
+	_, err := recurrence.SpawnNext(ctx, h.series, task, time.Now())
+	return err
+}
+
+// recordTaskUpdate пишет события изменения задачи, если она действительно изменилась.
+// Вызывается внутри WithinTx вместе с самим изменением
+func (h *TaskHandler) recordTaskUpdate(ctx context.Context, r *http.Request, userID uint, before, after models.Task) error {
+	return h.events.AppendEvents(ctx, stampEvents(r, userID, models.TaskUpdateEvents(before, after)...)...)
+}
+
+// GetStatusTransitions отдаёт таблицу допустимых переходов, чтобы клиент не дублировал её у себя
+func (h *TaskHandler) GetStatusTransitions(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, types.StatusTransitionsResponse{
+		Transitions:       models.StatusTransitions(),
+		SystemTransitions: models.SystemStatusTransitions(),
+	})
 }
@@ -4,14 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"testing"
 	"time"
 	"to-do-list/internal/api/types"
 	"to-do-list/internal/middleware"
 	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -34,8 +35,13 @@ func (m *MockTaskRepository) GetTaskByID(ctx context.Context, id uint) (models.T
 	return args.Get(0).(models.Task), args.Error(1)
 }
 
-func (m *MockTaskRepository) GetTasksByUserID(ctx context.Context, userID uint, limit, offset int) ([]models.Task, error) {
-	args := m.Called(ctx, userID, limit, offset)
+func (m *MockTaskRepository) GetTasksWithAccess(ctx context.Context, userID uint, ids []uint) ([]models.AccessibleTask, error) {
+	args := m.Called(ctx, userID, ids)
+	return args.Get(0).([]models.AccessibleTask), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetTasksByUserID(ctx context.Context, userID uint, filter repository.TaskFilter) ([]models.Task, error) {
+	args := m.Called(ctx, userID, filter)
 	return args.Get(0).([]models.Task), args.Error(1)
 }
 func (m *MockTaskRepository) UpdateTaskName(ctx context.Context, id uint, name string) error {
@@ -50,6 +56,134 @@ func (m *MockTaskRepository) UpdateTaskStatus(ctx context.Context, id uint, stat
 	args := m.Called(ctx, id, status)
 	return args.Error(0)
 }
+func (m *MockTaskRepository) UpdateTaskPriority(ctx context.Context, id uint, priority models.Priority) error {
+	args := m.Called(ctx, id, priority)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) UpdateTaskDeadline(ctx context.Context, id uint, deadline time.Time) error {
+	args := m.Called(ctx, id, deadline)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) SetTaskTags(ctx context.Context, taskID, userID uint, tags []string) error {
+	args := m.Called(ctx, taskID, userID, tags)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) UpdateTaskProject(ctx context.Context, id uint, projectID *uint) error {
+	args := m.Called(ctx, id, projectID)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) UpdateTaskParent(ctx context.Context, id uint, parentID *uint) error {
+	args := m.Called(ctx, id, parentID)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) GetSubtasks(ctx context.Context, parentID uint) ([]models.Task, error) {
+	args := m.Called(ctx, parentID)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+func (m *MockTaskRepository) GetTaskAncestorIDs(ctx context.Context, id uint) ([]uint, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]uint), args.Error(1)
+}
+func (m *MockTaskRepository) GetSubtreeHeight(ctx context.Context, id uint) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+func (m *MockTaskRepository) CountOpenSubtasks(ctx context.Context, id uint) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+func (m *MockTaskRepository) AddTaskDependency(ctx context.Context, userID, taskID, dependsOnID uint) error {
+	args := m.Called(ctx, userID, taskID, dependsOnID)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) RemoveTaskDependency(ctx context.Context, taskID, dependsOnID uint) error {
+	args := m.Called(ctx, taskID, dependsOnID)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) CountOpenDependencies(ctx context.Context, id uint) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+func (m *MockTaskRepository) GetTrashedTasks(ctx context.Context, userID uint, limit, offset int) ([]models.Task, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+func (m *MockTaskRepository) LockTask(ctx context.Context, id uint) (models.Task, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.Task), args.Error(1)
+}
+func (m *MockTaskRepository) RestoreTask(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) PurgeTask(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) GetTrashedTask(ctx context.Context, id uint) (models.Task, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.Task), args.Error(1)
+}
+func (m *MockTaskRepository) EmptyTrash(ctx context.Context, userID uint) ([]models.Task, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+func (m *MockTaskRepository) SetTaskArchived(ctx context.Context, id uint, archived bool) error {
+	args := m.Called(ctx, id, archived)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) ArchiveCompletedTasks(ctx context.Context, userID uint) ([]models.Task, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+func (m *MockTaskRepository) LockProjectTasks(ctx context.Context, projectID uint) ([]models.Task, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+func (m *MockTaskRepository) CreateTaskRevision(ctx context.Context, revision *models.TaskRevision) error {
+	args := m.Called(ctx, revision)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) GetTaskRevisions(ctx context.Context, taskID uint, limit, offset int) ([]models.TaskRevision, error) {
+	args := m.Called(ctx, taskID, limit, offset)
+	return args.Get(0).([]models.TaskRevision), args.Error(1)
+}
+func (m *MockTaskRepository) GetTaskRevisionsAfter(ctx context.Context, taskID, revisionID uint) ([]models.TaskRevision, error) {
+	args := m.Called(ctx, taskID, revisionID)
+	return args.Get(0).([]models.TaskRevision), args.Error(1)
+}
+func (m *MockTaskRepository) ShareTask(ctx context.Context, taskID, ownerID uint, login string, permission models.SharePermission) (models.TaskShare, bool, error) {
+	args := m.Called(ctx, taskID, ownerID, login, permission)
+	return args.Get(0).(models.TaskShare), args.Bool(1), args.Error(2)
+}
+func (m *MockTaskRepository) GetTaskShares(ctx context.Context, taskID uint) ([]models.TaskShare, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]models.TaskShare), args.Error(1)
+}
+func (m *MockTaskRepository) RemoveTaskShare(ctx context.Context, taskID, userID uint) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) GetTaskSharePermission(ctx context.Context, taskID, userID uint) (models.SharePermission, error) {
+	args := m.Called(ctx, taskID, userID)
+	return args.Get(0).(models.SharePermission), args.Error(1)
+}
+func (m *MockTaskRepository) GetSharedTasks(ctx context.Context, userID uint, limit, offset int) ([]models.SharedTask, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	return args.Get(0).([]models.SharedTask), args.Error(1)
+}
+func (m *MockTaskRepository) GetWorkspaceRole(ctx context.Context, workspaceID, userID uint) (models.WorkspaceRole, error) {
+	args := m.Called(ctx, workspaceID, userID)
+	return args.Get(0).(models.WorkspaceRole), args.Error(1)
+}
+func (m *MockTaskRepository) UpdateTaskWorkspace(ctx context.Context, id uint, workspaceID *uint) error {
+	args := m.Called(ctx, id, workspaceID)
+	return args.Error(0)
+}
+func (m *MockTaskRepository) UpdateTaskAssignee(ctx context.Context, id uint, assigneeID *uint) error {
+	args := m.Called(ctx, id, assigneeID)
+	return args.Error(0)
+}
 func (m *MockTaskRepository) DeleteTask(ctx context.Context, id uint) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -59,17 +193,38 @@ func withUserID(ctx context.Context, userID uint) context.Context {
 	return context.WithValue(ctx, middleware.UserIDKey, userID)
 }
 
+// taskJSONKeys - ключи задачи без необязательных полей, какими их видит клиент
+var taskJSONKeys = []string{"blockedBy", "blocks", "createdAt", "deadline", "description", "id", "name", "priority", "projectId", "status", "tags", "updatedAt", "userId"}
+
+func assertTaskJSONKeys(t *testing.T, raw []byte) {
+	t.Helper()
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(raw, &fields))
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	assert.Equal(t, taskJSONKeys, keys)
+	assert.JSONEq(t, "[]", string(fields["blockedBy"]))
+}
+
 func TestTaskHandler_CreateTask(t *testing.T) {
 	mockRepo := new(MockTaskRepository)
-	handler := NewTaskHandler(mockRepo)
+	eventStore := &fakeEventStore{}
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, eventStore, &fakeAuditRecorder{}, TaskPolicy{})
 
 	t.Run("Success", func(t *testing.T) {
-		mockRepo.On("CreateTask", mock.Anything, mock.AnythingOfType("*models.Task")).Return(nil).Once()
+		mockRepo.On("CreateTask", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+			return assert.ObjectsAreEqual([]string{"work", "home"}, task.Tags)
+		})).Return(nil).Once()
 
 		createReq := types.CreateTaskRequest{
 			Name:        "Test Task",
 			Description: "Test Description",
 			Deadline:    time.Now().Add(24 * time.Hour),
+			Tags:        []string{"work", " home", "work"},
 		}
 		body, _ := json.Marshal(createReq)
 		req := httptest.NewRequest("POST", "/tasks", bytes.NewReader(body))
@@ -79,11 +234,15 @@ func TestTaskHandler_CreateTask(t *testing.T) {
 		handler.CreateTask(rr, req.WithContext(ctx))
 
 		assert.Equal(t, http.StatusCreated, rr.Code)
-		var resp models.Task
+		assertTaskJSONKeys(t, rr.Body.Bytes())
+		var resp types.TaskResponse
 		err := json.Unmarshal(rr.Body.Bytes(), &resp)
 		require.NoError(t, err)
 		assert.Equal(t, createReq.Name, resp.Name)
 		assert.Equal(t, uint(1), resp.UserID)
+		assert.Equal(t, models.PriorityMedium, resp.Priority)
+		require.Len(t, eventStore.events, 1)
+		assert.Equal(t, models.EventTaskCreated, eventStore.events[0].Type)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -105,7 +264,7 @@ func TestTaskHandler_CreateTask(t *testing.T) {
 
 func TestTaskHandler_GetTaskByID(t *testing.T) {
 	mockRepo := new(MockTaskRepository)
-	handler := NewTaskHandler(mockRepo)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
 
 	t.Run("Success", func(t *testing.T) {
 		taskID := uint(1)
@@ -123,7 +282,8 @@ func TestTaskHandler_GetTaskByID(t *testing.T) {
 		handler.GetTaskByID(rr, req.WithContext(ctx))
 
 		assert.Equal(t, http.StatusOK, rr.Code)
-		var resp models.Task
+		assertTaskJSONKeys(t, rr.Body.Bytes())
+		var resp types.TaskResponse
 		err := json.Unmarshal(rr.Body.Bytes(), &resp)
 		require.NoError(t, err)
 		assert.Equal(t, mockTask.Name, resp.Name)
@@ -136,6 +296,7 @@ func TestTaskHandler_GetTaskByID(t *testing.T) {
 		requesterUserID := uint(20)
 		mockTask := models.Task{ID: taskID, UserID: ownerUserID, Name: "Not Your Task"}
 		mockRepo.On("GetTaskByID", mock.Anything, taskID).Return(mockTask, nil).Once()
+		mockRepo.On("GetTaskSharePermission", mock.Anything, taskID, requesterUserID).Return(models.SharePermission(""), repository.ErrShareNotFound).Once()
 
 		req := httptest.NewRequest("GET", "/tasks/1", nil)
 		rctx := chi.NewRouteContext()
@@ -153,7 +314,7 @@ func TestTaskHandler_GetTaskByID(t *testing.T) {
 	t.Run("Not Found", func(t *testing.T) {
 		taskID := uint(999)
 		userID := uint(10)
-		mockRepo.On("GetTaskByID", mock.Anything, taskID).Return(models.Task{}, errors.New("not found")).Once()
+		mockRepo.On("GetTaskByID", mock.Anything, taskID).Return(models.Task{}, repository.ErrTaskNotFound).Once()
 
 		req := httptest.NewRequest("GET", "/tasks/999", nil)
 		rctx := chi.NewRouteContext()
@@ -168,3 +329,211 @@ func TestTaskHandler_GetTaskByID(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestTaskHandler_GetUserTasks(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+	notArchived := false
+
+	t.Run("Priority Filter And Sort", func(t *testing.T) {
+		expectedFilter := repository.TaskFilter{
+			Archived: &notArchived,
+			Priority: models.PriorityUrgent,
+			Tags:     []string{},
+			TagMatch: repository.TagMatchAny,
+			Sort:     repository.SortByPriority,
+			Limit:    10,
+			Offset:   0,
+		}
+		tasks := []models.Task{{ID: 1, UserID: 10, Priority: models.PriorityUrgent}}
+		mockRepo.On("GetTasksByUserID", mock.Anything, uint(10), expectedFilter).Return(tasks, nil).Once()
+
+		req := httptest.NewRequest("GET", "/tasks?priority=urgent&sort=priority", nil)
+		rr := httptest.NewRecorder()
+		handler.GetUserTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var resp []json.RawMessage
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		require.Len(t, resp, 1)
+		assertTaskJSONKeys(t, resp[0])
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Tag Filter", func(t *testing.T) {
+		expectedFilter := repository.TaskFilter{
+			Archived: &notArchived,
+			Tags:     []string{"work", "urgent"},
+			TagMatch: repository.TagMatchAll,
+			Sort:     repository.SortByCreatedAt,
+			Limit:    10,
+		}
+		mockRepo.On("GetTasksByUserID", mock.Anything, uint(10), expectedFilter).Return([]models.Task{}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/tasks?tag=work&tag=urgent&tag=work&tag_mode=all", nil)
+		rr := httptest.NewRecorder()
+		handler.GetUserTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Archived Filter", func(t *testing.T) {
+		mockRepo.On("GetTasksByUserID", mock.Anything, uint(10), mock.MatchedBy(func(f repository.TaskFilter) bool {
+			return f.Archived != nil && *f.Archived
+		})).Return([]models.Task{}, nil).Once()
+		mockRepo.On("GetTasksByUserID", mock.Anything, uint(10), mock.MatchedBy(func(f repository.TaskFilter) bool {
+			return f.Archived == nil
+		})).Return([]models.Task{}, nil).Once()
+
+		for _, query := range []string{"archived=true", "archived=all"} {
+			req := httptest.NewRequest("GET", "/tasks?"+query, nil)
+			rr := httptest.NewRecorder()
+			handler.GetUserTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+		}
+		mockRepo.AssertExpectations(t)
+
+		req := httptest.NewRequest("GET", "/tasks?archived=maybe", nil)
+		rr := httptest.NewRecorder()
+		handler.GetUserTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Invalid Tag Mode", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/tasks?tag=work&tag_mode=some", nil)
+		rr := httptest.NewRecorder()
+		handler.GetUserTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Invalid Priority", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/tasks?priority=asap", nil)
+		rr := httptest.NewRecorder()
+		handler.GetUserTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Invalid Sort", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/tasks?sort=name", nil)
+		rr := httptest.NewRecorder()
+		handler.GetUserTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestTaskHandler_UpdateTask_StatusTransitions(t *testing.T) {
+	newRequest := func(userID uint, body types.UpdateTaskRequest) *http.Request {
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest("PATCH", "/tasks/1", bytes.NewReader(data))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("taskID", "1")
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		return req.WithContext(withUserID(ctx, userID))
+	}
+	status := func(s models.Status) *models.Status { return &s }
+
+	t.Run("Allowed Transition", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10, Status: models.StatusPending}, nil).Once()
+		mockRepo.On("CountOpenDependencies", mock.Anything, uint(1)).Return(0, nil).Once()
+		mockRepo.On("UpdateTaskStatus", mock.Anything, uint(1), models.StatusInProgress).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest(10, types.UpdateTaskRequest{Status: status(models.StatusInProgress)}))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Completed Task Cannot Be Reopened", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10, Status: models.StatusCompleted}, nil).Once()
+
+		name := "renamed"
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest(10, types.UpdateTaskRequest{Name: &name, Status: status(models.StatusPending)}))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		mockRepo.AssertNotCalled(t, "UpdateTaskName")
+		mockRepo.AssertNotCalled(t, "UpdateTaskStatus")
+	})
+
+	t.Run("Failed Cannot Be Set Manually", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10, Status: models.StatusInProgress}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest(10, types.UpdateTaskRequest{Status: status(models.StatusFailed)}))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		mockRepo.AssertNotCalled(t, "UpdateTaskStatus")
+	})
+
+	t.Run("Concurrent Change", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10, Status: models.StatusPending}, nil).Once()
+		mockRepo.On("UpdateTaskStatus", mock.Anything, uint(1), models.StatusCompleted).Return(repository.ErrInvalidStatusTransition).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest(10, types.UpdateTaskRequest{Status: status(models.StatusCompleted)}))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTaskHandler_UpdateTask_Deadline(t *testing.T) {
+	newRequest := func(body types.UpdateTaskRequest) *http.Request {
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest("PATCH", "/tasks/1", bytes.NewReader(data))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("taskID", "1")
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		return req.WithContext(withUserID(ctx, 10))
+	}
+
+	t.Run("Reopen Failed Task With New Deadline", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		deadline := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+		pending := models.StatusPending
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10, Status: models.StatusFailed}, nil).Once()
+		mockRepo.On("UpdateTaskDeadline", mock.Anything, uint(1), mock.MatchedBy(deadline.Equal)).Return(nil).Once()
+		mockRepo.On("UpdateTaskStatus", mock.Anything, uint(1), models.StatusPending).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest(types.UpdateTaskRequest{Deadline: &deadline, Status: &pending}))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Deadline In The Past", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+		deadline := time.Now().Add(-time.Hour)
+		mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10, Status: models.StatusPending}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.UpdateTask(rr, newRequest(types.UpdateTaskRequest{Deadline: &deadline}))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockRepo.AssertNotCalled(t, "UpdateTaskDeadline")
+	})
+}
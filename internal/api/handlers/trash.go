@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+func (h *TaskHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	tasks, err := h.repo.GetTrashedTasks(r.Context(), userID, limit, offset)
+	if err != nil {
+		slog.Error("Failed to get trashed tasks", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get trash"})
+
+		return
+	}
+
+	render.JSON(w, r, types.NewTaskResponses(tasks))
+}
+
+// RestoreTask возвращает задачу из корзины. Событие task.updated с пустым DeletedAt в After
+// пишется в той же транзакции, журнал аудита записывает его как восстановление
+func (h *TaskHandler) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	userID, taskID, ok := parseTrashRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var restored models.Task
+	err := h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		trashed, err := h.getTrashedTask(ctx, userID, taskID)
+		if err != nil {
+			return err
+		}
+
+		err = h.repo.RestoreTask(ctx, taskID)
+		if err != nil {
+			return err
+		}
+
+		restored, err = h.repo.GetTaskByID(ctx, taskID)
+		if err != nil {
+			return err
+		}
+
+		return h.events.AppendEvents(ctx, stampEvents(r, userID, models.NewTaskEvent(models.EventTaskUpdated, &trashed, &restored))...)
+	})
+	if errors.Is(err, repository.ErrTaskNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Task not found in trash"})
+		return
+	}
+	if errors.Is(err, errTaskAccessDenied) {
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, map[string]string{"error": "Not enough permissions for this task"})
+		return
+	}
+	if errors.Is(err, repository.ErrParentInTrash) {
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "Parent task is in trash, restore it first"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to restore task", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to restore task"})
+
+		return
+	}
+
+	render.JSON(w, r, types.NewTaskResponse(restored))
+}
+
+// PurgeTask окончательно удаляет задачу из корзины. Событие task.deleted отличается от удаления
+// в корзину заполненным DeletedAt в Before
+func (h *TaskHandler) PurgeTask(w http.ResponseWriter, r *http.Request) {
+	userID, taskID, ok := parseTrashRequest(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		trashed, err := h.getTrashedTask(ctx, userID, taskID)
+		if err != nil {
+			return err
+		}
+
+		err = h.repo.PurgeTask(ctx, taskID)
+		if err != nil {
+			return err
+		}
+
+		return h.events.AppendEvents(ctx, stampEvents(r, userID, models.NewTaskEvent(models.EventTaskDeleted, &trashed, nil))...)
+	})
+	if errors.Is(err, repository.ErrTaskNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Task not found in trash"})
+		return
+	}
+	if errors.Is(err, errTaskAccessDenied) {
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, map[string]string{"error": "Not enough permissions for this task"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to purge task", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to purge task"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) EmptyTrash(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		purged, err := h.repo.EmptyTrash(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		events := make([]models.DomainEvent, 0, len(purged))
+		for i := range purged {
+			events = append(events, models.NewTaskEvent(models.EventTaskDeleted, &purged[i], nil))
+		}
+		return h.events.AppendEvents(ctx, stampEvents(r, userID, events...)...)
+	})
+	if err != nil {
+		slog.Error("Failed to empty trash", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to empty trash"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getTrashedTask загружает задачу из корзины и, как DeleteTask, требует доступа владельца: задачу,
+// удалённую администратором пространства, он же и восстановит. Недоступная задача выглядит отсутствующей
+func (h *TaskHandler) getTrashedTask(ctx context.Context, userID, taskID uint) (models.Task, error) {
+	task, err := h.repo.GetTrashedTask(ctx, taskID)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	access, err := taskAccess(ctx, h.repo, task, userID)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if access == models.AccessNone {
+		return models.Task{}, repository.ErrTaskNotFound
+	}
+	if access < models.AccessOwner {
+		return models.Task{}, errTaskAccessDenied
+	}
+
+	return task, nil
+}
+
+// parseTrashRequest читает пользователя и id задачи из запроса к корзине
+func parseTrashRequest(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return 0, 0, false
+	}
+
+	taskID, err := strconv.ParseUint(chi.URLParam(r, "taskID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid task ID"})
+		return 0, 0, false
+	}
+
+	return userID, uint(taskID), true
+}
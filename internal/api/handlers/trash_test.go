@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskHandler_GetTrash(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	deletedAt := time.Now()
+	trashed := []models.Task{{ID: 3, UserID: 10, DeletedAt: &deletedAt}}
+	mockRepo.On("GetTrashedTasks", mock.Anything, uint(10), 10, 0).Return(trashed, nil).Once()
+
+	req := httptest.NewRequest("GET", "/tasks/trash", nil)
+	rr := httptest.NewRecorder()
+	handler.GetTrash(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var got []types.TaskResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	require.Len(t, got, 1)
+	assert.NotNil(t, got[0].DeletedAt)
+}
+
+func TestTaskHandler_RestoreTask(t *testing.T) {
+	deletedAt := time.Now()
+	trashed := models.Task{ID: 1, UserID: 10, DeletedAt: &deletedAt}
+
+	testCases := []struct {
+		name         string
+		trashed      models.Task
+		getErr       error
+		repoErr      error
+		expectedCode int
+	}{
+		{"Success", trashed, nil, nil, http.StatusOK},
+		{"Not In Trash", models.Task{}, repository.ErrTaskNotFound, nil, http.StatusNotFound},
+		{"Another User Task", models.Task{ID: 1, UserID: 99, DeletedAt: &deletedAt}, nil, nil, http.StatusNotFound},
+		{"Parent In Trash", trashed, nil, repository.ErrParentInTrash, http.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			eventStore := &fakeEventStore{}
+			handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, eventStore, &fakeAuditRecorder{}, TaskPolicy{})
+
+			mockRepo.On("GetTrashedTask", mock.Anything, uint(1)).Return(tc.trashed, tc.getErr).Once()
+			mockRepo.On("GetTaskSharePermission", mock.Anything, uint(1), uint(10)).Return(models.SharePermission(""), repository.ErrShareNotFound).Maybe()
+			mockRepo.On("RestoreTask", mock.Anything, uint(1)).Return(tc.repoErr).Maybe()
+			mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10}, nil).Maybe()
+
+			rr := httptest.NewRecorder()
+			handler.RestoreTask(rr, withTaskID(httptest.NewRequest("POST", "/tasks/1/restore", nil), "1", 10))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode != http.StatusOK {
+				assert.Empty(t, eventStore.events)
+				return
+			}
+
+			require.Equal(t, []models.EventType{models.EventTaskUpdated}, eventStore.eventTypes())
+			payload, err := eventStore.events[0].TaskPayload()
+			require.NoError(t, err)
+			assert.NotNil(t, payload.Before.DeletedAt)
+			assert.Nil(t, payload.After.DeletedAt)
+		})
+	}
+}
+
+func TestTaskHandler_PurgeTask(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	eventStore := &fakeEventStore{}
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, eventStore, &fakeAuditRecorder{}, TaskPolicy{})
+
+	deletedAt := time.Now()
+	mockRepo.On("GetTrashedTask", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 10, DeletedAt: &deletedAt}, nil).Once()
+	mockRepo.On("PurgeTask", mock.Anything, uint(1)).Return(nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.PurgeTask(rr, withTaskID(httptest.NewRequest("DELETE", "/tasks/trash/1", nil), "1", 10))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, []models.EventType{models.EventTaskDeleted}, eventStore.eventTypes())
+}
+
+func TestTaskHandler_PurgeTask_NotInTrash(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	mockRepo.On("GetTrashedTask", mock.Anything, uint(1)).Return(models.Task{}, repository.ErrTaskNotFound).Once()
+
+	rr := httptest.NewRecorder()
+	handler.PurgeTask(rr, withTaskID(httptest.NewRequest("DELETE", "/tasks/trash/1", nil), "1", 10))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockRepo.AssertNotCalled(t, "PurgeTask", mock.Anything, mock.Anything)
+}
+
+// Администратор пространства может удалить задачу участника, значит, может и вернуть её из корзины
+func TestTaskHandler_WorkspaceAdminDeletesAndRestoresMemberTask(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	eventStore := &fakeEventStore{}
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, eventStore, &fakeAuditRecorder{}, TaskPolicy{})
+
+	workspaceID := uint(7)
+	deletedAt := time.Now()
+	task := models.Task{ID: 1, UserID: 20, WorkspaceID: &workspaceID}
+	trashed := task
+	trashed.DeletedAt = &deletedAt
+
+	mockRepo.On("GetWorkspaceRole", mock.Anything, workspaceID, uint(10)).Return(models.WorkspaceRoleAdmin, nil)
+	mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil)
+	mockRepo.On("DeleteTask", mock.Anything, uint(1)).Return(nil).Once()
+	mockRepo.On("GetTrashedTask", mock.Anything, uint(1)).Return(trashed, nil).Once()
+	mockRepo.On("RestoreTask", mock.Anything, uint(1)).Return(nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.DeleteTask(rr, withTaskID(httptest.NewRequest("DELETE", "/tasks/1", nil), "1", 10))
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.RestoreTask(rr, withTaskID(httptest.NewRequest("POST", "/tasks/1/restore", nil), "1", 10))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	assert.Equal(t, []models.EventType{models.EventTaskDeleted, models.EventTaskUpdated}, eventStore.eventTypes())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskHandler_RestoreTask_WorkspaceMemberForbidden(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	workspaceID := uint(7)
+	deletedAt := time.Now()
+	mockRepo.On("GetTrashedTask", mock.Anything, uint(1)).Return(models.Task{ID: 1, UserID: 20, WorkspaceID: &workspaceID, DeletedAt: &deletedAt}, nil).Once()
+	mockRepo.On("GetWorkspaceRole", mock.Anything, workspaceID, uint(10)).Return(models.WorkspaceRoleMember, nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.RestoreTask(rr, withTaskID(httptest.NewRequest("POST", "/tasks/1/restore", nil), "1", 10))
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	mockRepo.AssertNotCalled(t, "RestoreTask", mock.Anything, mock.Anything)
+}
+
+func TestTaskHandler_EmptyTrash(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	eventStore := &fakeEventStore{}
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, eventStore, &fakeAuditRecorder{}, TaskPolicy{})
+
+	deletedAt := time.Now()
+	mockRepo.On("EmptyTrash", mock.Anything, uint(10)).Return([]models.Task{
+		{ID: 1, UserID: 10, DeletedAt: &deletedAt},
+		{ID: 2, UserID: 10, DeletedAt: &deletedAt},
+	}, nil).Once()
+
+	req := httptest.NewRequest("DELETE", "/tasks/trash", nil)
+	rr := httptest.NewRecorder()
+	handler.EmptyTrash(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, []models.EventType{models.EventTaskDeleted, models.EventTaskDeleted}, eventStore.eventTypes())
+}
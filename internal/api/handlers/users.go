@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"log/slog"
+	"net"
 	"net/http"
+	"time"
 	"to-do-list/internal/api/types"
+	"to-do-list/internal/audit"
 	"to-do-list/internal/auth"
 	"to-do-list/internal/models"
 	"to-do-list/internal/repository"
@@ -17,16 +21,55 @@ var validate = validator.New()
 
 type UserHandler struct {
 	repo         repository.UserRepository
+	sessions     repository.SessionRepository
 	tokenManager *auth.TokenManager
+	tx           repository.Transactor
+	events       EventStore
+	audit        AuditRecorder
 }
 
-func NewUserHandler(repo repository.UserRepository, tm *auth.TokenManager) *UserHandler {
+func NewUserHandler(repo repository.UserRepository, sessions repository.SessionRepository, tm *auth.TokenManager, transactor repository.Transactor, events EventStore, auditRecorder AuditRecorder) *UserHandler {
 	return &UserHandler{
 		repo:         repo,
+		sessions:     sessions,
 		tokenManager: tm,
+		tx:           transactor,
+		events:       events,
+		audit:        auditRecorder,
 	}
 }
 
+// issueToken выдаёт токен и записывает сессию, к которой он привязан
+func (h *UserHandler) issueToken(r *http.Request, user models.User) (string, error) {
+	tokenID, err := auth.NewTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	session := &models.Session{
+		UserID:    user.ID,
+		TokenID:   tokenID,
+		UserAgent: r.UserAgent(),
+		IP:        clientIP(r),
+		ExpiresAt: time.Now().Add(h.tokenManager.TokenDuration()),
+	}
+
+	err = h.sessions.CreateSession(r.Context(), session)
+	if err != nil {
+		return "", err
+	}
+
+	return h.tokenManager.GenerateTokenWithID(user, tokenID)
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req types.RegisterRequest
 	err := render.DecodeJSON(r.Body, &req)
@@ -59,7 +102,13 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.repo.CreateUser(r.Context(), user)
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		err := h.repo.CreateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+		return h.events.AppendEvents(ctx, stampEvents(r, user.ID, models.NewUserEvent(models.EventUserRegistered, *user))...)
+	})
 	if err != nil {
 		slog.Error("Failed to create user in repository", slog.Any("error", err))
 
@@ -69,7 +118,7 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.tokenManager.GenerateToken(*user)
+	token, err := h.issueToken(r, *user)
 	if err != nil {
 		slog.Error("Failed to generate token", slog.Any("error", err))
 
@@ -109,6 +158,8 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		slog.Error("User not found", slog.Any("error", err))
 
+		h.recordLoginFailure(r, nil, req.Email, "user_not_found")
+
 		render.Status(r, http.StatusUnauthorized)
 		render.JSON(w, r, map[string]string{"error": "Invalid credentials"})
 
@@ -119,13 +170,15 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		slog.Error("Invalid password", slog.Any("error", err))
 
+		h.recordLoginFailure(r, &user.ID, req.Email, "invalid_password")
+
 		render.Status(r, http.StatusUnauthorized)
 		render.JSON(w, r, map[string]string{"error": "Invalid credentials"})
 
 		return
 	}
 
-	token, err := h.tokenManager.GenerateToken(user)
+	token, err := h.issueToken(r, user)
 	if err != nil {
 		slog.Error("Failed to generate token", slog.Any("error", err))
 
@@ -135,6 +188,10 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	event := audit.NewEvent(r, models.AuditLoginSuccess)
+	event.UserID = &user.ID
+	h.audit.Record(r.Context(), event)
+
 	resp := types.AuthResponse{
 		User: types.UserResponse{
 			ID:        user.ID,
@@ -148,3 +205,89 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, resp)
 }
+
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	var req types.ChangePasswordRequest
+	err = render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+
+	user, err := h.repo.GetUserByID(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get user by ID", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	err = bcrypt.CompareHashAndPassword(user.Password, []byte(req.CurrentPassword))
+	if err != nil {
+		render.Status(r, http.StatusUnauthorized)
+		render.JSON(w, r, map[string]string{"error": "Invalid credentials"})
+
+		return
+	}
+
+	hashedPassword, err := models.HashPassword(req.NewPassword)
+	if err != nil {
+		slog.Error("Failed to hash password", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	err = h.tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		err := h.repo.UpdatePassword(ctx, userID, hashedPassword)
+		if err != nil {
+			return err
+		}
+		return h.events.AppendEvents(ctx, stampEvents(r, userID, models.NewUserEvent(models.EventUserPasswordChanged, user))...)
+	})
+	if err != nil {
+		slog.Error("Failed to update password", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) recordLoginFailure(r *http.Request, userID *uint, email, reason string) {
+	event := audit.NewEvent(r, models.AuditLoginFailure)
+	event.UserID = userID
+	event.Metadata = audit.Snapshot(map[string]string{"email": email, "reason": reason})
+	h.audit.Record(r.Context(), event)
+}
@@ -34,12 +34,50 @@ func (m *MockUserRepository) GetUserByEmail(ctx context.Context, email string) (
 	return args.Get(0).(models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetUserByID(ctx context.Context, id uint) (models.User, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id uint, passwordHash []byte) error {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Error(0)
+}
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) CreateSession(ctx context.Context, session *models.Session) error {
+	args := m.Called(ctx, session)
+	session.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) TouchSession(ctx context.Context, tokenID string) error {
+	args := m.Called(ctx, tokenID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetActiveSessionsByUserID(ctx context.Context, userID uint) ([]models.Session, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) RevokeSession(ctx context.Context, userID, id uint) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
 func TestUserHandler_Register_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	tokenManager := auth.NewTokenManager("test-secret", time.Minute*15)
-	handler := NewUserHandler(mockRepo, tokenManager)
+	mockSessions := new(MockSessionRepository)
+	eventStore := &fakeEventStore{}
+	handler := NewUserHandler(mockRepo, mockSessions, tokenManager, fakeTransactor{}, eventStore, &fakeAuditRecorder{})
 
 	mockRepo.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+	mockSessions.On("CreateSession", mock.Anything, mock.AnythingOfType("*models.Session")).Return(nil)
 
 	registerReq := types.RegisterRequest{
 		Username: "testuser",
@@ -63,14 +101,18 @@ func TestUserHandler_Register_Success(t *testing.T) {
 	assert.Equal(t, registerReq.Email, resp.User.Email)
 	assert.NotEmpty(t, resp.Token)
 	assert.Equal(t, uint(1), resp.User.ID)
+	require.Len(t, eventStore.events, 1)
+	assert.Equal(t, models.EventUserRegistered, eventStore.events[0].Type)
 
 	mockRepo.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
 }
 
 func TestUserHandler_Register_ValidationFailure(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	tokenManager := auth.NewTokenManager("test-secret", time.Minute*15)
-	handler := NewUserHandler(mockRepo, tokenManager)
+	mockSessions := new(MockSessionRepository)
+	handler := NewUserHandler(mockRepo, mockSessions, tokenManager, fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{})
 
 	registerReq := types.RegisterRequest{
 		Username: "testuser",
@@ -97,7 +139,8 @@ func TestUserHandler_Register_ValidationFailure(t *testing.T) {
 func TestUserHandler_Login_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	tokenManager := auth.NewTokenManager("test-secret", time.Minute*15)
-	handler := NewUserHandler(mockRepo, tokenManager)
+	mockSessions := new(MockSessionRepository)
+	handler := NewUserHandler(mockRepo, mockSessions, tokenManager, fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{})
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
 	require.NoError(t, err)
@@ -110,6 +153,9 @@ func TestUserHandler_Login_Success(t *testing.T) {
 		CreatedAt: time.Now(),
 	}
 	mockRepo.On("GetUserByEmail", mock.Anything, "test@example.com").Return(mockUser, nil)
+	mockSessions.On("CreateSession", mock.Anything, mock.MatchedBy(func(s *models.Session) bool {
+		return s.UserID == mockUser.ID && s.UserAgent == "test-agent" && s.IP == "192.0.2.1" && s.TokenID != ""
+	})).Return(nil)
 
 	loginReq := types.LoginRequest{
 		Email:    "test@example.com",
@@ -118,6 +164,7 @@ func TestUserHandler_Login_Success(t *testing.T) {
 	body, _ := json.Marshal(loginReq)
 	req := httptest.NewRequest("POST", "/login", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "test-agent")
 	rr := httptest.NewRecorder()
 
 	handler.Login(rr, req)
@@ -131,12 +178,14 @@ func TestUserHandler_Login_Success(t *testing.T) {
 	assert.Equal(t, mockUser.Username, resp.User.Username)
 	assert.NotEmpty(t, resp.Token)
 	mockRepo.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
 }
 
 func TestUserHandler_Login_InvalidPassword(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	tokenManager := auth.NewTokenManager("test-secret", time.Minute*15)
-	handler := NewUserHandler(mockRepo, tokenManager)
+	mockSessions := new(MockSessionRepository)
+	handler := NewUserHandler(mockRepo, mockSessions, tokenManager, fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{})
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
 	require.NoError(t, err)
@@ -166,7 +215,8 @@ func TestUserHandler_Login_InvalidPassword(t *testing.T) {
 func TestUserHandler_Login_UserNotFound(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	tokenManager := auth.NewTokenManager("test-secret", time.Minute*15)
-	handler := NewUserHandler(mockRepo, tokenManager)
+	mockSessions := new(MockSessionRepository)
+	handler := NewUserHandler(mockRepo, mockSessions, tokenManager, fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{})
 
 	mockRepo.On("GetUserByEmail", mock.Anything, "notfound@example.com").Return(models.User{}, assert.AnError)
 
@@ -184,3 +234,73 @@ func TestUserHandler_Login_UserNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, rr.Code)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestUserHandler_Login_InvalidPassword_Audited(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	tokenManager := auth.NewTokenManager("test-secret", time.Minute*15)
+	auditRecorder := &fakeAuditRecorder{}
+	handler := NewUserHandler(mockRepo, new(MockSessionRepository), tokenManager, fakeTransactor{}, &fakeEventStore{}, auditRecorder)
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	mockRepo.On("GetUserByEmail", mock.Anything, "test@example.com").Return(models.User{ID: 7, Password: hashedPassword}, nil)
+
+	body, _ := json.Marshal(types.LoginRequest{Email: "test@example.com", Password: "wrongpassword"})
+	req := httptest.NewRequest("POST", "/login", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	handler.Login(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	require.Len(t, auditRecorder.events, 1)
+	event := auditRecorder.events[0]
+	assert.Equal(t, models.AuditLoginFailure, event.Action)
+	require.NotNil(t, event.UserID)
+	assert.Equal(t, uint(7), *event.UserID)
+	assert.JSONEq(t, `{"email":"test@example.com","reason":"invalid_password"}`, string(event.Metadata))
+}
+
+func TestUserHandler_ChangePassword(t *testing.T) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	user := models.User{ID: 1, Password: hashedPassword}
+
+	newRequest := func(current, next string) *http.Request {
+		body, _ := json.Marshal(types.ChangePasswordRequest{CurrentPassword: current, NewPassword: next})
+		req := httptest.NewRequest("POST", "/users/me/password", bytes.NewReader(body))
+		return req.WithContext(withUserID(req.Context(), user.ID))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		eventStore := &fakeEventStore{}
+		handler := NewUserHandler(mockRepo, new(MockSessionRepository), auth.NewTokenManager("test-secret", time.Minute), fakeTransactor{}, eventStore, &fakeAuditRecorder{})
+
+		mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+		mockRepo.On("UpdatePassword", mock.Anything, user.ID, mock.MatchedBy(func(hash []byte) bool {
+			return bcrypt.CompareHashAndPassword(hash, []byte("newpassword123")) == nil
+		})).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.ChangePassword(rr, newRequest("password123", "newpassword123"))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		require.Len(t, eventStore.events, 1)
+		assert.Equal(t, models.EventUserPasswordChanged, eventStore.events[0].Type)
+		assert.Equal(t, user.ID, eventStore.events[0].AggregateID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Wrong Current Password", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		handler := NewUserHandler(mockRepo, new(MockSessionRepository), auth.NewTokenManager("test-secret", time.Minute), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{})
+
+		mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.ChangePassword(rr, newRequest("wrong", "newpassword123"))
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockRepo.AssertNotCalled(t, "UpdatePassword")
+	})
+}
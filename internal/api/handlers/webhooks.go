@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+	"to-do-list/internal/webhook"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// WebhookURLChecker проверяет адрес вебхука перед сохранением, см. webhook.Guard
+type WebhookURLChecker interface {
+	CheckURL(ctx context.Context, raw string) error
+}
+
+type WebhookHandler struct {
+	repo repository.WebhookRepository
+	urls WebhookURLChecker
+}
+
+func NewWebhookHandler(repo repository.WebhookRepository, urls WebhookURLChecker) *WebhookHandler {
+	return &WebhookHandler{repo: repo, urls: urls}
+}
+
+func (h *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	webhooks, err := h.repo.GetWebhooksByUserID(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get webhooks", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get webhooks"})
+
+		return
+	}
+
+	resp := make([]types.WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		resp = append(resp, toWebhookResponse(webhook))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// CreateWebhook регистрирует адрес для событий задач пользователя. Секрет для проверки подписи
+// генерируется сервером и показывается только в этом ответе
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	var req types.CreateWebhookRequest
+	err = render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+
+	if !h.checkURL(w, r, req.URL) {
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		slog.Error("Failed to generate webhook secret", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	hook := &models.Webhook{UserID: userID, URL: req.URL, Secret: secret, Events: req.Events, Active: true}
+	err = h.repo.CreateWebhook(r.Context(), hook)
+	if err != nil {
+		slog.Error("Failed to create webhook", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to create webhook"})
+
+		return
+	}
+
+	resp := toWebhookResponse(*hook)
+	resp.Secret = hook.Secret
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, ok := h.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	render.JSON(w, r, toWebhookResponse(hook))
+}
+
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, ok := h.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	var req types.UpdateWebhookRequest
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode update request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Update validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+
+	if req.URL != nil {
+		if !h.checkURL(w, r, *req.URL) {
+			return
+		}
+		hook.URL = *req.URL
+	}
+	if req.Events != nil {
+		hook.Events = *req.Events
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+
+	err = h.repo.UpdateWebhook(r.Context(), &hook)
+	if err != nil {
+		slog.Error("Failed to update webhook", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to update webhook"})
+
+		return
+	}
+
+	render.JSON(w, r, toWebhookResponse(hook))
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, ok := h.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.repo.DeleteWebhook(r.Context(), hook.ID)
+	if err != nil && !errors.Is(err, repository.ErrWebhookNotFound) {
+		slog.Error("Failed to delete webhook", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to delete webhook"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeliveries - журнал доставок вебхука, новые первыми
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	hook, ok := h.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	deliveries, err := h.repo.GetWebhookDeliveries(r.Context(), hook.ID, limit, offset)
+	if err != nil {
+		slog.Error("Failed to get webhook deliveries", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get deliveries"})
+
+		return
+	}
+
+	resp := make([]types.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		resp = append(resp, toWebhookDeliveryResponse(delivery))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// loadWebhook достаёт вебхук текущего пользователя из URL. Чужой вебхук выглядит несуществующим
+func (h *WebhookHandler) loadWebhook(w http.ResponseWriter, r *http.Request) (models.Webhook, bool) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.Webhook{}, false
+	}
+
+	webhookID, err := strconv.ParseUint(chi.URLParam(r, "webhookID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid webhook ID"})
+		return models.Webhook{}, false
+	}
+
+	hook, err := h.repo.GetWebhookByID(r.Context(), uint(webhookID))
+	if errors.Is(err, repository.ErrWebhookNotFound) || (err == nil && hook.UserID != userID) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Webhook not found"})
+		return models.Webhook{}, false
+	}
+	if err != nil {
+		slog.Error("Failed to get webhook by ID", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.Webhook{}, false
+	}
+
+	return hook, true
+}
+
+// checkURL отвечает 400, если адрес не http(s), не разрешается или ведёт во внутреннюю сеть
+func (h *WebhookHandler) checkURL(w http.ResponseWriter, r *http.Request, raw string) bool {
+	err := h.urls.CheckURL(r.Context(), raw)
+	if err == nil {
+		return true
+	}
+
+	msg := "Webhook URL must be http or https"
+	switch {
+	case errors.Is(err, webhook.ErrForbiddenAddress):
+		msg = "Webhook URL must point to a public address"
+	case errors.Is(err, webhook.ErrUnresolvableHost):
+		msg = "Webhook host can't be resolved"
+	}
+
+	render.Status(r, http.StatusBadRequest)
+	render.JSON(w, r, map[string]string{"error": msg})
+	return false
+}
+
+func toWebhookResponse(hook models.Webhook) types.WebhookResponse {
+	return types.WebhookResponse{
+		ID:        hook.ID,
+		URL:       hook.URL,
+		Events:    hook.Events,
+		Active:    hook.Active,
+		CreatedAt: hook.CreatedAt,
+		UpdatedAt: hook.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(delivery models.WebhookDelivery) types.WebhookDeliveryResponse {
+	resp := types.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		Event:          delivery.Event,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+	if delivery.Status == models.DeliveryPending {
+		resp.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return resp
+}
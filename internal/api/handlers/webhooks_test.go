@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/webhook"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	args := m.Called(ctx, webhook)
+	webhook.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetWebhookByID(ctx context.Context, id uint) (models.Webhook, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetWebhooksByUserID(ctx context.Context, userID uint) ([]models.Webhook, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	args := m.Called(ctx, webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) DeleteWebhook(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetWebhookDeliveries(ctx context.Context, webhookID uint, limit, offset int) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, limit, offset)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) EnqueueWebhookDeliveries(ctx context.Context, userID uint, event models.WebhookEvent, payload json.RawMessage) (int64, error) {
+	args := m.Called(ctx, userID, event, payload)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.DueWebhookDelivery, error) {
+	args := m.Called(ctx, limit, lease)
+	return args.Get(0).([]models.DueWebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) CompleteDelivery(ctx context.Context, id uint, statusCode int) error {
+	args := m.Called(ctx, id, statusCode)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) FailDelivery(ctx context.Context, id uint, statusCode *int, reason string, retryAt *time.Time) error {
+	args := m.Called(ctx, id, statusCode, reason, retryAt)
+	return args.Error(0)
+}
+
+func withWebhookID(req *http.Request, webhookID string, userID uint) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("webhookID", webhookID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(withUserID(ctx, userID))
+}
+
+func newTestGuard(t *testing.T) *webhook.Guard {
+	t.Helper()
+	guard, err := webhook.NewGuard(nil)
+	require.NoError(t, err)
+	return guard
+}
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	testCases := []struct {
+		name         string
+		req          types.CreateWebhookRequest
+		expectedCode int
+	}{
+		{"Success", types.CreateWebhookRequest{URL: "https://203.0.113.10/hook", Events: []models.WebhookEvent{models.WebhookTaskCreated, models.WebhookTaskStatusChanged}}, http.StatusCreated},
+		{"Unknown Event", types.CreateWebhookRequest{URL: "https://203.0.113.10/hook", Events: []models.WebhookEvent{"task.exploded"}}, http.StatusBadRequest},
+		{"No Events", types.CreateWebhookRequest{URL: "https://203.0.113.10/hook"}, http.StatusBadRequest},
+		{"Not HTTP", types.CreateWebhookRequest{URL: "ftp://ci.example.com/hook", Events: []models.WebhookEvent{models.WebhookTaskCreated}}, http.StatusBadRequest},
+		{"Loopback", types.CreateWebhookRequest{URL: "http://127.0.0.1:8080/admin", Events: []models.WebhookEvent{models.WebhookTaskCreated}}, http.StatusBadRequest},
+		{"Cloud Metadata", types.CreateWebhookRequest{URL: "http://169.254.169.254/latest/meta-data", Events: []models.WebhookEvent{models.WebhookTaskCreated}}, http.StatusBadRequest},
+		{"Private Network", types.CreateWebhookRequest{URL: "http://[fd00::1]/hook", Events: []models.WebhookEvent{models.WebhookTaskCreated}}, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockWebhookRepository)
+			handler := NewWebhookHandler(repo, newTestGuard(t))
+			repo.On("CreateWebhook", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			body, _ := json.Marshal(tc.req)
+			req := httptest.NewRequest("POST", "/webhooks", bytes.NewReader(body))
+			rr := httptest.NewRecorder()
+			handler.CreateWebhook(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode != http.StatusCreated {
+				repo.AssertNotCalled(t, "CreateWebhook", mock.Anything, mock.Anything)
+				return
+			}
+
+			var resp types.WebhookResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Len(t, resp.Secret, 64)
+			assert.True(t, resp.Active)
+			repo.AssertCalled(t, "CreateWebhook", mock.Anything, mock.MatchedBy(func(w *models.Webhook) bool {
+				return w.UserID == 10 && w.Secret == resp.Secret
+			}))
+		})
+	}
+}
+
+func TestWebhookHandler_UpdateWebhook_RejectsPrivateAddress(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	handler := NewWebhookHandler(repo, newTestGuard(t))
+	repo.On("GetWebhookByID", mock.Anything, uint(1)).Return(models.Webhook{ID: 1, UserID: 10, URL: "https://203.0.113.10/hook"}, nil)
+
+	body, _ := json.Marshal(map[string]string{"url": "http://10.0.0.5/internal"})
+	rr := httptest.NewRecorder()
+	handler.UpdateWebhook(rr, withWebhookID(httptest.NewRequest("PATCH", "/webhooks/1", bytes.NewReader(body)), "1", 10))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Webhook URL must point to a public address")
+	repo.AssertNotCalled(t, "UpdateWebhook", mock.Anything, mock.Anything)
+}
+
+func TestWebhookHandler_GetWebhook_HidesSecretAndForeignHooks(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	handler := NewWebhookHandler(repo, newTestGuard(t))
+	repo.On("GetWebhookByID", mock.Anything, uint(1)).Return(models.Webhook{ID: 1, UserID: 10, URL: "https://x.example", Secret: "top-secret"}, nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetWebhook(rr, withWebhookID(httptest.NewRequest("GET", "/webhooks/1", nil), "1", 10))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "top-secret")
+
+	rr = httptest.NewRecorder()
+	handler.GetWebhook(rr, withWebhookID(httptest.NewRequest("GET", "/webhooks/1", nil), "1", 20))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestWebhookHandler_GetDeliveries(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	handler := NewWebhookHandler(repo, newTestGuard(t))
+	code := http.StatusInternalServerError
+	reason := "unexpected status 500"
+
+	repo.On("GetWebhookByID", mock.Anything, uint(1)).Return(models.Webhook{ID: 1, UserID: 10}, nil).Once()
+	repo.On("GetWebhookDeliveries", mock.Anything, uint(1), 10, 0).Return([]models.WebhookDelivery{
+		{ID: 3, Event: models.WebhookTaskUpdated, Payload: json.RawMessage(`{}`), Status: models.DeliveryPending, Attempts: 2, LastStatusCode: &code, LastError: &reason},
+		{ID: 2, Event: models.WebhookTaskCreated, Payload: json.RawMessage(`{}`), Status: models.DeliveryDelivered, Attempts: 1},
+	}, nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.GetDeliveries(rr, withWebhookID(httptest.NewRequest("GET", "/webhooks/1/deliveries", nil), "1", 10))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp []types.WebhookDeliveryResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp, 2)
+	assert.NotNil(t, resp[0].NextAttemptAt)
+	assert.Equal(t, reason, *resp[0].LastError)
+	assert.Nil(t, resp[1].NextAttemptAt)
+	repo.AssertExpectations(t)
+}
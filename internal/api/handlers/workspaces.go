@@ -0,0 +1,662 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// WorkspacePolicy: InvitationTTL - сколько действует приглашение
+type WorkspacePolicy struct {
+	InvitationTTL time.Duration
+}
+
+type WorkspaceHandler struct {
+	repo   repository.WorkspaceRepository
+	tasks  repository.TaskRepository
+	policy WorkspacePolicy
+}
+
+func NewWorkspaceHandler(repo repository.WorkspaceRepository, tasks repository.TaskRepository, policy WorkspacePolicy) *WorkspaceHandler {
+	return &WorkspaceHandler{repo: repo, tasks: tasks, policy: policy}
+}
+
+func (h *WorkspaceHandler) GetWorkspaces(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	workspaces, err := h.repo.GetWorkspacesByUserID(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get user workspaces", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get workspaces"})
+
+		return
+	}
+
+	resp := make([]types.WorkspaceResponse, 0, len(workspaces))
+	for _, uw := range workspaces {
+		resp = append(resp, toWorkspaceResponse(uw.Workspace, uw.Role))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (h *WorkspaceHandler) CreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	var req types.CreateWorkspaceRequest
+	err = render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+
+	workspace := &models.Workspace{Name: req.Name, OwnerID: userID}
+	err = h.repo.CreateWorkspace(r.Context(), workspace)
+	if err != nil {
+		slog.Error("Failed to create workspace", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to create workspace"})
+
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, toWorkspaceResponse(*workspace, models.WorkspaceRoleOwner))
+}
+
+func (h *WorkspaceHandler) GetWorkspace(w http.ResponseWriter, r *http.Request) {
+	workspace, role, ok := h.loadWorkspace(w, r, models.WorkspaceRoleMember)
+	if !ok {
+		return
+	}
+
+	render.JSON(w, r, toWorkspaceResponse(workspace, role))
+}
+
+func (h *WorkspaceHandler) UpdateWorkspace(w http.ResponseWriter, r *http.Request) {
+	workspace, role, ok := h.loadWorkspace(w, r, models.WorkspaceRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req types.UpdateWorkspaceRequest
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode update request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Update validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+
+	if req.Name != nil {
+		err = h.repo.UpdateWorkspaceName(r.Context(), workspace.ID, *req.Name)
+		if err != nil {
+			slog.Error("Failed to update workspace", slog.Any("error", err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Failed to update workspace"})
+
+			return
+		}
+		workspace.Name = *req.Name
+	}
+
+	render.JSON(w, r, toWorkspaceResponse(workspace, role))
+}
+
+// DeleteWorkspace удаляет пространство, его задачи становятся личными задачами авторов
+func (h *WorkspaceHandler) DeleteWorkspace(w http.ResponseWriter, r *http.Request) {
+	workspace, _, ok := h.loadWorkspace(w, r, models.WorkspaceRoleOwner)
+	if !ok {
+		return
+	}
+
+	err := h.repo.DeleteWorkspace(r.Context(), workspace.ID)
+	if err != nil && !errors.Is(err, repository.ErrWorkspaceNotFound) {
+		slog.Error("Failed to delete workspace", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to delete workspace"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWorkspaceTasks - общая доска задач пространства, поддерживает те же фильтры, что и список задач
+func (h *WorkspaceHandler) GetWorkspaceTasks(w http.ResponseWriter, r *http.Request) {
+	workspace, _, ok := h.loadWorkspace(w, r, models.WorkspaceRoleMember)
+	if !ok {
+		return
+	}
+
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	filter, err := parseTaskFilter(r)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
+	}
+	filter.WorkspaceID = &workspace.ID
+
+	tasks, err := h.tasks.GetTasksByUserID(r.Context(), userID, filter)
+	if err != nil {
+		slog.Error("Failed to get workspace tasks", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get tasks"})
+
+		return
+	}
+
+	render.JSON(w, r, types.NewTaskResponses(tasks))
+}
+
+func (h *WorkspaceHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	workspace, _, ok := h.loadWorkspace(w, r, models.WorkspaceRoleMember)
+	if !ok {
+		return
+	}
+
+	members, err := h.repo.GetMembers(r.Context(), workspace.ID)
+	if err != nil {
+		slog.Error("Failed to get workspace members", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get members"})
+
+		return
+	}
+
+	resp := make([]types.WorkspaceMemberResponse, 0, len(members))
+	for _, member := range members {
+		resp = append(resp, toWorkspaceMemberResponse(member))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// UpdateMemberRole меняет роль участника. Администратор управляет только обычными участниками
+// и не может назначать администраторов
+func (h *WorkspaceHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	workspace, role, ok := h.loadWorkspace(w, r, models.WorkspaceRoleAdmin)
+	if !ok {
+		return
+	}
+
+	memberID, targetRole, ok := h.loadMember(w, r, workspace.ID)
+	if !ok {
+		return
+	}
+
+	var req types.UpdateMemberRequest
+	err := render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+
+	if !role.CanManage(targetRole) || !role.CanManage(req.Role) {
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, map[string]string{"error": "Not enough permissions to change this member's role"})
+		return
+	}
+
+	err = h.repo.UpdateMemberRole(r.Context(), workspace.ID, memberID, req.Role)
+	if errors.Is(err, repository.ErrNotWorkspaceMember) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Member not found"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to update member role", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to update member role"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveMember исключает участника. Любой участник, кроме владельца, может выйти сам
+func (h *WorkspaceHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	workspace, role, ok := h.loadWorkspace(w, r, models.WorkspaceRoleMember)
+	if !ok {
+		return
+	}
+
+	memberID, targetRole, ok := h.loadMember(w, r, workspace.ID)
+	if !ok {
+		return
+	}
+
+	if memberID == userID {
+		if role == models.WorkspaceRoleOwner {
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, map[string]string{"error": "Owner can't leave the workspace"})
+			return
+		}
+	} else if !role.CanManage(targetRole) {
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, map[string]string{"error": "Not enough permissions to remove this member"})
+		return
+	}
+
+	err = h.repo.RemoveMember(r.Context(), workspace.ID, memberID)
+	if err != nil && !errors.Is(err, repository.ErrNotWorkspaceMember) {
+		slog.Error("Failed to remove workspace member", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to remove member"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateInvitation приглашает пользователя по почте. Писем сервер не отправляет: токен для принятия
+// возвращается в ответе один раз, дальше хранится только его хеш
+func (h *WorkspaceHandler) CreateInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	workspace, role, ok := h.loadWorkspace(w, r, models.WorkspaceRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req types.CreateInvitationRequest
+	err = render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+
+	if !role.CanManage(req.Role) {
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, map[string]string{"error": "Only the owner can invite admins"})
+		return
+	}
+
+	token, hash, err := models.NewInvitationToken()
+	if err != nil {
+		slog.Error("Failed to generate invitation token", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	invitation := &models.WorkspaceInvitation{
+		WorkspaceID: workspace.ID,
+		Email:       strings.ToLower(req.Email),
+		Role:        req.Role,
+		TokenHash:   hash,
+		InvitedBy:   &userID,
+		ExpiresAt:   time.Now().Add(h.policy.InvitationTTL),
+	}
+	err = h.repo.CreateInvitation(r.Context(), invitation)
+	if err != nil {
+		slog.Error("Failed to create workspace invitation", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to create invitation"})
+
+		return
+	}
+
+	resp := toInvitationResponse(*invitation)
+	resp.AcceptToken = token
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+func (h *WorkspaceHandler) GetInvitations(w http.ResponseWriter, r *http.Request) {
+	workspace, _, ok := h.loadWorkspace(w, r, models.WorkspaceRoleAdmin)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.repo.GetInvitations(r.Context(), workspace.ID)
+	if err != nil {
+		slog.Error("Failed to get workspace invitations", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to get invitations"})
+
+		return
+	}
+
+	resp := make([]types.InvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		resp = append(resp, toInvitationResponse(invitation))
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func (h *WorkspaceHandler) DeleteInvitation(w http.ResponseWriter, r *http.Request) {
+	workspace, _, ok := h.loadWorkspace(w, r, models.WorkspaceRoleAdmin)
+	if !ok {
+		return
+	}
+
+	invitationID, err := strconv.ParseUint(chi.URLParam(r, "invitationID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid invitation ID"})
+		return
+	}
+
+	err = h.repo.DeleteInvitation(r.Context(), workspace.ID, uint(invitationID))
+	if errors.Is(err, repository.ErrInvitationNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Invitation not found"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to delete workspace invitation", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to delete invitation"})
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AcceptInvitation добавляет текущего пользователя в пространство по токену из приглашения
+func (h *WorkspaceHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return
+	}
+
+	var req types.AcceptInvitationRequest
+	err = render.DecodeJSON(r.Body, &req)
+	if err != nil {
+		slog.Error("Failed to decode request body", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+
+		return
+	}
+
+	err = validate.Struct(req)
+	if err != nil {
+		slog.Error("Validation failed", slog.Any("error", err))
+
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Validation failed: " + err.Error()})
+
+		return
+	}
+
+	member, err := h.repo.AcceptInvitation(r.Context(), models.HashInvitationToken(req.Token), userID)
+	switch {
+	case errors.Is(err, repository.ErrInvitationNotFound):
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Invitation not found"})
+		return
+	case errors.Is(err, repository.ErrInvitationUsed):
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, map[string]string{"error": "Invitation has already been accepted"})
+		return
+	case errors.Is(err, repository.ErrInvitationExpired):
+		render.Status(r, http.StatusGone)
+		render.JSON(w, r, map[string]string{"error": "Invitation has expired"})
+		return
+	case errors.Is(err, repository.ErrInvitationEmailMismatch):
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, map[string]string{"error": "Invitation was sent to another email"})
+		return
+	case err != nil:
+		slog.Error("Failed to accept workspace invitation", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to accept invitation"})
+
+		return
+	}
+
+	render.JSON(w, r, toWorkspaceMemberResponse(member))
+}
+
+// loadWorkspace достаёт пространство из URL вместе с ролью текущего пользователя. Пространство,
+// в котором пользователь не состоит, выглядит несуществующим, а роль ниже need даёт 403
+func (h *WorkspaceHandler) loadWorkspace(w http.ResponseWriter, r *http.Request, need models.WorkspaceRole) (models.Workspace, models.WorkspaceRole, bool) {
+	userID, err := getUserIDFromCtx(r.Context())
+	if err != nil {
+		slog.Error("Failed to get user ID from context", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.Workspace{}, "", false
+	}
+
+	workspaceID, err := strconv.ParseUint(chi.URLParam(r, "workspaceID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid workspace ID"})
+		return models.Workspace{}, "", false
+	}
+
+	role, err := h.repo.GetMemberRole(r.Context(), uint(workspaceID), userID)
+	if errors.Is(err, repository.ErrNotWorkspaceMember) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Workspace not found"})
+		return models.Workspace{}, "", false
+	}
+	if err != nil {
+		slog.Error("Failed to get workspace role", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.Workspace{}, "", false
+	}
+	if !role.AtLeast(need) {
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, map[string]string{"error": "Not enough permissions in this workspace"})
+		return models.Workspace{}, "", false
+	}
+
+	workspace, err := h.repo.GetWorkspaceByID(r.Context(), uint(workspaceID))
+	if errors.Is(err, repository.ErrWorkspaceNotFound) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Workspace not found"})
+		return models.Workspace{}, "", false
+	}
+	if err != nil {
+		slog.Error("Failed to get workspace by ID", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return models.Workspace{}, "", false
+	}
+
+	return workspace, role, true
+}
+
+// loadMember разбирает {userID} и возвращает роль этого участника
+func (h *WorkspaceHandler) loadMember(w http.ResponseWriter, r *http.Request, workspaceID uint) (uint, models.WorkspaceRole, bool) {
+	memberID, err := strconv.ParseUint(chi.URLParam(r, "userID"), 10, 32)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid user ID"})
+		return 0, "", false
+	}
+
+	role, err := h.repo.GetMemberRole(r.Context(), workspaceID, uint(memberID))
+	if errors.Is(err, repository.ErrNotWorkspaceMember) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "Member not found"})
+		return 0, "", false
+	}
+	if err != nil {
+		slog.Error("Failed to get workspace member role", slog.Any("error", err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+
+		return 0, "", false
+	}
+
+	return uint(memberID), role, true
+}
+
+func toWorkspaceResponse(workspace models.Workspace, role models.WorkspaceRole) types.WorkspaceResponse {
+	return types.WorkspaceResponse{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		OwnerID:   workspace.OwnerID,
+		Role:      role,
+		CreatedAt: workspace.CreatedAt,
+		UpdatedAt: workspace.UpdatedAt,
+	}
+}
+
+func toWorkspaceMemberResponse(member models.WorkspaceMember) types.WorkspaceMemberResponse {
+	return types.WorkspaceMemberResponse{
+		WorkspaceID: member.WorkspaceID,
+		UserID:      member.UserID,
+		Username:    member.Username,
+		Role:        member.Role,
+		JoinedAt:    member.JoinedAt,
+	}
+}
+
+func toInvitationResponse(invitation models.WorkspaceInvitation) types.InvitationResponse {
+	return types.InvitationResponse{
+		ID:          invitation.ID,
+		WorkspaceID: invitation.WorkspaceID,
+		Email:       invitation.Email,
+		Role:        invitation.Role,
+		InvitedBy:   invitation.InvitedBy,
+		ExpiresAt:   invitation.ExpiresAt,
+		CreatedAt:   invitation.CreatedAt,
+	}
+}
@@ -0,0 +1,436 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockWorkspaceRepository struct {
+	mock.Mock
+}
+
+func (m *MockWorkspaceRepository) CreateWorkspace(ctx context.Context, workspace *models.Workspace) error {
+	args := m.Called(ctx, workspace)
+	workspace.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) GetWorkspaceByID(ctx context.Context, id uint) (models.Workspace, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.Workspace), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) GetWorkspacesByUserID(ctx context.Context, userID uint) ([]models.UserWorkspace, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.UserWorkspace), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) UpdateWorkspaceName(ctx context.Context, id uint, name string) error {
+	args := m.Called(ctx, id, name)
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) DeleteWorkspace(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) GetMemberRole(ctx context.Context, workspaceID, userID uint) (models.WorkspaceRole, error) {
+	args := m.Called(ctx, workspaceID, userID)
+	return args.Get(0).(models.WorkspaceRole), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) GetMembers(ctx context.Context, workspaceID uint) ([]models.WorkspaceMember, error) {
+	args := m.Called(ctx, workspaceID)
+	return args.Get(0).([]models.WorkspaceMember), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) UpdateMemberRole(ctx context.Context, workspaceID, userID uint, role models.WorkspaceRole) error {
+	args := m.Called(ctx, workspaceID, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID uint) error {
+	args := m.Called(ctx, workspaceID, userID)
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) CreateInvitation(ctx context.Context, invitation *models.WorkspaceInvitation) error {
+	args := m.Called(ctx, invitation)
+	invitation.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) GetInvitations(ctx context.Context, workspaceID uint) ([]models.WorkspaceInvitation, error) {
+	args := m.Called(ctx, workspaceID)
+	return args.Get(0).([]models.WorkspaceInvitation), args.Error(1)
+}
+
+func (m *MockWorkspaceRepository) DeleteInvitation(ctx context.Context, workspaceID, id uint) error {
+	args := m.Called(ctx, workspaceID, id)
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceRepository) AcceptInvitation(ctx context.Context, tokenHash string, userID uint) (models.WorkspaceMember, error) {
+	args := m.Called(ctx, tokenHash, userID)
+	return args.Get(0).(models.WorkspaceMember), args.Error(1)
+}
+
+func withWorkspaceMember(req *http.Request, workspaceID, memberID string, userID uint) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("workspaceID", workspaceID)
+	if memberID != "" {
+		rctx.URLParams.Add("userID", memberID)
+	}
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(withUserID(ctx, userID))
+}
+
+// newWorkspaceRepo возвращает репозиторий, в котором пространство 1 принадлежит пользователю 10,
+// 20 - администратор, 30 - участник, 40 - второй администратор
+func newWorkspaceRepo() *MockWorkspaceRepository {
+	repo := new(MockWorkspaceRepository)
+	repo.On("GetWorkspaceByID", mock.Anything, uint(1)).Return(models.Workspace{ID: 1, Name: "Team", OwnerID: 10}, nil).Maybe()
+	roles := map[uint]models.WorkspaceRole{
+		10: models.WorkspaceRoleOwner,
+		20: models.WorkspaceRoleAdmin,
+		30: models.WorkspaceRoleMember,
+		40: models.WorkspaceRoleAdmin,
+	}
+	for userID, role := range roles {
+		repo.On("GetMemberRole", mock.Anything, uint(1), userID).Return(role, nil).Maybe()
+	}
+	repo.On("GetMemberRole", mock.Anything, uint(1), mock.Anything).Return(models.WorkspaceRole(""), repository.ErrNotWorkspaceMember).Maybe()
+	return repo
+}
+
+func TestWorkspaceHandler_CreateWorkspace(t *testing.T) {
+	repo := new(MockWorkspaceRepository)
+	handler := NewWorkspaceHandler(repo, new(MockTaskRepository), WorkspacePolicy{})
+
+	repo.On("CreateWorkspace", mock.Anything, mock.MatchedBy(func(ws *models.Workspace) bool {
+		return ws.Name == "Team" && ws.OwnerID == 10
+	})).Return(nil).Once()
+
+	body, _ := json.Marshal(types.CreateWorkspaceRequest{Name: "Team"})
+	req := httptest.NewRequest("POST", "/workspaces", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	handler.CreateWorkspace(rr, req.WithContext(withUserID(req.Context(), 10)))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var resp types.WorkspaceResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, models.WorkspaceRoleOwner, resp.Role)
+	repo.AssertExpectations(t)
+}
+
+func TestWorkspaceHandler_RoleChecks(t *testing.T) {
+	testCases := []struct {
+		name         string
+		userID       uint
+		expectedCode int
+	}{
+		{"Owner Deletes", 10, http.StatusNoContent},
+		{"Admin Can't Delete", 20, http.StatusForbidden},
+		{"Member Can't Delete", 30, http.StatusForbidden},
+		{"Stranger Sees Nothing", 99, http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newWorkspaceRepo()
+			handler := NewWorkspaceHandler(repo, new(MockTaskRepository), WorkspacePolicy{})
+			repo.On("DeleteWorkspace", mock.Anything, uint(1)).Return(nil).Maybe()
+
+			rr := httptest.NewRecorder()
+			handler.DeleteWorkspace(rr, withWorkspaceMember(httptest.NewRequest("DELETE", "/workspaces/1", nil), "1", "", tc.userID))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusNoContent {
+				repo.AssertCalled(t, "DeleteWorkspace", mock.Anything, uint(1))
+			} else {
+				repo.AssertNotCalled(t, "DeleteWorkspace", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestWorkspaceHandler_GetWorkspaceTasks(t *testing.T) {
+	repo := newWorkspaceRepo()
+	tasksRepo := new(MockTaskRepository)
+	handler := NewWorkspaceHandler(repo, tasksRepo, WorkspacePolicy{})
+
+	tasksRepo.On("GetTasksByUserID", mock.Anything, uint(30), mock.MatchedBy(func(f repository.TaskFilter) bool {
+		return f.WorkspaceID != nil && *f.WorkspaceID == 1 && f.Priority == models.PriorityHigh
+	})).Return([]models.Task{{ID: 5, UserID: 10}}, nil).Once()
+
+	rr := httptest.NewRecorder()
+	handler.GetWorkspaceTasks(rr, withWorkspaceMember(httptest.NewRequest("GET", "/workspaces/1/tasks?priority=high", nil), "1", "", 30))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	tasksRepo.AssertExpectations(t)
+}
+
+func TestWorkspaceHandler_UpdateMemberRole(t *testing.T) {
+	testCases := []struct {
+		name         string
+		userID       uint
+		memberID     string
+		role         models.WorkspaceRole
+		expectedCode int
+	}{
+		{"Owner Promotes Member", 10, "30", models.WorkspaceRoleAdmin, http.StatusNoContent},
+		{"Owner Demotes Admin", 10, "20", models.WorkspaceRoleMember, http.StatusNoContent},
+		{"Admin Can't Promote", 20, "30", models.WorkspaceRoleAdmin, http.StatusForbidden},
+		{"Admin Can't Demote Admin", 20, "40", models.WorkspaceRoleMember, http.StatusForbidden},
+		{"Nobody Changes Owner", 20, "10", models.WorkspaceRoleMember, http.StatusForbidden},
+		{"Member Can't Manage", 30, "30", models.WorkspaceRoleMember, http.StatusForbidden},
+		{"Unknown Member", 10, "99", models.WorkspaceRoleMember, http.StatusNotFound},
+		{"Owner Role Can't Be Assigned", 10, "30", models.WorkspaceRoleOwner, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newWorkspaceRepo()
+			handler := NewWorkspaceHandler(repo, new(MockTaskRepository), WorkspacePolicy{})
+			repo.On("UpdateMemberRole", mock.Anything, uint(1), mock.Anything, tc.role).Return(nil).Maybe()
+
+			body, _ := json.Marshal(types.UpdateMemberRequest{Role: tc.role})
+			req := httptest.NewRequest("PATCH", "/workspaces/1/members/"+tc.memberID, bytes.NewReader(body))
+			rr := httptest.NewRecorder()
+			handler.UpdateMemberRole(rr, withWorkspaceMember(req, "1", tc.memberID, tc.userID))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode != http.StatusNoContent {
+				repo.AssertNotCalled(t, "UpdateMemberRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestWorkspaceHandler_RemoveMember(t *testing.T) {
+	testCases := []struct {
+		name         string
+		userID       uint
+		memberID     string
+		expectedCode int
+	}{
+		{"Member Leaves", 30, "30", http.StatusNoContent},
+		{"Admin Removes Member", 20, "30", http.StatusNoContent},
+		{"Admin Can't Remove Admin", 20, "40", http.StatusForbidden},
+		{"Member Can't Remove Others", 30, "20", http.StatusForbidden},
+		{"Owner Can't Leave", 10, "10", http.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newWorkspaceRepo()
+			handler := NewWorkspaceHandler(repo, new(MockTaskRepository), WorkspacePolicy{})
+			repo.On("RemoveMember", mock.Anything, uint(1), mock.Anything).Return(nil).Maybe()
+
+			rr := httptest.NewRecorder()
+			handler.RemoveMember(rr, withWorkspaceMember(httptest.NewRequest("DELETE", "/workspaces/1/members/"+tc.memberID, nil), "1", tc.memberID, tc.userID))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestWorkspaceHandler_CreateInvitation(t *testing.T) {
+	testCases := []struct {
+		name         string
+		userID       uint
+		role         models.WorkspaceRole
+		expectedCode int
+	}{
+		{"Owner Invites Admin", 10, models.WorkspaceRoleAdmin, http.StatusCreated},
+		{"Admin Invites Member", 20, models.WorkspaceRoleMember, http.StatusCreated},
+		{"Admin Can't Invite Admin", 20, models.WorkspaceRoleAdmin, http.StatusForbidden},
+		{"Member Can't Invite", 30, models.WorkspaceRoleMember, http.StatusForbidden},
+		{"Owner Role Rejected", 10, models.WorkspaceRoleOwner, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newWorkspaceRepo()
+			handler := NewWorkspaceHandler(repo, new(MockTaskRepository), WorkspacePolicy{InvitationTTL: time.Hour})
+
+			var saved *models.WorkspaceInvitation
+			repo.On("CreateInvitation", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				saved = args.Get(1).(*models.WorkspaceInvitation)
+			}).Return(nil).Maybe()
+
+			body, _ := json.Marshal(types.CreateInvitationRequest{Email: "Bob@Example.com", Role: tc.role})
+			req := httptest.NewRequest("POST", "/workspaces/1/invitations", bytes.NewReader(body))
+			rr := httptest.NewRecorder()
+			handler.CreateInvitation(rr, withWorkspaceMember(req, "1", "", tc.userID))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode != http.StatusCreated {
+				assert.Nil(t, saved)
+				return
+			}
+
+			var resp types.InvitationResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.NotNil(t, saved)
+			assert.Equal(t, "bob@example.com", saved.Email)
+			assert.Equal(t, models.HashInvitationToken(resp.AcceptToken), saved.TokenHash)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), saved.ExpiresAt, time.Minute)
+		})
+	}
+}
+
+func TestWorkspaceHandler_AcceptInvitation(t *testing.T) {
+	testCases := []struct {
+		name         string
+		repoErr      error
+		expectedCode int
+	}{
+		{"Accepted", nil, http.StatusOK},
+		{"Unknown Token", repository.ErrInvitationNotFound, http.StatusNotFound},
+		{"Already Used", repository.ErrInvitationUsed, http.StatusConflict},
+		{"Expired", repository.ErrInvitationExpired, http.StatusGone},
+		{"Another Email", repository.ErrInvitationEmailMismatch, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockWorkspaceRepository)
+			handler := NewWorkspaceHandler(repo, new(MockTaskRepository), WorkspacePolicy{})
+
+			member := models.WorkspaceMember{WorkspaceID: 1, UserID: 30, Role: models.WorkspaceRoleMember}
+			repo.On("AcceptInvitation", mock.Anything, models.HashInvitationToken("secret"), uint(30)).Return(member, tc.repoErr).Once()
+
+			body, _ := json.Marshal(types.AcceptInvitationRequest{Token: "secret"})
+			req := httptest.NewRequest("POST", "/workspaces/invitations/accept", bytes.NewReader(body))
+			rr := httptest.NewRecorder()
+			handler.AcceptInvitation(rr, req.WithContext(withUserID(req.Context(), 30)))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTaskHandler_WorkspaceAccess(t *testing.T) {
+	workspaceID := uint(7)
+	task := models.Task{ID: 1, UserID: 10, WorkspaceID: &workspaceID}
+
+	testCases := []struct {
+		name       string
+		role       models.WorkspaceRole
+		roleErr    error
+		getCode    int
+		deleteCode int
+	}{
+		{"Member Edits But Can't Delete", models.WorkspaceRoleMember, nil, http.StatusOK, http.StatusForbidden},
+		{"Admin Manages", models.WorkspaceRoleAdmin, nil, http.StatusOK, http.StatusNoContent},
+		{"Non-Member Sees Nothing", "", repository.ErrNotWorkspaceMember, http.StatusNotFound, http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+			mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(task, nil)
+			mockRepo.On("GetWorkspaceRole", mock.Anything, workspaceID, uint(20)).Return(tc.role, tc.roleErr)
+			mockRepo.On("GetTaskSharePermission", mock.Anything, uint(1), uint(20)).Return(models.SharePermission(""), repository.ErrShareNotFound).Maybe()
+			mockRepo.On("DeleteTask", mock.Anything, uint(1)).Return(nil).Maybe()
+
+			rr := httptest.NewRecorder()
+			handler.GetTaskByID(rr, withTaskID(httptest.NewRequest("GET", "/tasks/1", nil), "1", 20))
+			assert.Equal(t, tc.getCode, rr.Code)
+
+			rr = httptest.NewRecorder()
+			handler.DeleteTask(rr, withTaskID(httptest.NewRequest("DELETE", "/tasks/1", nil), "1", 20))
+			assert.Equal(t, tc.deleteCode, rr.Code)
+		})
+	}
+}
+
+func TestTaskHandler_UpdateTaskWorkspace(t *testing.T) {
+	parentID := uint(2)
+	workspaceID := uint(7)
+
+	testCases := []struct {
+		name         string
+		task         models.Task
+		workspaceID  uint
+		member       bool
+		expectedCode int
+	}{
+		{"Move To Workspace", models.Task{ID: 1, UserID: 10}, workspaceID, true, http.StatusNoContent},
+		{"Make Personal", models.Task{ID: 1, UserID: 10, WorkspaceID: &workspaceID}, 0, true, http.StatusNoContent},
+		{"Not A Member", models.Task{ID: 1, UserID: 10}, workspaceID, false, http.StatusBadRequest},
+		{"Subtask Follows Parent", models.Task{ID: 1, UserID: 10, ParentID: &parentID}, workspaceID, true, http.StatusConflict},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+			mockRepo.On("GetTaskByID", mock.Anything, uint(1)).Return(tc.task, nil).Once()
+			if tc.member {
+				mockRepo.On("GetWorkspaceRole", mock.Anything, workspaceID, uint(10)).Return(models.WorkspaceRoleMember, nil).Maybe()
+			} else {
+				mockRepo.On("GetWorkspaceRole", mock.Anything, workspaceID, uint(10)).Return(models.WorkspaceRole(""), repository.ErrNotWorkspaceMember).Maybe()
+			}
+			mockRepo.On("UpdateTaskWorkspace", mock.Anything, uint(1), mock.Anything).Return(nil).Maybe()
+			mockRepo.On("CreateTaskRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			body, _ := json.Marshal(types.UpdateTaskRequest{WorkspaceID: &tc.workspaceID})
+			rr := httptest.NewRecorder()
+			handler.UpdateTask(rr, withTaskID(httptest.NewRequest("PATCH", "/tasks/1", bytes.NewReader(body)), "1", 10))
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusNoContent {
+				mockRepo.AssertCalled(t, "UpdateTaskWorkspace", mock.Anything, uint(1), mock.MatchedBy(func(id *uint) bool {
+					return (tc.workspaceID == 0) == (id == nil)
+				}))
+			} else {
+				mockRepo.AssertNotCalled(t, "UpdateTaskWorkspace", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetUserTasks_WorkspaceFilter(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	handler := NewTaskHandler(mockRepo, new(MockProjectRepository), new(MockSeriesRepository), fakeTransactor{}, &fakeEventStore{}, &fakeAuditRecorder{}, TaskPolicy{})
+
+	mockRepo.On("GetTasksByUserID", mock.Anything, uint(10), mock.MatchedBy(func(f repository.TaskFilter) bool {
+		return f.WorkspaceID != nil && *f.WorkspaceID == 0
+	})).Return([]models.Task{}, nil).Once()
+	mockRepo.On("GetTasksByUserID", mock.Anything, uint(10), mock.MatchedBy(func(f repository.TaskFilter) bool {
+		return f.WorkspaceID != nil && *f.WorkspaceID == 7
+	})).Return([]models.Task{}, nil).Once()
+
+	for _, query := range []string{"workspace=personal", "workspace=7"} {
+		req := httptest.NewRequest("GET", "/tasks?"+query, nil)
+		rr := httptest.NewRecorder()
+		handler.GetUserTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
+	mockRepo.AssertExpectations(t)
+
+	req := httptest.NewRequest("GET", "/tasks?workspace=abc", nil)
+	rr := httptest.NewRecorder()
+	handler.GetUserTasks(rr, req.WithContext(withUserID(req.Context(), 10)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
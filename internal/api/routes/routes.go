@@ -4,38 +4,207 @@ import (
 	"database/sql"
 	"net/http"
 	"to-do-list/internal/api/handlers"
+	"to-do-list/internal/audit"
 	"to-do-list/internal/auth"
+	"to-do-list/internal/config"
 	"to-do-list/internal/middleware"
 	"to-do-list/internal/repository"
+	"to-do-list/internal/storage"
+	"to-do-list/internal/stream"
+	"to-do-list/internal/webhook"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func SetupRoutes(db *sql.DB, tm *auth.TokenManager) http.Handler {
+func SetupRoutes(db *sql.DB, tm *auth.TokenManager, cfg *config.Config, store storage.BlobStore, hub *stream.Hub, rooms *stream.Rooms, guard *webhook.Guard) http.Handler {
 	r := chi.NewRouter()
 
+	transactor := repository.NewPostgresTransactor(db)
+	outboxRepo := repository.NewPostgresOutboxRepository(db)
+
+	auditRepo := repository.NewPostgresAuditRepository(db)
+	auditRecorder := audit.NewRecorder(auditRepo)
+	auditHandler := handlers.NewAuditHandler(auditRepo)
+
+	webhookRepo := repository.NewPostgresWebhookRepository(db)
+	webhookHandler := handlers.NewWebhookHandler(webhookRepo, guard)
+
+	notificationHandler := handlers.NewNotificationHandler(repository.NewPostgresNotificationRepository(db))
+
+	sessionRepo := repository.NewPostgresSessionRepository(db)
+	sessionHandler := handlers.NewSessionHandler(sessionRepo)
+
 	userRepo := repository.NewPostgresUserRepository(db)
-	userHandler := handlers.NewUserHandler(userRepo, tm)
+	userHandler := handlers.NewUserHandler(userRepo, sessionRepo, tm, transactor, outboxRepo, auditRecorder)
+
+	projectRepo := repository.NewPostgresProjectRepository(db)
+
+	seriesRepo := repository.NewPostgresSeriesRepository(db)
+	seriesHandler := handlers.NewSeriesHandler(seriesRepo)
 
 	taskRepo := repository.NewPostgresTaskRepository(db)
-	taskHandler := handlers.NewTaskHandler(taskRepo)
+	taskHandler := handlers.NewTaskHandler(taskRepo, projectRepo, seriesRepo, transactor, outboxRepo, auditRecorder, handlers.TaskPolicy{
+		RequireSubtasksCompleted: cfg.Tasks.RequireSubtasksCompleted,
+	})
+	projectHandler := handlers.NewProjectHandler(projectRepo, taskRepo, transactor, outboxRepo)
+
+	commentRepo := repository.NewPostgresCommentRepository(db)
+	commentHandler := handlers.NewCommentHandler(commentRepo, taskRepo)
+
+	attachmentRepo := repository.NewPostgresAttachmentRepository(db)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentRepo, taskRepo, store, handlers.AttachmentPolicy{
+		MaxSize:      cfg.Storage.MaxSize,
+		AllowedTypes: cfg.Storage.AllowedTypes,
+	})
+
+	workspaceRepo := repository.NewPostgresWorkspaceRepository(db)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceRepo, taskRepo, handlers.WorkspacePolicy{
+		InvitationTTL: cfg.Workspaces.InvitationTTL,
+	})
+
+	reminderRepo := repository.NewPostgresReminderRepository(db)
+	reminderHandler := handlers.NewReminderHandler(reminderRepo, taskRepo)
+
+	streamHandler := handlers.NewStreamHandler(hub, outboxRepo, handlers.StreamPolicy{
+		Heartbeat:    cfg.Stream.Heartbeat,
+		ReplayLimit:  cfg.Stream.ReplayLimit,
+		WriteTimeout: cfg.Stream.WriteTimeout,
+	})
+
+	liveHandler := handlers.NewLiveHandler(rooms, tm, sessionRepo, taskRepo, projectRepo, handlers.LivePolicy{
+		PingInterval:   cfg.WS.PingInterval,
+		PongTimeout:    cfg.WS.PongTimeout,
+		AuthTimeout:    cfg.WS.AuthTimeout,
+		WriteTimeout:   cfg.WS.WriteTimeout,
+		MaxMessageSize: cfg.WS.MaxMessageSize,
+		AccessTTL:      cfg.WS.AccessTTL,
+	})
+
+	tagRepo := repository.NewPostgresTagRepository(db)
+	tagHandler := handlers.NewTagHandler(tagRepo)
+
+	authMiddleware := middleware.AuthMiddleware(tm, sessionRepo)
+
+	// Токен проверяет сам обработчик: браузер не может передать его в заголовке при открытии WebSocket
+	r.Get("/ws", liveHandler.Serve)
 
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Route("/users", func(r chi.Router) {
 			r.Post("/register", userHandler.Register)
 			r.Post("/login", userHandler.Login)
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware)
+
+				r.Get("/me/sessions", sessionHandler.GetSessions)
+				r.Delete("/me/sessions/{sessionID}", sessionHandler.DeleteSession)
+				r.Post("/me/password", userHandler.ChangePassword)
+				r.Get("/me/audit", auditHandler.GetMyAuditEvents)
+				r.Get("/me/reminders", reminderHandler.GetReminderPreferences)
+				r.Put("/me/reminders", reminderHandler.UpdateReminderPreferences)
+				r.Get("/me/notifications", notificationHandler.GetNotifications)
+				r.Post("/me/notifications/read-all", notificationHandler.MarkAllNotificationsRead)
+				r.Post("/me/notifications/{notificationID}/read", notificationHandler.MarkNotificationRead)
+			})
+		})
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(authMiddleware)
+			r.Use(middleware.RequireAdmin(userRepo))
+
+			r.Get("/audit", auditHandler.GetAuditEvents)
 		})
 
 		// Все задачи только для авторизованных пользователей
 		r.Group(func(r chi.Router) {
-			r.Use(middleware.AuthMiddleware(tm))
+			r.Use(authMiddleware)
 
 			r.Route("/tasks", func(r chi.Router) {
 				r.Post("/", taskHandler.CreateTask)
 				r.Get("/", taskHandler.GetUserTasks)
+				r.Get("/stream", streamHandler.StreamTasks)
+				r.Get("/transitions", taskHandler.GetStatusTransitions)
+				r.Get("/shared", taskHandler.GetSharedTasks)
+				r.Post("/batch", taskHandler.BatchTasks)
+				r.Post("/archive-completed", taskHandler.ArchiveCompletedTasks)
+				r.Get("/trash", taskHandler.GetTrash)
+				r.Delete("/trash", taskHandler.EmptyTrash)
+				r.Delete("/trash/{taskID}", taskHandler.PurgeTask)
+				r.Post("/{taskID}/restore", taskHandler.RestoreTask)
+				r.Post("/{taskID}/archive", taskHandler.ArchiveTask)
+				r.Post("/{taskID}/unarchive", taskHandler.UnarchiveTask)
+				r.Get("/{taskID}/history", taskHandler.GetTaskHistory)
+				r.Post("/{taskID}/history/{revisionID}/revert", taskHandler.RevertTask)
 				r.Get("/{taskID}", taskHandler.GetTaskByID)
 				r.Patch("/{taskID}", taskHandler.UpdateTask)
 				r.Delete("/{taskID}", taskHandler.DeleteTask)
+				r.Get("/{taskID}/subtasks", taskHandler.GetSubtasks)
+				r.Post("/{taskID}/subtasks", taskHandler.CreateSubtask)
+				r.Post("/{taskID}/dependencies", taskHandler.AddDependency)
+				r.Delete("/{taskID}/dependencies/{dependsOnID}", taskHandler.RemoveDependency)
+				r.Get("/{taskID}/shares", taskHandler.GetTaskShares)
+				r.Post("/{taskID}/shares", taskHandler.ShareTask)
+				r.Delete("/{taskID}/shares/{userID}", taskHandler.RemoveTaskShare)
+				r.Put("/{taskID}/assignee", taskHandler.AssignTask)
+				r.Delete("/{taskID}/assignee", taskHandler.UnassignTask)
+				r.Get("/{taskID}/reminders", reminderHandler.GetTaskReminders)
+				r.Put("/{taskID}/reminders", reminderHandler.SetTaskReminders)
+				r.Get("/{taskID}/comments", commentHandler.GetComments)
+				r.Post("/{taskID}/comments", commentHandler.CreateComment)
+				r.Patch("/{taskID}/comments/{commentID}", commentHandler.UpdateComment)
+				r.Delete("/{taskID}/comments/{commentID}", commentHandler.DeleteComment)
+				r.Get("/{taskID}/attachments", attachmentHandler.GetAttachments)
+				r.Post("/{taskID}/attachments", attachmentHandler.UploadAttachment)
+				r.Get("/{taskID}/attachments/{attachmentID}", attachmentHandler.DownloadAttachment)
+				r.Delete("/{taskID}/attachments/{attachmentID}", attachmentHandler.DeleteAttachment)
+			})
+
+			r.Route("/projects", func(r chi.Router) {
+				r.Post("/", projectHandler.CreateProject)
+				r.Get("/", projectHandler.GetProjects)
+				r.Get("/{projectID}", projectHandler.GetProject)
+				r.Patch("/{projectID}", projectHandler.UpdateProject)
+				r.Delete("/{projectID}", projectHandler.DeleteProject)
+				r.Get("/{projectID}/tasks", projectHandler.GetProjectTasks)
+			})
+
+			r.Route("/workspaces", func(r chi.Router) {
+				r.Post("/", workspaceHandler.CreateWorkspace)
+				r.Get("/", workspaceHandler.GetWorkspaces)
+				r.Post("/invitations/accept", workspaceHandler.AcceptInvitation)
+				r.Get("/{workspaceID}", workspaceHandler.GetWorkspace)
+				r.Patch("/{workspaceID}", workspaceHandler.UpdateWorkspace)
+				r.Delete("/{workspaceID}", workspaceHandler.DeleteWorkspace)
+				r.Get("/{workspaceID}/tasks", workspaceHandler.GetWorkspaceTasks)
+				r.Get("/{workspaceID}/members", workspaceHandler.GetMembers)
+				r.Patch("/{workspaceID}/members/{userID}", workspaceHandler.UpdateMemberRole)
+				r.Delete("/{workspaceID}/members/{userID}", workspaceHandler.RemoveMember)
+				r.Post("/{workspaceID}/invitations", workspaceHandler.CreateInvitation)
+				r.Get("/{workspaceID}/invitations", workspaceHandler.GetInvitations)
+				r.Delete("/{workspaceID}/invitations/{invitationID}", workspaceHandler.DeleteInvitation)
+			})
+
+			r.Route("/webhooks", func(r chi.Router) {
+				r.Post("/", webhookHandler.CreateWebhook)
+				r.Get("/", webhookHandler.GetWebhooks)
+				r.Get("/{webhookID}", webhookHandler.GetWebhook)
+				r.Patch("/{webhookID}", webhookHandler.UpdateWebhook)
+				r.Delete("/{webhookID}", webhookHandler.DeleteWebhook)
+				r.Get("/{webhookID}/deliveries", webhookHandler.GetDeliveries)
+			})
+
+			r.Route("/series", func(r chi.Router) {
+				r.Get("/", seriesHandler.GetSeries)
+				r.Get("/{seriesID}", seriesHandler.GetSeriesByID)
+				r.Patch("/{seriesID}", seriesHandler.UpdateSeries)
+				r.Post("/{seriesID}/stop", seriesHandler.StopSeries)
+			})
+
+			r.Route("/tags", func(r chi.Router) {
+				r.Get("/", tagHandler.GetTags)
+				r.Post("/", tagHandler.CreateTag)
+				r.Patch("/{tagID}", tagHandler.UpdateTag)
+				r.Delete("/{tagID}", tagHandler.DeleteTag)
 			})
 		})
 	})
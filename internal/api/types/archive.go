@@ -0,0 +1,5 @@
+package types
+
+type ArchiveCompletedResponse struct {
+	Archived int64 `json:"archived"`
+}
@@ -0,0 +1,12 @@
+package types
+
+import "time"
+
+type AttachmentResponse struct {
+	ID          uint      `json:"id"`
+	TaskID      uint      `json:"taskId"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
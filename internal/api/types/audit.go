@@ -0,0 +1,21 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+	"to-do-list/internal/models"
+)
+
+type AuditEventResponse struct {
+	ID         uint               `json:"id"`
+	UserID     *uint              `json:"userId"`
+	Action     models.AuditAction `json:"action"`
+	EntityType string             `json:"entityType,omitempty"`
+	EntityID   *uint              `json:"entityId,omitempty"`
+	RequestID  string             `json:"requestId,omitempty"`
+	IP         string             `json:"ip,omitempty"`
+	Before     json.RawMessage    `json:"before,omitempty"`
+	After      json.RawMessage    `json:"after,omitempty"`
+	Metadata   json.RawMessage    `json:"metadata,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
+}
@@ -0,0 +1,48 @@
+package types
+
+const (
+	BatchAtomic  = "atomic"
+	BatchPerItem = "per_item"
+
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchTaskRequest: в режиме atomic (по умолчанию) первая же ошибка отменяет весь пакет,
+// в режиме per_item неудачные операции пропускаются, остальные выполняются
+type BatchTaskRequest struct {
+	Mode       string               `json:"mode" validate:"omitempty,oneof=atomic per_item"`
+	Operations []BatchTaskOperation `json:"operations" validate:"required,min=1,max=100,dive"`
+}
+
+// BatchTaskOperation: для create заполняется Create, для update - TaskID и Update, для delete - TaskID
+type BatchTaskOperation struct {
+	Op     string             `json:"op" validate:"required,oneof=create update delete"`
+	TaskID uint               `json:"taskId"`
+	Create *CreateTaskRequest `json:"create" validate:"required_if=Op create,omitempty"`
+	Update *UpdateTaskRequest `json:"update" validate:"required_if=Op update,omitempty"`
+}
+
+// BatchTaskResult - итог одной операции. Status - код, который вернул бы одиночный запрос
+type BatchTaskResult struct {
+	Index  int           `json:"index"`
+	Op     string        `json:"op"`
+	TaskID uint          `json:"taskId,omitempty"`
+	Status int           `json:"status"`
+	Task   *TaskResponse `json:"task,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+type BatchTaskResponse struct {
+	Mode      string            `json:"mode"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchTaskResult `json:"results"`
+}
+
+// BatchTaskErrorResponse - ответ на отменённый пакет в режиме atomic: Index - операция, на которой он остановился
+type BatchTaskErrorResponse struct {
+	Error string `json:"error"`
+	Index int    `json:"index"`
+}
@@ -0,0 +1,17 @@
+package types
+
+import "time"
+
+// CommentRequest: тело - markdown до models.MaxCommentLength символов
+type CommentRequest struct {
+	Body string `json:"body"`
+}
+
+type CommentResponse struct {
+	ID        uint       `json:"id"`
+	TaskID    uint       `json:"taskId"`
+	AuthorID  uint       `json:"authorId"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"createdAt"`
+	EditedAt  *time.Time `json:"editedAt,omitempty"`
+}
@@ -0,0 +1,6 @@
+package types
+
+// AddDependencyRequest: задача из URL не может начаться, пока не завершена TaskID
+type AddDependencyRequest struct {
+	TaskID uint `json:"taskId" validate:"required"`
+}
@@ -0,0 +1,32 @@
+package types
+
+// Типы сообщений WebSocket. Клиент шлёт auth, subscribe, unsubscribe, ping и pong,
+// сервер - ready, subscribed, unsubscribed, event, error, ping и pong
+const (
+	LiveAuth         = "auth"
+	LiveSubscribe    = "subscribe"
+	LiveUnsubscribe  = "unsubscribe"
+	LivePing         = "ping"
+	LivePong         = "pong"
+	LiveReady        = "ready"
+	LiveSubscribed   = "subscribed"
+	LiveUnsubscribed = "unsubscribed"
+	LiveEvent        = "event"
+	LiveError        = "error"
+)
+
+// LiveClientMessage - сообщение клиента. Token нужен только в auth, Topic - в subscribe и unsubscribe
+type LiveClientMessage struct {
+	Type  string `json:"type"`
+	Token string `json:"token,omitempty"`
+	Topic string `json:"topic,omitempty"`
+}
+
+// LiveServerMessage - сообщение сервера. В ответах на subscribe и unsubscribe и в их ошибках есть Topic
+type LiveServerMessage struct {
+	Type   string           `json:"type"`
+	UserID uint             `json:"userId,omitempty"`
+	Topic  string           `json:"topic,omitempty"`
+	Event  *TaskStreamEvent `json:"event,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"time"
+	"to-do-list/internal/models"
+)
+
+type NotificationResponse struct {
+	ID        uint                    `json:"id"`
+	Kind      models.NotificationKind `json:"kind"`
+	TaskID    *uint                   `json:"taskId,omitempty"`
+	Title     string                  `json:"title"`
+	Body      string                  `json:"body"`
+	Read      bool                    `json:"read"`
+	ReadAt    *time.Time              `json:"readAt,omitempty"`
+	CreatedAt time.Time               `json:"createdAt"`
+}
+
+// NotificationListResponse: Unread - все непрочитанные пользователя, а не только на этой странице
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	Unread        int                    `json:"unread"`
+}
+
+type MarkAllReadResponse struct {
+	Marked int64 `json:"marked"`
+}
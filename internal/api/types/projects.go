@@ -0,0 +1,23 @@
+package types
+
+import "time"
+
+type CreateProjectRequest struct {
+	Name  string `json:"name" validate:"required,max=50"`
+	Color string `json:"color" validate:"omitempty,hexcolor,max=7"`
+}
+
+type UpdateProjectRequest struct {
+	Name     *string `json:"name" validate:"omitempty,min=1,max=50"`
+	Color    *string `json:"color" validate:"omitempty,hexcolor,max=7"`
+	Archived *bool   `json:"archived"`
+}
+
+type ProjectResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	Archived  bool      `json:"archived"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"time"
+	"to-do-list/internal/models"
+)
+
+// TaskRemindersRequest: сроки вида 30m, 2h, 1d до дедлайна. Пустой список - напоминать по настройкам получателя
+type TaskRemindersRequest struct {
+	Before []string `json:"before" validate:"max=5"`
+}
+
+type TaskRemindersResponse struct {
+	Before []string `json:"before"`
+}
+
+// ReminderPreferencesRequest: Before - напоминания для задач без своих, пустой Channels - все каналы
+type ReminderPreferencesRequest struct {
+	Before   []string                         `json:"before" validate:"max=5"`
+	Channels []models.NotificationChannelName `json:"channels" validate:"max=3,dive,oneof=email webhook in_app"`
+}
+
+type ReminderPreferencesResponse struct {
+	Before    []string                         `json:"before"`
+	Channels  []models.NotificationChannelName `json:"channels"`
+	UpdatedAt *time.Time                       `json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,14 @@
+package types
+
+import (
+	"time"
+	"to-do-list/internal/models"
+)
+
+type RevisionResponse struct {
+	ID         uint                          `json:"id"`
+	UserID     *uint                         `json:"userId"`
+	Changes    map[string]models.FieldChange `json:"changes"`
+	RevertedTo *uint                         `json:"revertedTo,omitempty"`
+	CreatedAt  time.Time                     `json:"createdAt"`
+}
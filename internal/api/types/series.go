@@ -0,0 +1,15 @@
+package types
+
+import "time"
+
+type UpdateSeriesRequest struct {
+	Rule string `json:"rule" validate:"required,max=200"`
+}
+
+type SeriesResponse struct {
+	ID        uint      `json:"id"`
+	Rule      string    `json:"rule"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
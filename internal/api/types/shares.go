@@ -0,0 +1,24 @@
+package types
+
+import (
+	"time"
+	"to-do-list/internal/models"
+)
+
+// ShareTaskRequest: User - имя пользователя или почта
+type ShareTaskRequest struct {
+	User       string                 `json:"user" validate:"required,max=100"`
+	Permission models.SharePermission `json:"permission" validate:"required,oneof=viewer editor"`
+}
+
+type ShareResponse struct {
+	UserID     uint                   `json:"userId"`
+	Username   string                 `json:"username"`
+	Permission models.SharePermission `json:"permission"`
+	CreatedAt  time.Time              `json:"createdAt"`
+}
+
+type SharedTaskResponse struct {
+	Permission models.SharePermission `json:"permission"`
+	Task       TaskResponse           `json:"task"`
+}
@@ -0,0 +1,14 @@
+package types
+
+import (
+	"time"
+	"to-do-list/internal/models"
+)
+
+// TaskStreamEvent - поле data события SSE. У task.deleted Task - задача до удаления
+type TaskStreamEvent struct {
+	ID         uint             `json:"id"`
+	Type       models.EventType `json:"type"`
+	Task       TaskResponse     `json:"task"`
+	OccurredAt time.Time        `json:"occurredAt"`
+}
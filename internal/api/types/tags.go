@@ -0,0 +1,13 @@
+package types
+
+import "time"
+
+type TagRequest struct {
+	Name string `json:"name" validate:"required,max=30"`
+}
+
+type TagResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
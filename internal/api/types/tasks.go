@@ -5,25 +5,109 @@ import (
 	"to-do-list/internal/models"
 )
 
+// CreateTaskRequest: Recurrence делает задачу повторяющейся - daily, weekly, monthly
+// или RRULE вида "FREQ=WEEKLY;BYDAY=MO,WE"
 type CreateTaskRequest struct {
-	Name        string    `json:"name" validate:"required,max=30"`
-	Description string    `json:"description" validate:"max=150"`
-	Deadline    time.Time `json:"deadline" validate:"required"`
+	Name        string          `json:"name" validate:"required,max=30"`
+	Description string          `json:"description" validate:"max=150"`
+	Deadline    time.Time       `json:"deadline" validate:"required"`
+	Priority    models.Priority `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	Tags        []string        `json:"tags" validate:"omitempty,max=20,dive,max=30"`
+	ProjectID   *uint           `json:"projectId"`
+	WorkspaceID *uint           `json:"workspaceId"`
+	Recurrence  string          `json:"recurrence" validate:"omitempty,max=200"`
 }
 
+// UpdateTaskRequest: nil-поля не меняются. ProjectID = 0 убирает задачу из проекта,
+// ParentID = 0 делает подзадачу самостоятельной задачей, WorkspaceID = 0 делает задачу личной
 type UpdateTaskRequest struct {
-	Name        *string        `json:"name" validate:"omitempty,max=30"`
-	Description *string        `json:"description" validate:"omitempty,max=150"`
-	Deadline    *time.Time     `json:"deadline" validate:"omitempty"`
-	Status      *models.Status `json:"status" validate:"omitempty,oneof=pending 'in progress' failed completed"`
+	Name        *string          `json:"name" validate:"omitempty,max=30"`
+	Description *string          `json:"description" validate:"omitempty,max=150"`
+	Deadline    *time.Time       `json:"deadline" validate:"omitempty"`
+	Status      *models.Status   `json:"status" validate:"omitempty,oneof=pending 'in progress' failed completed"`
+	Priority    *models.Priority `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	Tags        *[]string        `json:"tags" validate:"omitempty,max=20,dive,max=30"`
+	ProjectID   *uint            `json:"projectId"`
+	ParentID    *uint            `json:"parentId"`
+	WorkspaceID *uint            `json:"workspaceId"`
+}
+
+// SystemTransitions выполняет только сервер (например, просроченная задача становится failed)
+type StatusTransitionsResponse struct {
+	Transitions       map[models.Status][]models.Status `json:"transitions"`
+	SystemTransitions map[models.Status][]models.Status `json:"systemTransitions"`
 }
 
 type TaskResponse struct {
-	ID          uint          `json:"id"`
-	UserID      uint          `json:"userId"`
-	Name        string        `json:"name"`
-	Description string        `json:"description"`
-	CreatedAt   time.Time     `json:"createdAt"`
-	Deadline    time.Time     `json:"deadline"`
-	Status      models.Status `json:"status"`
+	ID          uint            `json:"id"`
+	UserID      uint            `json:"userId"`
+	WorkspaceID *uint           `json:"workspaceId,omitempty"`
+	AssigneeID  *uint           `json:"assigneeId,omitempty"`
+	ProjectID   *uint           `json:"projectId"`
+	ParentID    *uint           `json:"parentId,omitempty"`
+	SeriesID    *uint           `json:"seriesId,omitempty"`
+	Occurrence  int             `json:"occurrence,omitempty"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+	Deadline    time.Time       `json:"deadline"`
+	Status      models.Status   `json:"status"`
+	Priority    models.Priority `json:"priority"`
+	CompletedAt *time.Time      `json:"completedAt,omitempty"`
+	ArchivedAt  *time.Time      `json:"archivedAt,omitempty"`
+	DeletedAt   *time.Time      `json:"deletedAt,omitempty"`
+	Tags        []string        `json:"tags"`
+	BlockedBy   []uint          `json:"blockedBy"`
+	Blocks      []uint          `json:"blocks"`
+}
+
+// NewTaskResponse - задача в том виде, в каком её видят клиенты API и получатели вебхуков. Пустые списки отдаются как [], а не null
+func NewTaskResponse(task models.Task) TaskResponse {
+	resp := TaskResponse{
+		ID:          task.ID,
+		UserID:      task.UserID,
+		WorkspaceID: task.WorkspaceID,
+		AssigneeID:  task.AssigneeID,
+		ProjectID:   task.ProjectID,
+		ParentID:    task.ParentID,
+		SeriesID:    task.SeriesID,
+		Occurrence:  task.Occurrence,
+		Name:        task.Name,
+		Description: task.Description,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+		Deadline:    task.Deadline,
+		Status:      task.Status,
+		Priority:    task.Priority,
+		CompletedAt: task.CompletedAt,
+		ArchivedAt:  task.ArchivedAt,
+		DeletedAt:   task.DeletedAt,
+		Tags:        task.Tags,
+		BlockedBy:   task.BlockedBy,
+		Blocks:      task.Blocks,
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+	if resp.BlockedBy == nil {
+		resp.BlockedBy = []uint{}
+	}
+	if resp.Blocks == nil {
+		resp.Blocks = []uint{}
+	}
+	return resp
+}
+
+func NewTaskResponses(tasks []models.Task) []TaskResponse {
+	resp := make([]TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		resp = append(resp, NewTaskResponse(task))
+	}
+	return resp
+}
+
+// AssignTaskRequest: исполнитель должен иметь право редактировать задачу
+type AssignTaskRequest struct {
+	UserID uint `json:"userId" validate:"required"`
 }
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+	"to-do-list/internal/models"
+)
+
+type CreateWebhookRequest struct {
+	URL    string                `json:"url" validate:"required,url,max=500"`
+	Events []models.WebhookEvent `json:"events" validate:"required,min=1,max=5,dive,oneof=task.created task.updated task.deleted task.status_changed task.reminder"`
+}
+
+// UpdateWebhookRequest: nil-поля не меняются, Active = false приостанавливает доставку
+type UpdateWebhookRequest struct {
+	URL    *string                `json:"url" validate:"omitempty,url,max=500"`
+	Events *[]models.WebhookEvent `json:"events" validate:"omitempty,min=1,max=5,dive,oneof=task.created task.updated task.deleted task.status_changed task.reminder"`
+	Active *bool                  `json:"active"`
+}
+
+// WebhookResponse: Secret возвращается только при создании, им получатель проверяет подпись
+type WebhookResponse struct {
+	ID        uint                  `json:"id"`
+	URL       string                `json:"url"`
+	Events    []models.WebhookEvent `json:"events"`
+	Active    bool                  `json:"active"`
+	Secret    string                `json:"secret,omitempty"`
+	CreatedAt time.Time             `json:"createdAt"`
+	UpdatedAt time.Time             `json:"updatedAt"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uint                  `json:"id"`
+	Event          models.WebhookEvent   `json:"event"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         models.DeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"nextAttemptAt,omitempty"`
+	LastStatusCode *int                  `json:"lastStatusCode,omitempty"`
+	LastError      *string               `json:"lastError,omitempty"`
+	CreatedAt      time.Time             `json:"createdAt"`
+	DeliveredAt    *time.Time            `json:"deliveredAt,omitempty"`
+}
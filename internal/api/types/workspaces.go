@@ -0,0 +1,58 @@
+package types
+
+import (
+	"time"
+	"to-do-list/internal/models"
+)
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+type UpdateWorkspaceRequest struct {
+	Name *string `json:"name" validate:"omitempty,min=1,max=50"`
+}
+
+// WorkspaceResponse: Role - роль текущего пользователя в пространстве
+type WorkspaceResponse struct {
+	ID        uint                 `json:"id"`
+	Name      string               `json:"name"`
+	OwnerID   uint                 `json:"ownerId"`
+	Role      models.WorkspaceRole `json:"role"`
+	CreatedAt time.Time            `json:"createdAt"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+}
+
+type WorkspaceMemberResponse struct {
+	WorkspaceID uint                 `json:"workspaceId"`
+	UserID      uint                 `json:"userId"`
+	Username    string               `json:"username"`
+	Role        models.WorkspaceRole `json:"role"`
+	JoinedAt    time.Time            `json:"joinedAt"`
+}
+
+// UpdateMemberRequest: владельца назначить нельзя, он единственный
+type UpdateMemberRequest struct {
+	Role models.WorkspaceRole `json:"role" validate:"required,oneof=admin member"`
+}
+
+type CreateInvitationRequest struct {
+	Email string               `json:"email" validate:"required,email,max=100"`
+	Role  models.WorkspaceRole `json:"role" validate:"required,oneof=admin member"`
+}
+
+// InvitationResponse: AcceptToken возвращается только при создании приглашения
+type InvitationResponse struct {
+	ID          uint                 `json:"id"`
+	WorkspaceID uint                 `json:"workspaceId"`
+	Email       string               `json:"email"`
+	Role        models.WorkspaceRole `json:"role"`
+	InvitedBy   *uint                `json:"invitedBy,omitempty"`
+	ExpiresAt   time.Time            `json:"expiresAt"`
+	CreatedAt   time.Time            `json:"createdAt"`
+	AcceptToken string               `json:"acceptToken,omitempty"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required,max=100"`
+}
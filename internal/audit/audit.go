@@ -0,0 +1,186 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"reflect"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Recorder пишет события аудита. Ошибка записи не должна ронять сам запрос, поэтому она только логируется
+type Recorder struct {
+	repo repository.AuditRepository
+}
+
+func NewRecorder(repo repository.AuditRepository) *Recorder {
+	return &Recorder{repo: repo}
+}
+
+func (rec *Recorder) Record(ctx context.Context, event models.AuditEvent) {
+	err := rec.repo.CreateAuditEvent(ctx, &event)
+	if err != nil {
+		slog.Error("Failed to record audit event",
+			slog.String("action", string(event.Action)),
+			slog.String("request_id", event.RequestID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// eventActions - доменные события, которые попадают в журнал. task.status_changed и task.assigned
+// не нужны: то же изменение уже записано как task.updated
+var eventActions = map[models.EventType]models.AuditAction{
+	models.EventTaskCreated:         models.AuditTaskCreated,
+	models.EventTaskUpdated:         models.AuditTaskUpdated,
+	models.EventTaskDeleted:         models.AuditTaskDeleted,
+	models.EventUserRegistered:      models.AuditUserRegistered,
+	models.EventUserPasswordChanged: models.AuditPasswordChanged,
+}
+
+// Subscriber пишет в журнал доменные события из outbox. В отличие от Recorder ошибку возвращает,
+// чтобы диспетчер повторил событие
+type Subscriber struct {
+	repo repository.AuditRepository
+}
+
+func NewSubscriber(repo repository.AuditRepository) *Subscriber {
+	return &Subscriber{repo: repo}
+}
+
+func (s *Subscriber) Name() string {
+	return "audit"
+}
+
+func (s *Subscriber) Handle(ctx context.Context, event models.DomainEvent) error {
+	action, ok := eventActions[event.Type]
+	if !ok {
+		return nil
+	}
+
+	entry := models.AuditEvent{
+		UserID:     event.ActorID,
+		Action:     action,
+		EntityType: event.AggregateType,
+		EntityID:   &event.AggregateID,
+		RequestID:  event.RequestID,
+		IP:         event.IP,
+	}
+
+	if event.AggregateType == "task" {
+		payload, err := event.TaskPayload()
+		if err != nil {
+			return fmt.Errorf("audit: failed to decode event payload: %w", err)
+		}
+
+		entry.Action = trashAction(action, payload)
+
+		switch {
+		case payload.Before != nil && payload.After != nil:
+			entry.Before, entry.After, err = Diff(payload.Before, payload.After)
+			if err != nil {
+				return err
+			}
+		case payload.After != nil:
+			entry.After = Snapshot(payload.After)
+		case payload.Before != nil:
+			entry.Before = Snapshot(payload.Before)
+		}
+	}
+
+	return s.repo.CreateAuditEvent(ctx, &entry)
+}
+
+// trashAction различает операции с корзиной: восстановление приходит как task.updated, у которого
+// пропал DeletedAt, окончательное удаление - как task.deleted уже удалённой задачи
+func trashAction(action models.AuditAction, payload models.TaskEventPayload) models.AuditAction {
+	switch {
+	case action == models.AuditTaskUpdated && payload.Before != nil && payload.After != nil &&
+		payload.Before.DeletedAt != nil && payload.After.DeletedAt == nil:
+		return models.AuditTaskRestored
+	case action == models.AuditTaskDeleted && payload.Before != nil && payload.Before.DeletedAt != nil:
+		return models.AuditTaskPurged
+	}
+	return action
+}
+
+// NewEvent заполняет то, что известно из самого запроса: request_id и адрес клиента
+func NewEvent(r *http.Request, action models.AuditAction) models.AuditEvent {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return models.AuditEvent{
+		Action:    action,
+		RequestID: middleware.GetReqID(r.Context()),
+		IP:        ip,
+	}
+}
+
+// Diff сравнивает JSON-представления двух значений и возвращает только отличающиеся поля
+func Diff(before, after any) (json.RawMessage, json.RawMessage, error) {
+	beforeMap, err := toMap(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterMap, err := toMap(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changedBefore := map[string]any{}
+	changedAfter := map[string]any{}
+
+	for key, value := range afterMap {
+		if !reflect.DeepEqual(beforeMap[key], value) {
+			changedBefore[key] = beforeMap[key]
+			changedAfter[key] = value
+		}
+	}
+	for key, value := range beforeMap {
+		if _, ok := afterMap[key]; !ok {
+			changedBefore[key] = value
+		}
+	}
+
+	b, err := json.Marshal(changedBefore)
+	if err != nil {
+		return nil, nil, fmt.Errorf("audit: failed to marshal diff: %w", err)
+	}
+	a, err := json.Marshal(changedAfter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("audit: failed to marshal diff: %w", err)
+	}
+
+	return b, a, nil
+}
+
+// Snapshot - JSON значения целиком, для событий создания/удаления
+func Snapshot(v any) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func toMap(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to marshal value: %w", err)
+	}
+
+	m := map[string]any{}
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to unmarshal value: %w", err)
+	}
+	return m, nil
+}
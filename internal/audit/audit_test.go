@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	before := models.Task{ID: 1, Name: "Old name", Description: "same", Status: models.StatusPending}
+	after := before
+	after.Name = "New name"
+	after.Status = models.StatusInProgress
+
+	b, a, err := Diff(before, after)
+	require.NoError(t, err)
+
+	var beforeDiff, afterDiff map[string]any
+	require.NoError(t, json.Unmarshal(b, &beforeDiff))
+	require.NoError(t, json.Unmarshal(a, &afterDiff))
+
+	assert.Equal(t, map[string]any{"Name": "Old name", "Status": "pending"}, beforeDiff)
+	assert.Equal(t, map[string]any{"Name": "New name", "Status": "in progress"}, afterDiff)
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	task := models.Task{ID: 1, Name: "Task"}
+
+	b, a, err := Diff(task, task)
+	require.NoError(t, err)
+	assert.JSONEq(t, "{}", string(b))
+	assert.JSONEq(t, "{}", string(a))
+}
+
+type fakeAuditRepository struct {
+	repository.AuditRepository
+	events []models.AuditEvent
+}
+
+func (f *fakeAuditRepository) CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	f.events = append(f.events, *event)
+	return nil
+}
+
+func TestSubscriber_Handle(t *testing.T) {
+	repo := &fakeAuditRepository{}
+	subscriber := NewSubscriber(repo)
+	actorID := uint(10)
+
+	before := models.Task{ID: 1, UserID: 10, Name: "Old name", Status: models.StatusPending}
+	after := before
+	after.Name = "New name"
+	after.Status = models.StatusInProgress
+
+	for _, event := range models.TaskUpdateEvents(before, after) {
+		event.ActorID = &actorID
+		event.RequestID = "req-1"
+		require.NoError(t, subscriber.Handle(context.Background(), event))
+	}
+
+	// task.status_changed дублирует task.updated и в журнал не попадает
+	require.Len(t, repo.events, 1)
+	entry := repo.events[0]
+	assert.Equal(t, models.AuditTaskUpdated, entry.Action)
+	assert.Equal(t, &actorID, entry.UserID)
+	assert.Equal(t, "task", entry.EntityType)
+	assert.Equal(t, "req-1", entry.RequestID)
+	assert.JSONEq(t, `{"Name":"Old name","Status":"pending"}`, string(entry.Before))
+	assert.JSONEq(t, `{"Name":"New name","Status":"in progress"}`, string(entry.After))
+}
+
+func TestSubscriber_HandleTrashEvents(t *testing.T) {
+	repo := &fakeAuditRepository{}
+	subscriber := NewSubscriber(repo)
+
+	deletedAt := time.Now()
+	trashed := models.Task{ID: 1, UserID: 10, DeletedAt: &deletedAt}
+	restored := models.Task{ID: 1, UserID: 10}
+
+	require.NoError(t, subscriber.Handle(context.Background(), models.NewTaskEvent(models.EventTaskDeleted, &restored, nil)))
+	require.NoError(t, subscriber.Handle(context.Background(), models.NewTaskEvent(models.EventTaskUpdated, &trashed, &restored)))
+	require.NoError(t, subscriber.Handle(context.Background(), models.NewTaskEvent(models.EventTaskDeleted, &trashed, nil)))
+
+	require.Len(t, repo.events, 3)
+	assert.Equal(t, models.AuditTaskDeleted, repo.events[0].Action)
+	assert.Equal(t, models.AuditTaskRestored, repo.events[1].Action)
+	assert.Equal(t, models.AuditTaskPurged, repo.events[2].Action)
+}
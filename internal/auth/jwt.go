@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 	"to-do-list/internal/models"
@@ -15,6 +17,12 @@ type TokenManager struct {
 	tokenDuration time.Duration
 }
 
+// Claims - то, что достаём из валидного токена. TokenID (jti) связывает токен с сессией
+type Claims struct {
+	UserID  uint
+	TokenID string
+}
+
 func NewTokenManager(secretKey string, tokenDuration time.Duration) *TokenManager {
 	return &TokenManager{
 		secretKey:     secretKey,
@@ -22,11 +30,33 @@ func NewTokenManager(secretKey string, tokenDuration time.Duration) *TokenManage
 	}
 }
 
+func (tm *TokenManager) TokenDuration() time.Duration {
+	return tm.tokenDuration
+}
+
+func NewTokenID() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("auth: failed to generate token id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
 func (tm *TokenManager) GenerateToken(user models.User) (string, error) {
+	tokenID, err := NewTokenID()
+	if err != nil {
+		return "", err
+	}
+	return tm.GenerateTokenWithID(user, tokenID)
+}
+
+func (tm *TokenManager) GenerateTokenWithID(user models.User, tokenID string) (string, error) {
 	claims := jwt.MapClaims{
 		"exp": time.Now().Add(tm.tokenDuration).Unix(),
 		"iat": time.Now().Unix(),
 		"uid": user.ID,
+		"jti": tokenID,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -40,6 +70,14 @@ func (tm *TokenManager) GenerateToken(user models.User) (string, error) {
 }
 
 func (tm *TokenManager) ValidateToken(tokenString string) (uint, error) {
+	claims, err := tm.ParseToken(tokenString)
+	if err != nil {
+		return 0, err
+	}
+	return claims.UserID, nil
+}
+
+func (tm *TokenManager) ParseToken(tokenString string) (Claims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
 		if !ok {
@@ -49,18 +87,22 @@ func (tm *TokenManager) ValidateToken(tokenString string) (uint, error) {
 	})
 
 	if err != nil {
-		return 0, fmt.Errorf("auth: failed to parse token: %w", err)
+		return Claims{}, fmt.Errorf("auth: failed to parse token: %w", err)
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok || !token.Valid {
-		return 0, fmt.Errorf("auth: invalid token")
+		return Claims{}, fmt.Errorf("auth: invalid token")
 	}
 
 	// В JWT числа декодируются как float64
 	userIDFloat, ok := claims["uid"].(float64)
 	if !ok {
-		return 0, fmt.Errorf("auth: invalid user id type in token")
+		return Claims{}, fmt.Errorf("auth: invalid user id type in token")
 	}
-	return uint(userIDFloat), nil
+
+	// Токены, выданные до появления сессий, jti не содержат. Там, где сессии проверяются, такие токены не принимаются
+	tokenID, _ := claims["jti"].(string)
+
+	return Claims{UserID: uint(userIDFloat), TokenID: tokenID}, nil
 }
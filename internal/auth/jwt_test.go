@@ -25,6 +25,16 @@ func TestTokenManager(t *testing.T) {
 		assert.Equal(t, user.ID, userID, "User ID from token should match the original")
 	})
 
+	t.Run("Token ID is preserved", func(t *testing.T) {
+		tokenString, err := tm.GenerateTokenWithID(user, "session-jti")
+		require.NoError(t, err)
+
+		claims, err := tm.ParseToken(tokenString)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, claims.UserID)
+		assert.Equal(t, "session-jti", claims.TokenID)
+	})
+
 	t.Run("Validation errors", func(t *testing.T) {
 		tmWithAnotherKey := NewTokenManager("another-secret", tokenDuration)
 		tokenWithAnotherKey, err := tmWithAnotherKey.GenerateToken(user)
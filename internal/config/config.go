@@ -11,12 +11,28 @@ type Config struct {
 	Server ServerCfg `env-prefix:"SERVER_"`
 	DB     DBCfg     `env-prefix:"DB_"`
 	JWT    JWTCfg    `env-prefix:"JWT_"`
+
+	Overdue OverdueCfg `env-prefix:"OVERDUE_"`
+	Tasks   TasksCfg   `env-prefix:"TASKS_"`
+	Trash   TrashCfg   `env-prefix:"TRASH_"`
+	Archive ArchiveCfg `env-prefix:"ARCHIVE_"`
+	Storage StorageCfg `env-prefix:"STORAGE_"`
+
+	Workspaces WorkspacesCfg `env-prefix:"WORKSPACES_"`
+	Webhooks   WebhooksCfg   `env-prefix:"WEBHOOKS_"`
+	Events     EventsCfg     `env-prefix:"EVENTS_"`
+	Reminders  RemindersCfg  `env-prefix:"REMINDERS_"`
+	SMTP       SMTPCfg       `env-prefix:"SMTP_"`
+	Stream     StreamCfg     `env-prefix:"STREAM_"`
+	WS         WSCfg         `env-prefix:"WS_"`
 }
 
 type ServerCfg struct {
 	Port        string        `env:"PORT" env-required:"true"`
 	Timeout     time.Duration `env:"TIMEOUT" env-required:"true"`
 	IdleTimeout time.Duration `env:"IDLETIMEOUT" env-required:"true"`
+
+	ShutdownTimeout time.Duration `env:"SHUTDOWNTIMEOUT" env-default:"15s"`
 }
 
 type DBCfg struct {
@@ -36,6 +52,125 @@ type JWTCfg struct {
 	TTL       time.Duration `env:"TTL" env-required:"true"`
 }
 
+// OverdueCfg - фоновая пометка просроченных задач как failed
+type OverdueCfg struct {
+	Enabled     bool          `env:"ENABLED" env-default:"true"`
+	Interval    time.Duration `env:"INTERVAL" env-default:"1m"`
+	GracePeriod time.Duration `env:"GRACEPERIOD" env-default:"0s"`
+}
+
+// TrashCfg - фоновая очистка корзины от задач старше Retention
+type TrashCfg struct {
+	PurgeEnabled bool          `env:"PURGEENABLED" env-default:"true"`
+	Interval     time.Duration `env:"INTERVAL" env-default:"1h"`
+	Retention    time.Duration `env:"RETENTION" env-default:"720h"`
+}
+
+// ArchiveCfg - автоматическая архивация задач через After после завершения. По умолчанию выключена
+type ArchiveCfg struct {
+	AutoEnabled bool          `env:"AUTOENABLED" env-default:"false"`
+	Interval    time.Duration `env:"INTERVAL" env-default:"1h"`
+	After       time.Duration `env:"AFTER" env-default:"168h"`
+}
+
+// StorageCfg - где хранятся вложения (local или s3) и какие файлы принимаются.
+// AllowedTypes - MIME-типы через запятую, допускаются маски вида image/*
+type StorageCfg struct {
+	Driver   string `env:"DRIVER" env-default:"local"`
+	LocalDir string `env:"LOCALDIR" env-default:"data/attachments"`
+
+	S3Endpoint  string `env:"S3ENDPOINT"`
+	S3Bucket    string `env:"S3BUCKET"`
+	S3Region    string `env:"S3REGION" env-default:"us-east-1"`
+	S3AccessKey string `env:"S3ACCESSKEY"`
+	S3SecretKey string `env:"S3SECRETKEY"`
+
+	MaxSize      int64    `env:"MAXSIZE" env-default:"10485760"`
+	AllowedTypes []string `env:"ALLOWEDTYPES" env-default:"image/*,application/pdf,text/plain"`
+}
+
+// WorkspacesCfg - сколько действует приглашение в пространство
+type WorkspacesCfg struct {
+	InvitationTTL time.Duration `env:"INVITATIONTTL" env-default:"168h"`
+}
+
+// WebhooksCfg - фоновая доставка вебхуков. Неудачная доставка повторяется через BaseBackoff,
+// 2*BaseBackoff и так далее (не больше MaxBackoff), всего MaxAttempts попыток.
+// Вебхуки на частные, loopback и link-local адреса запрещены, кроме сетей из AllowedNetworks
+type WebhooksCfg struct {
+	DeliveryEnabled bool          `env:"DELIVERYENABLED" env-default:"true"`
+	Interval        time.Duration `env:"INTERVAL" env-default:"5s"`
+	BatchSize       int           `env:"BATCHSIZE" env-default:"50"`
+	Timeout         time.Duration `env:"TIMEOUT" env-default:"10s"`
+	MaxAttempts     int           `env:"MAXATTEMPTS" env-default:"8"`
+	BaseBackoff     time.Duration `env:"BASEBACKOFF" env-default:"30s"`
+	MaxBackoff      time.Duration `env:"MAXBACKOFF" env-default:"6h"`
+	AllowedNetworks []string      `env:"ALLOWEDNETWORKS" env-separator:","`
+}
+
+// EventsCfg - раздача доменных событий из outbox подписчикам: аудиту, вебхукам, уведомлениям.
+// Упавший подписчик повторяется с той же схемой задержек, что и доставка вебхуков.
+// Обработанные события хранятся Retention, неудавшиеся - пока их не удалят вручную
+type EventsCfg struct {
+	DispatchEnabled bool          `env:"DISPATCHENABLED" env-default:"true"`
+	Interval        time.Duration `env:"INTERVAL" env-default:"1s"`
+	BatchSize       int           `env:"BATCHSIZE" env-default:"100"`
+	Lease           time.Duration `env:"LEASE" env-default:"1m"`
+	MaxAttempts     int           `env:"MAXATTEMPTS" env-default:"10"`
+	BaseBackoff     time.Duration `env:"BASEBACKOFF" env-default:"5s"`
+	MaxBackoff      time.Duration `env:"MAXBACKOFF" env-default:"1h"`
+	Retention       time.Duration `env:"RETENTION" env-default:"168h"`
+	CleanupInterval time.Duration `env:"CLEANUPINTERVAL" env-default:"1h"`
+}
+
+// RemindersCfg - фоновая рассылка напоминаний о дедлайнах
+type RemindersCfg struct {
+	Enabled   bool          `env:"ENABLED" env-default:"true"`
+	Interval  time.Duration `env:"INTERVAL" env-default:"1m"`
+	BatchSize int           `env:"BATCHSIZE" env-default:"100"`
+}
+
+// SMTPCfg - почтовый канал уведомлений. Без Host письма не отправляются; для разработки
+// подходит MailHog из docker-compose, ему не нужна авторизация
+type SMTPCfg struct {
+	Host     string `env:"HOST"`
+	Port     int    `env:"PORT" env-default:"1025"`
+	From     string `env:"FROM" env-default:"todo@localhost"`
+	Username string `env:"USERNAME"`
+	Password string `env:"PASSWORD"`
+}
+
+// StreamCfg - поток изменений задач через SSE. Backend local раздаёт события, разобранные диспетчером
+// этой реплики (нужен EVENTS_DISPATCHENABLED), и годится для одной реплики; postgres получает все
+// события через LISTEN/NOTIFY. BufferSize - сколько событий ждут медленного клиента, прежде чем его отключить
+type StreamCfg struct {
+	Backend      string        `env:"BACKEND" env-default:"local"`
+	Heartbeat    time.Duration `env:"HEARTBEAT" env-default:"15s"`
+	BufferSize   int           `env:"BUFFERSIZE" env-default:"64"`
+	ReplayLimit  int           `env:"REPLAYLIMIT" env-default:"500"`
+	WriteTimeout time.Duration `env:"WRITETIMEOUT" env-default:"10s"`
+}
+
+// WSCfg - WebSocket для совместной работы (/ws). События приходят из того же источника, что и поток
+// SSE (STREAM_BACKEND). Клиент, не ответивший на ping за PongTimeout, отключается. Доступ к задаче
+// перепроверяется перед отправкой события, AccessTTL - сколько держится результат проверки
+type WSCfg struct {
+	MaxConnsPerUser int           `env:"MAXCONNSPERUSER" env-default:"5"`
+	MaxTopics       int           `env:"MAXTOPICS" env-default:"100"`
+	BufferSize      int           `env:"BUFFERSIZE" env-default:"64"`
+	PingInterval    time.Duration `env:"PINGINTERVAL" env-default:"30s"`
+	PongTimeout     time.Duration `env:"PONGTIMEOUT" env-default:"10s"`
+	AuthTimeout     time.Duration `env:"AUTHTIMEOUT" env-default:"10s"`
+	WriteTimeout    time.Duration `env:"WRITETIMEOUT" env-default:"10s"`
+	MaxMessageSize  int           `env:"MAXMESSAGESIZE" env-default:"4096"`
+	AccessTTL       time.Duration `env:"ACCESSTTL" env-default:"30s"`
+}
+
+// TasksCfg - правила работы с задачами
+type TasksCfg struct {
+	RequireSubtasksCompleted bool `env:"REQUIRESUBTASKSCOMPLETED" env-default:"false"`
+}
+
 func NewConfig() (*Config, error) {
 	err := godotenv.Load(".env")
 	if err != nil {
@@ -0,0 +1,127 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+	"to-do-list/internal/models"
+)
+
+type Store interface {
+	ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]models.DomainEvent, error)
+	MarkEventHandled(ctx context.Context, id uint, subscriber string) error
+	CompleteEvent(ctx context.Context, id uint) error
+	FailEvent(ctx context.Context, id uint, reason string, retryAt *time.Time) error
+}
+
+// Subscriber получает доменные события. Доставка at-least-once: если диспетчер упадёт между обработкой
+// и отметкой о ней, событие придёт повторно. Name попадает в outbox_events.handled и не должен меняться
+type Subscriber interface {
+	Name() string
+	Handle(ctx context.Context, event models.DomainEvent) error
+}
+
+// DispatcherConfig: Lease должен покрывать обработку всей пачки, иначе её заберёт другая реплика
+type DispatcherConfig struct {
+	BatchSize   int
+	Lease       time.Duration
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Dispatcher разбирает outbox и раздаёт события подписчикам. Событие считается обработанным, когда
+// его приняли все подписчики; упавшие повторяются с экспоненциальной задержкой, успевшие - нет
+type Dispatcher struct {
+	store       Store
+	subscribers []Subscriber
+	cfg         DispatcherConfig
+}
+
+func NewDispatcher(store Store, cfg DispatcherConfig, subscribers ...Subscriber) *Dispatcher {
+	return &Dispatcher{store: store, subscribers: subscribers, cfg: cfg}
+}
+
+// Backoff - задержка перед попыткой attempt+1: BaseBackoff, 2*BaseBackoff, 4*BaseBackoff... но не больше MaxBackoff
+func (d *Dispatcher) Backoff(attempt int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return min(delay, d.cfg.MaxBackoff)
+}
+
+// Dispatch разбирает одну пачку событий и возвращает число полностью обработанных
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	claimed, err := d.store.ClaimEvents(ctx, d.cfg.BatchSize, d.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, event := range claimed {
+		if ctx.Err() != nil {
+			return processed, ctx.Err()
+		}
+
+		err := d.dispatch(ctx, event)
+		if err == nil {
+			err = d.store.CompleteEvent(ctx, event.ID)
+			if err != nil {
+				slog.Error("Failed to mark outbox event processed", slog.Any("eventID", event.ID), slog.Any("error", err))
+				continue
+			}
+			processed++
+			continue
+		}
+
+		var retryAt *time.Time
+		if event.Attempts < d.cfg.MaxAttempts {
+			next := time.Now().Add(d.Backoff(event.Attempts))
+			retryAt = &next
+		} else {
+			slog.Error("Outbox event failed, giving up",
+				slog.Any("eventID", event.ID),
+				slog.String("type", string(event.Type)),
+				slog.Any("error", err),
+			)
+		}
+
+		err = d.store.FailEvent(ctx, event.ID, err.Error(), retryAt)
+		if err != nil {
+			slog.Error("Failed to record outbox event failure", slog.Any("eventID", event.ID), slog.Any("error", err))
+		}
+	}
+
+	return processed, nil
+}
+
+// dispatch вызывает подписчиков, которые ещё не обработали событие. Ошибка одного не мешает остальным
+func (d *Dispatcher) dispatch(ctx context.Context, event models.DomainEvent) error {
+	var errs []error
+
+	for _, sub := range d.subscribers {
+		if slices.Contains(event.Handled, sub.Name()) {
+			continue
+		}
+
+		err := sub.Handle(ctx, event)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.Name(), err))
+			continue
+		}
+
+		err = d.store.MarkEventHandled(ctx, event.ID, sub.Name())
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,127 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	claimed   []models.DomainEvent
+	handled   map[uint][]string
+	completed []uint
+	failed    map[uint]*time.Time
+}
+
+func newFakeStore(events ...models.DomainEvent) *fakeStore {
+	return &fakeStore{claimed: events, handled: map[uint][]string{}, failed: map[uint]*time.Time{}}
+}
+
+func (f *fakeStore) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]models.DomainEvent, error) {
+	claimed := f.claimed
+	f.claimed = nil
+	for i := range claimed {
+		claimed[i].Attempts++
+	}
+	return claimed, nil
+}
+
+func (f *fakeStore) MarkEventHandled(ctx context.Context, id uint, subscriber string) error {
+	f.handled[id] = append(f.handled[id], subscriber)
+	return nil
+}
+
+func (f *fakeStore) CompleteEvent(ctx context.Context, id uint) error {
+	f.completed = append(f.completed, id)
+	return nil
+}
+
+func (f *fakeStore) FailEvent(ctx context.Context, id uint, reason string, retryAt *time.Time) error {
+	f.failed[id] = retryAt
+	return nil
+}
+
+type fakeSubscriber struct {
+	name   string
+	err    error
+	events []models.DomainEvent
+}
+
+func (f *fakeSubscriber) Name() string {
+	return f.name
+}
+
+func (f *fakeSubscriber) Handle(ctx context.Context, event models.DomainEvent) error {
+	f.events = append(f.events, event)
+	return f.err
+}
+
+var testConfig = DispatcherConfig{BatchSize: 10, Lease: time.Minute, MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: time.Minute}
+
+func TestDispatcher_FansOutToSubscribers(t *testing.T) {
+	store := newFakeStore(models.DomainEvent{ID: 1, Type: models.EventTaskCreated}, models.DomainEvent{ID: 2, Type: models.EventTaskDeleted})
+	audit := &fakeSubscriber{name: "audit"}
+	webhooks := &fakeSubscriber{name: "webhooks"}
+	dispatcher := NewDispatcher(store, testConfig, audit, webhooks)
+
+	processed, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, processed)
+	assert.Len(t, audit.events, 2)
+	assert.Len(t, webhooks.events, 2)
+	assert.Equal(t, []uint{1, 2}, store.completed)
+	assert.Equal(t, []string{"audit", "webhooks"}, store.handled[1])
+}
+
+func TestDispatcher_RetriesOnlyFailedSubscribers(t *testing.T) {
+	store := newFakeStore(models.DomainEvent{ID: 1, Type: models.EventTaskCreated})
+	audit := &fakeSubscriber{name: "audit"}
+	webhooks := &fakeSubscriber{name: "webhooks", err: errors.New("db is down")}
+	dispatcher := NewDispatcher(store, testConfig, audit, webhooks)
+
+	processed, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+
+	assert.Zero(t, processed)
+	assert.Empty(t, store.completed)
+	require.Contains(t, store.failed, uint(1))
+	require.NotNil(t, store.failed[1])
+	assert.WithinDuration(t, time.Now().Add(time.Second), *store.failed[1], time.Second)
+	assert.Equal(t, []string{"audit"}, store.handled[1])
+
+	// Повтор: audit уже отметился и второй раз событие не получит
+	webhooks.err = nil
+	store.claimed = []models.DomainEvent{{ID: 1, Type: models.EventTaskCreated, Handled: store.handled[1], Attempts: 1}}
+
+	processed, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, processed)
+	assert.Len(t, audit.events, 1)
+	assert.Len(t, webhooks.events, 2)
+}
+
+func TestDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	store := newFakeStore(models.DomainEvent{ID: 1, Type: models.EventTaskCreated, Attempts: testConfig.MaxAttempts - 1})
+	dispatcher := NewDispatcher(store, testConfig, &fakeSubscriber{name: "webhooks", err: errors.New("db is down")})
+
+	_, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+
+	require.Contains(t, store.failed, uint(1))
+	assert.Nil(t, store.failed[1])
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	dispatcher := NewDispatcher(newFakeStore(), DispatcherConfig{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second})
+
+	assert.Equal(t, time.Second, dispatcher.Backoff(1))
+	assert.Equal(t, 4*time.Second, dispatcher.Backoff(3))
+	assert.Equal(t, 5*time.Second, dispatcher.Backoff(10))
+}
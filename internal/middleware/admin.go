@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+type AdminChecker interface {
+	IsAdmin(ctx context.Context, userID uint) (bool, error)
+}
+
+// RequireAdmin пропускает дальше только администраторов. Ставится после AuthMiddleware
+func RequireAdmin(checker AdminChecker) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value(UserIDKey).(uint)
+			if !ok {
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, map[string]string{"error": "Unauthorized"})
+				return
+			}
+
+			isAdmin, err := checker.IsAdmin(r.Context(), userID)
+			if err != nil {
+				slog.Error("Failed to check admin flag", slog.Any("error", err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, map[string]string{"error": "Internal server error"})
+				return
+			}
+
+			if !isAdmin {
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, map[string]string{"error": "Forbidden"})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAdminChecker struct {
+	isAdmin bool
+	err     error
+}
+
+func (s stubAdminChecker) IsAdmin(ctx context.Context, userID uint) (bool, error) {
+	return s.isAdmin, s.err
+}
+
+func TestRequireAdmin(t *testing.T) {
+	testCases := []struct {
+		name           string
+		checker        stubAdminChecker
+		withUser       bool
+		expectedStatus int
+	}{
+		{"Admin", stubAdminChecker{isAdmin: true}, true, http.StatusOK},
+		{"Regular user", stubAdminChecker{isAdmin: false}, true, http.StatusForbidden},
+		{"Checker error", stubAdminChecker{err: errors.New("db down")}, true, http.StatusInternalServerError},
+		{"No user in context", stubAdminChecker{isAdmin: true}, false, http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tc.withUser {
+				req = req.WithContext(context.WithValue(req.Context(), UserIDKey, uint(1)))
+			}
+			rr := httptest.NewRecorder()
+
+			RequireAdmin(tc.checker)(testHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
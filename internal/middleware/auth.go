@@ -2,19 +2,36 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 	"to-do-list/internal/auth"
+	"to-do-list/internal/repository"
 
 	"github.com/go-chi/render"
 )
 
 type CtxKey string
 
-const UserIDKey CtxKey = "userID"
+const (
+	UserIDKey  CtxKey = "userID"
+	TokenIDKey CtxKey = "tokenID"
+)
+
+// ErrInvalidToken - токен не принят: не разобрался, без jti или его сессия отозвана. Остальные ошибки
+// Authenticate - сбой проверки, на них отвечают 500, а не 401
+var ErrInvalidToken = errors.New("middleware: invalid token")
 
+// SessionChecker проверяет, что сессия токена не отозвана, и отмечает активность.
+// Отозванная или неизвестная сессия - repository.ErrSessionNotFound
+type SessionChecker interface {
+	TouchSession(ctx context.Context, tokenID string) error
+}
 
-func AuthMiddleware(tokenManager *auth.TokenManager) func(next http.Handler) http.Handler {
+// sessions может быть nil - тогда отзыв токенов не проверяется
+func AuthMiddleware(tokenManager *auth.TokenManager, sessions SessionChecker) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -33,16 +50,50 @@ func AuthMiddleware(tokenManager *auth.TokenManager) func(next http.Handler) htt
 
 			tokenString := headerParts[1]
 
-			userID, err := tokenManager.ValidateToken(tokenString)
-			if err != nil {
+			claims, err := Authenticate(r.Context(), tokenManager, sessions, tokenString)
+			if errors.Is(err, ErrInvalidToken) {
 				render.Status(r, http.StatusUnauthorized)
 				render.JSON(w, r, map[string]string{"error": "Invalid token"})
 				return
 			}
+			if err != nil {
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, map[string]string{"error": "Internal server error"})
+				return
+			}
 
-			ctx := context.WithValue(r.Context(), UserIDKey, userID)
+			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+			ctx = context.WithValue(ctx, TokenIDKey, claims.TokenID)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// Authenticate проверяет токен и, если передан sessions, что его сессия не отозвана. С sessions сессии
+// обязательны: токен без jti не привязан ни к какой сессии, отозвать его нельзя, поэтому он не принимается.
+// Нужен и там, где токен приходит не в заголовке, например в первом сообщении WebSocket
+func Authenticate(ctx context.Context, tokenManager *auth.TokenManager, sessions SessionChecker, token string) (auth.Claims, error) {
+	claims, err := tokenManager.ParseToken(token)
+	if err != nil {
+		return auth.Claims{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if sessions == nil {
+		return claims, nil
+	}
+	if claims.TokenID == "" {
+		return auth.Claims{}, fmt.Errorf("%w: token has no session id", ErrInvalidToken)
+	}
+
+	err = sessions.TouchSession(ctx, claims.TokenID)
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		return auth.Claims{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if err != nil {
+		slog.Error("Session check failed", slog.Any("error", err))
+		return auth.Claims{}, fmt.Errorf("middleware: failed to check session: %w", err)
+	}
+
+	return claims, nil
+}
@@ -1,12 +1,15 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 	"to-do-list/internal/auth"
 	"to-do-list/internal/models"
+	"to-do-list/internal/repository"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,7 +17,7 @@ import (
 
 func TestAuthMiddleware_Success(t *testing.T) {
 	tokenManager := auth.NewTokenManager("test-secret", time.Minute*15)
-	middleware := AuthMiddleware(tokenManager)
+	middleware := AuthMiddleware(tokenManager, nil)
 
 	testUser := models.User{ID: 123}
 	token, err := tokenManager.GenerateToken(testUser)
@@ -40,7 +43,7 @@ func TestAuthMiddleware_Success(t *testing.T) {
 
 func TestAuthMiddleware_Failure(t *testing.T) {
 	tokenManager := auth.NewTokenManager("test-secret", time.Minute*15)
-	middleware := AuthMiddleware(tokenManager)
+	middleware := AuthMiddleware(tokenManager, nil)
 
 	expiredTokenManager := auth.NewTokenManager("test-secret", -time.Minute)
 	expiredToken, _ := expiredTokenManager.GenerateToken(models.User{ID: 1})
@@ -98,3 +101,67 @@ func TestAuthMiddleware_Failure(t *testing.T) {
 		})
 	}
 }
+
+type stubSessionChecker struct {
+	err error
+}
+
+func (s stubSessionChecker) TouchSession(ctx context.Context, tokenID string) error {
+	return s.err
+}
+
+func TestAuthMiddleware_RevokedSession(t *testing.T) {
+	tokenManager := auth.NewTokenManager("test-secret", time.Minute*15)
+	middleware := AuthMiddleware(tokenManager, stubSessionChecker{err: repository.ErrSessionNotFound})
+
+	token, err := tokenManager.GenerateToken(models.User{ID: 1})
+	require.NoError(t, err)
+
+	handlerCalled := false
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+
+	middleware(testHandler).ServeHTTP(rr, req)
+
+	assert.False(t, handlerCalled, "Next handler should not be called for revoked session")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestAuthMiddleware_SessionChecks(t *testing.T) {
+	tokenManager := auth.NewTokenManager("test-secret", time.Minute*15)
+
+	withSession, err := tokenManager.GenerateToken(models.User{ID: 1})
+	require.NoError(t, err)
+	withoutSession, err := tokenManager.GenerateTokenWithID(models.User{ID: 1}, "")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		token        string
+		sessionErr   error
+		expectedCode int
+	}{
+		{"Active Session", withSession, nil, http.StatusOK},
+		{"Token Without Session ID", withoutSession, nil, http.StatusUnauthorized},
+		{"Session Store Failure", withSession, errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			middleware := AuthMiddleware(tokenManager, stubSessionChecker{err: tc.sessionErr})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			rr := httptest.NewRecorder()
+
+			middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"mime"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxFileNameLength = 255
+
+// TaskAttachment - метаданные файла. Само содержимое лежит в хранилище по StorageKey
+type TaskAttachment struct {
+	ID          uint
+	TaskID      uint
+	UserID      uint
+	FileName    string
+	ContentType string
+	Size        int64
+	StorageKey  string
+	CreatedAt   time.Time
+}
+
+// ContentTypeAllowed сравнивает тип без параметров со списком. Допускаются маски вида image/*
+func ContentTypeAllowed(contentType string, allowed []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// SanitizeFileName оставляет только базовое имя без управляющих символов. Пустое имя заменяется на "file"
+func SanitizeFileName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = path.Base(name)
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == ".." || name == "/" || !utf8.ValidString(name) {
+		return "file"
+	}
+
+	if utf8.RuneCountInString(name) > maxFileNameLength {
+		name = string([]rune(name)[:maxFileNameLength])
+	}
+	return name
+}
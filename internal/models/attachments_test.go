@@ -0,0 +1,29 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentTypeAllowed(t *testing.T) {
+	allowed := []string{"image/*", "application/pdf", " text/plain "}
+
+	assert.True(t, ContentTypeAllowed("image/png", allowed))
+	assert.True(t, ContentTypeAllowed("application/pdf", allowed))
+	assert.True(t, ContentTypeAllowed("text/plain; charset=utf-8", allowed))
+	assert.False(t, ContentTypeAllowed("application/zip", allowed))
+	assert.False(t, ContentTypeAllowed("imagex/png", allowed))
+	assert.False(t, ContentTypeAllowed("", allowed))
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	assert.Equal(t, "report.pdf", SanitizeFileName("report.pdf"))
+	assert.Equal(t, "passwd", SanitizeFileName("../../etc/passwd"))
+	assert.Equal(t, "shot.png", SanitizeFileName(`C:\Users\me\shot.png`))
+	assert.Equal(t, "evil.txt", SanitizeFileName("ev\"il\n.txt"))
+	assert.Equal(t, "file", SanitizeFileName(".."))
+	assert.Equal(t, "file", SanitizeFileName(""))
+	assert.Len(t, []rune(SanitizeFileName(strings.Repeat("я", 300))), maxFileNameLength)
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditLoginSuccess    AuditAction = "login.success"
+	AuditLoginFailure    AuditAction = "login.failure"
+	AuditUserRegistered  AuditAction = "user.registered"
+	AuditPasswordChanged AuditAction = "user.password_changed"
+	AuditTaskCreated     AuditAction = "task.created"
+	AuditTaskUpdated     AuditAction = "task.updated"
+	AuditTaskDeleted     AuditAction = "task.deleted"
+	AuditTaskRestored    AuditAction = "task.restored"
+	AuditTaskPurged      AuditAction = "task.purged"
+	AuditTaskShared      AuditAction = "task.shared"
+	AuditTaskUnshared    AuditAction = "task.unshared"
+)
+
+// AuditEvent - запись о том, кто и что изменил. Before/After содержат только изменённые поля
+type AuditEvent struct {
+	ID         uint
+	UserID     *uint
+	Action     AuditAction
+	EntityType string
+	EntityID   *uint
+	RequestID  string
+	IP         string
+	Before     json.RawMessage
+	After      json.RawMessage
+	Metadata   json.RawMessage
+	CreatedAt  time.Time
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxCommentLength - лимит тела комментария в символах, а не байтах
+const MaxCommentLength = 5000
+
+var (
+	ErrCommentEmpty    = errors.New("comment body is empty")
+	ErrCommentTooLong  = errors.New("comment body is too long")
+	ErrCommentBadChars = errors.New("comment body contains control characters")
+)
+
+type TaskComment struct {
+	ID        uint
+	TaskID    uint
+	UserID    uint
+	Body      string
+	CreatedAt time.Time
+	EditedAt  *time.Time
+}
+
+// NormalizeCommentBody готовит markdown к сохранению: переводы строк приводятся к \n,
+// пробелы по краям обрезаются, управляющие символы кроме \n и \t запрещены.
+// Сам markdown не меняется - экранирование остаётся за клиентом при отображении
+func NormalizeCommentBody(body string) (string, error) {
+	if !utf8.ValidString(body) {
+		return "", ErrCommentBadChars
+	}
+
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	body = strings.TrimSpace(body)
+
+	if body == "" {
+		return "", ErrCommentEmpty
+	}
+	if utf8.RuneCountInString(body) > MaxCommentLength {
+		return "", ErrCommentTooLong
+	}
+	for _, r := range body {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return "", ErrCommentBadChars
+		}
+	}
+
+	return body, nil
+}
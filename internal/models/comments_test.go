@@ -0,0 +1,38 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCommentBody(t *testing.T) {
+	body, err := NormalizeCommentBody("  **done**\r\n- step one\r\n\t- nested  \n")
+	require.NoError(t, err)
+	assert.Equal(t, "**done**\n- step one\n\t- nested", body)
+
+	body, err = NormalizeCommentBody(strings.Repeat("я", MaxCommentLength))
+	require.NoError(t, err)
+	assert.Len(t, []rune(body), MaxCommentLength)
+
+	testCases := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{"Empty", "", ErrCommentEmpty},
+		{"Whitespace Only", " \n\t ", ErrCommentEmpty},
+		{"Too Long", strings.Repeat("я", MaxCommentLength+1), ErrCommentTooLong},
+		{"Control Character", "bell\a", ErrCommentBadChars},
+		{"Invalid UTF-8", "bad \xff", ErrCommentBadChars},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NormalizeCommentBody(tc.body)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventTaskCreated         EventType = "task.created"
+	EventTaskUpdated         EventType = "task.updated"
+	EventTaskDeleted         EventType = "task.deleted"
+	EventTaskStatusChanged   EventType = "task.status_changed"
+	EventTaskAssigned        EventType = "task.assigned"
+	EventTaskShared          EventType = "task.shared"
+	EventUserRegistered      EventType = "user.registered"
+	EventUserPasswordChanged EventType = "user.password_changed"
+)
+
+type EventStatus string
+
+const (
+	EventPending   EventStatus = "pending"
+	EventProcessed EventStatus = "processed"
+	EventFailed    EventStatus = "failed"
+)
+
+// DomainEvent - факт изменения задачи или пользователя. Пишется в outbox в одной транзакции
+// с самим изменением. ActorID пуст для изменений, сделанных фоновыми задачами
+type DomainEvent struct {
+	ID            uint
+	Type          EventType
+	AggregateType string
+	AggregateID   uint
+	ActorID       *uint
+	RequestID     string
+	IP            string
+	Payload       json.RawMessage
+	Status        EventStatus
+	// Handled - подписчики, уже обработавшие событие; при повторе их не вызывают
+	Handled   []string
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// TaskEventPayload - тело событий задачи: у task.created есть только After, у task.deleted - только Before
+type TaskEventPayload struct {
+	Before *Task `json:"before,omitempty"`
+	After  *Task `json:"after,omitempty"`
+}
+
+// TaskShareEventPayload - тело task.shared: кому и с каким правом выдали доступ
+type TaskShareEventPayload struct {
+	Task       Task            `json:"task"`
+	UserID     uint            `json:"userId"`
+	Permission SharePermission `json:"permission"`
+}
+
+// UserEventPayload - тело событий пользователя, без пароля
+type UserEventPayload struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+func NewTaskEvent(eventType EventType, before, after *Task) DomainEvent {
+	event := DomainEvent{Type: eventType, AggregateType: "task"}
+	if after != nil {
+		event.AggregateID = after.ID
+	} else if before != nil {
+		event.AggregateID = before.ID
+	}
+	// Task состоит из простых полей, Marshal для него не возвращает ошибку
+	event.Payload, _ = json.Marshal(TaskEventPayload{Before: before, After: after})
+	return event
+}
+
+// TaskUpdateEvents - события изменения задачи: ничего, если она не изменилась, task.updated и отдельно
+// task.status_changed и task.assigned, если сменились статус или исполнитель
+func TaskUpdateEvents(before, after Task) []DomainEvent {
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+
+	events := []DomainEvent{NewTaskEvent(EventTaskUpdated, &before, &after)}
+	if before.Status != after.Status {
+		events = append(events, NewTaskEvent(EventTaskStatusChanged, &before, &after))
+	}
+	if after.AssigneeID != nil && (before.AssigneeID == nil || *before.AssigneeID != *after.AssigneeID) {
+		events = append(events, NewTaskEvent(EventTaskAssigned, &before, &after))
+	}
+	return events
+}
+
+func NewTaskShareEvent(task Task, share TaskShare) DomainEvent {
+	event := DomainEvent{Type: EventTaskShared, AggregateType: "task", AggregateID: task.ID}
+	event.Payload, _ = json.Marshal(TaskShareEventPayload{Task: task, UserID: share.UserID, Permission: share.Permission})
+	return event
+}
+
+func NewUserEvent(eventType EventType, user User) DomainEvent {
+	event := DomainEvent{Type: eventType, AggregateType: "user", AggregateID: user.ID}
+	event.Payload, _ = json.Marshal(UserEventPayload{ID: user.ID, Username: user.Username, Email: user.Email})
+	return event
+}
+
+func (e DomainEvent) TaskPayload() (TaskEventPayload, error) {
+	var payload TaskEventPayload
+	err := json.Unmarshal(e.Payload, &payload)
+	return payload, err
+}
+
+// TaskAudience - владелец задачи из события и её исполнители до и после изменения. Для событий
+// не о задаче и без before/after (например, task.shared) ok = false
+func (e DomainEvent) TaskAudience() (userID uint, assigneeIDs []uint, ok bool) {
+	if e.AggregateType != "task" {
+		return 0, nil, false
+	}
+
+	payload, err := e.TaskPayload()
+	if err != nil {
+		return 0, nil, false
+	}
+
+	task := payload.After
+	if task == nil {
+		task = payload.Before
+	}
+	if task == nil {
+		return 0, nil, false
+	}
+
+	for _, t := range []*Task{payload.After, payload.Before} {
+		if t != nil && t.AssigneeID != nil && !slices.Contains(assigneeIDs, *t.AssigneeID) {
+			assigneeIDs = append(assigneeIDs, *t.AssigneeID)
+		}
+	}
+	return task.UserID, assigneeIDs, true
+}
+
+func (e DomainEvent) SharePayload() (TaskShareEventPayload, error) {
+	var payload TaskShareEventPayload
+	err := json.Unmarshal(e.Payload, &payload)
+	return payload, err
+}
+
+func (e DomainEvent) UserPayload() (UserEventPayload, error) {
+	var payload UserEventPayload
+	err := json.Unmarshal(e.Payload, &payload)
+	return payload, err
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskUpdateEvents(t *testing.T) {
+	assigneeID := uint(20)
+	before := Task{ID: 1, UserID: 10, Name: "Task", Status: StatusPending}
+
+	t.Run("No Changes", func(t *testing.T) {
+		assert.Empty(t, TaskUpdateEvents(before, before))
+	})
+
+	t.Run("Status And Assignee Changed", func(t *testing.T) {
+		after := before
+		after.Status = StatusInProgress
+		after.AssigneeID = &assigneeID
+
+		events := TaskUpdateEvents(before, after)
+		require.Len(t, events, 3)
+		assert.Equal(t, EventTaskUpdated, events[0].Type)
+		assert.Equal(t, EventTaskStatusChanged, events[1].Type)
+		assert.Equal(t, EventTaskAssigned, events[2].Type)
+
+		payload, err := events[1].TaskPayload()
+		require.NoError(t, err)
+		assert.Equal(t, StatusPending, payload.Before.Status)
+		assert.Equal(t, StatusInProgress, payload.After.Status)
+	})
+
+	t.Run("Unassign Is Only An Update", func(t *testing.T) {
+		assigned := before
+		assigned.AssigneeID = &assigneeID
+
+		events := TaskUpdateEvents(assigned, before)
+		require.Len(t, events, 1)
+		assert.Equal(t, EventTaskUpdated, events[0].Type)
+	})
+}
+
+func TestNewUserEvent_OmitsPassword(t *testing.T) {
+	event := NewUserEvent(EventUserRegistered, User{ID: 3, Username: "user", Email: "user@example.com", Password: []byte("hash")})
+
+	assert.Equal(t, "user", event.AggregateType)
+	assert.Equal(t, uint(3), event.AggregateID)
+	assert.NotContains(t, string(event.Payload), "hash")
+}
+
+func TestDomainEvent_TaskAudience(t *testing.T) {
+	prev, next := uint(20), uint(30)
+	before := Task{ID: 1, UserID: 10, AssigneeID: &prev}
+	after := before
+	after.AssigneeID = &next
+
+	events := TaskUpdateEvents(before, after)
+	require.NotEmpty(t, events)
+	userID, assignees, ok := events[0].TaskAudience()
+	require.True(t, ok)
+	assert.Equal(t, uint(10), userID)
+	assert.ElementsMatch(t, []uint{20, 30}, assignees)
+
+	deleted := NewTaskEvent(EventTaskDeleted, &Task{ID: 1, UserID: 10}, nil)
+	userID, assignees, ok = deleted.TaskAudience()
+	require.True(t, ok)
+	assert.Equal(t, uint(10), userID)
+	assert.Empty(t, assignees)
+
+	_, _, ok = NewTaskShareEvent(before, TaskShare{UserID: 40}).TaskAudience()
+	assert.False(t, ok)
+	_, _, ok = NewUserEvent(EventUserRegistered, User{ID: 10}).TaskAudience()
+	assert.False(t, ok)
+}
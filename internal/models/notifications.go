@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type NotificationKind string
+
+const (
+	NotificationTaskAssigned NotificationKind = "task.assigned"
+	NotificationTaskReminder NotificationKind = "task.reminder"
+	NotificationTaskShared   NotificationKind = "task.shared"
+)
+
+// Notification - сообщение конкретному пользователю о событии, которое его касается.
+// Channels ограничивает способы доставки, пустой - все подключённые каналы. ReadAt есть только у сохранённых в приложении
+type Notification struct {
+	ID        uint
+	UserID    uint
+	Kind      NotificationKind
+	TaskID    *uint
+	Title     string
+	Body      string
+	Channels  []NotificationChannelName
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Project struct {
+	ID        uint
+	UserID    uint
+	Name      string
+	Color     string
+	Archived  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Границы срока напоминания: чаще раза в минуту планировщик не проверяет, а за месяц - уже не напоминание
+const (
+	MinReminderOffset = time.Minute
+	MaxReminderOffset = 30 * 24 * time.Hour
+)
+
+var ErrInvalidReminderOffset = errors.New("reminder offset must look like 30m, 2h or 1d and be between 1m and 30d")
+
+// ReminderOffset - за сколько до дедлайна напомнить. Снаружи записывается как 30m, 2h или 1d
+type ReminderOffset time.Duration
+
+func ParseReminderOffset(s string) (ReminderOffset, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return 0, ErrInvalidReminderOffset
+	}
+
+	var unit time.Duration
+	switch s[len(s)-1] {
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	default:
+		return 0, ErrInvalidReminderOffset
+	}
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidReminderOffset
+	}
+
+	offset := time.Duration(n) * unit
+	if offset < MinReminderOffset || offset > MaxReminderOffset {
+		return 0, ErrInvalidReminderOffset
+	}
+	return ReminderOffset(offset), nil
+}
+
+// ParseReminderOffsets разбирает список сроков, убирает повторы и сортирует от дальнего к ближнему
+func ParseReminderOffsets(values []string) ([]ReminderOffset, error) {
+	offsets := make([]ReminderOffset, 0, len(values))
+	for _, v := range values {
+		offset, err := ParseReminderOffset(v)
+		if err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, offset)
+	}
+
+	slices.SortFunc(offsets, func(a, b ReminderOffset) int { return cmp.Compare(b, a) })
+	return slices.Compact(offsets), nil
+}
+
+// String записывает срок самой крупной единицей, которой он делится нацело
+func (o ReminderOffset) String() string {
+	d := time.Duration(o)
+	switch {
+	case d%(24*time.Hour) == 0:
+		return strconv.Itoa(int(d/(24*time.Hour))) + "d"
+	case d%time.Hour == 0:
+		return strconv.Itoa(int(d/time.Hour)) + "h"
+	default:
+		return strconv.Itoa(int(d/time.Minute)) + "m"
+	}
+}
+
+func FormatReminderOffsets(offsets []ReminderOffset) []string {
+	values := make([]string, 0, len(offsets))
+	for _, o := range offsets {
+		values = append(values, o.String())
+	}
+	return values
+}
+
+// NotificationChannelName - способ доставки уведомления, который пользователь может выбрать для напоминаний
+type NotificationChannelName string
+
+const (
+	ChannelEmail   NotificationChannelName = "email"
+	ChannelWebhook NotificationChannelName = "webhook"
+	ChannelInApp   NotificationChannelName = "in_app"
+)
+
+// ReminderPreferences - напоминания по умолчанию для задач, где пользователь исполнитель (или владелец,
+// если исполнителя нет), а у самой задачи своих напоминаний нет. Пустой Channels - все каналы
+type ReminderPreferences struct {
+	UserID    uint
+	Offsets   []ReminderOffset
+	Channels  []NotificationChannelName
+	UpdatedAt time.Time
+}
+
+// DueReminder - напоминание, которое пора отправить. Channels берутся из настроек получателя
+type DueReminder struct {
+	TaskID   uint
+	UserID   uint
+	TaskName string
+	Offset   ReminderOffset
+	Deadline time.Time
+	Channels []NotificationChannelName
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReminderOffset(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Duration
+		valid    bool
+	}{
+		{"30m", 30 * time.Minute, true},
+		{"2h", 2 * time.Hour, true},
+		{"1d", 24 * time.Hour, true},
+		{"30d", 30 * 24 * time.Hour, true},
+		{"31d", 0, false},
+		{"0m", 0, false},
+		{"-1h", 0, false},
+		{"1w", 0, false},
+		{"h", 0, false},
+		{"1.5h", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			offset, err := ParseReminderOffset(tc.value)
+			if !tc.valid {
+				assert.ErrorIs(t, err, ErrInvalidReminderOffset)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, time.Duration(offset))
+		})
+	}
+}
+
+func TestParseReminderOffsets_SortsAndDeduplicates(t *testing.T) {
+	offsets, err := ParseReminderOffsets([]string{"1h", "1d", "60m", "24h", "90m"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1d", "90m", "1h"}, FormatReminderOffsets(offsets))
+}
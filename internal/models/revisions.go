@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Поля задачи, изменения которых попадают в историю ревизий
+const (
+	RevisionFieldName        = "name"
+	RevisionFieldDescription = "description"
+)
+
+type FieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// TaskRevision - одно изменение содержимого задачи. RevertedToID указывает ревизию,
+// к которой задачу откатили этим изменением
+type TaskRevision struct {
+	ID           uint
+	TaskID       uint
+	UserID       *uint
+	Changes      map[string]FieldChange
+	RevertedToID *uint
+	CreatedAt    time.Time
+}
+
+// DiffTaskContent возвращает изменившиеся поля, которые хранятся в истории. Пустой результат - изменений нет
+func DiffTaskContent(before, after Task) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+	if before.Name != after.Name {
+		changes[RevisionFieldName] = FieldChange{From: before.Name, To: after.Name}
+	}
+	if before.Description != after.Description {
+		changes[RevisionFieldDescription] = FieldChange{From: before.Description, To: after.Description}
+	}
+	return changes
+}
+
+// UndoRevisions откатывает содержимое задачи на состояние до переданных ревизий.
+// Ревизии должны идти от новых к старым
+func UndoRevisions(task Task, newestFirst []TaskRevision) Task {
+	for _, revision := range newestFirst {
+		if change, ok := revision.Changes[RevisionFieldName]; ok {
+			task.Name = change.From
+		}
+		if change, ok := revision.Changes[RevisionFieldDescription]; ok {
+			task.Description = change.From
+		}
+	}
+	return task
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTaskContent(t *testing.T) {
+	before := Task{Name: "Old", Description: "Same", Priority: PriorityLow}
+	after := Task{Name: "New", Description: "Same", Priority: PriorityHigh}
+
+	changes := DiffTaskContent(before, after)
+
+	assert.Equal(t, map[string]FieldChange{RevisionFieldName: {From: "Old", To: "New"}}, changes)
+	assert.Empty(t, DiffTaskContent(before, before))
+}
+
+func TestUndoRevisions(t *testing.T) {
+	task := Task{Name: "v3", Description: "d2"}
+	newestFirst := []TaskRevision{
+		{ID: 3, Changes: map[string]FieldChange{RevisionFieldName: {From: "v2", To: "v3"}}},
+		{ID: 2, Changes: map[string]FieldChange{
+			RevisionFieldName:        {From: "v1", To: "v2"},
+			RevisionFieldDescription: {From: "d1", To: "d2"},
+		}},
+	}
+
+	t.Run("Undo Latest Only", func(t *testing.T) {
+		reverted := UndoRevisions(task, newestFirst[:1])
+		assert.Equal(t, "v2", reverted.Name)
+		assert.Equal(t, "d2", reverted.Description)
+	})
+
+	t.Run("Undo Several", func(t *testing.T) {
+		reverted := UndoRevisions(task, newestFirst)
+		assert.Equal(t, "v1", reverted.Name)
+		assert.Equal(t, "d1", reverted.Description)
+	})
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// TaskSeries - серия повторяющейся задачи. Каждый экземпляр - обычная задача с SeriesID и номером Occurrence
+type TaskSeries struct {
+	ID        uint
+	UserID    uint
+	Rule      string
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Session - вход пользователя с конкретного устройства, привязан к jti выданного токена
+type Session struct {
+	ID         uint
+	UserID     uint
+	TokenID    string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+}
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+type SharePermission string
+
+const (
+	ShareViewer SharePermission = "viewer"
+	ShareEditor SharePermission = "editor"
+)
+
+// TaskAccess - уровень доступа к задаче. Уровни упорядочены: больший включает меньший
+type TaskAccess int
+
+const (
+	AccessNone TaskAccess = iota
+	AccessView
+	AccessEdit
+	AccessOwner
+)
+
+func (p SharePermission) Access() TaskAccess {
+	switch p {
+	case ShareViewer:
+		return AccessView
+	case ShareEditor:
+		return AccessEdit
+	default:
+		return AccessNone
+	}
+}
+
+// TaskShare - доступ к чужой задаче. Username заполняется при чтении для отображения владельцу
+type TaskShare struct {
+	ID         uint
+	TaskID     uint
+	UserID     uint
+	Username   string
+	Permission SharePermission
+	CreatedAt  time.Time
+}
+
+// SharedTask - задача из списка "доступные мне" вместе с выданным правом
+type SharedTask struct {
+	Task       Task
+	Permission SharePermission
+}
+
+// ResolveTaskAccess определяет доступ пользователя к задаче: владелец, роль в пространстве задачи
+// (если он в нём состоит) или право из выданного доступа. role и share - nil, если их нет
+func ResolveTaskAccess(task Task, userID uint, role *WorkspaceRole, share *SharePermission) TaskAccess {
+	if task.UserID == userID {
+		return AccessOwner
+	}
+	if task.WorkspaceID != nil && role != nil {
+		return role.TaskAccess()
+	}
+	if share != nil {
+		return share.Access()
+	}
+	return AccessNone
+}
+
+// AccessibleTask - задача вместе с доступом к ней пользователя
+type AccessibleTask struct {
+	Task   Task
+	Access TaskAccess
+}
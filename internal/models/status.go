@@ -0,0 +1,59 @@
+package models
+
+// Переходы, которые может сделать пользователь. Завершённая задача больше не меняет статус,
+// а в failed задачу переводит только система, когда истёк дедлайн
+var statusTransitions = map[Status][]Status{
+	StatusPending:    {StatusInProgress, StatusCompleted},
+	StatusInProgress: {StatusPending, StatusCompleted},
+	StatusFailed:     {StatusPending, StatusInProgress},
+	StatusCompleted:  {},
+}
+
+var systemStatusTransitions = map[Status][]Status{
+	StatusPending:    {StatusFailed},
+	StatusInProgress: {StatusFailed},
+}
+
+func (s Status) IsValid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo - можно ли пользователю перевести задачу из s в next
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusesLeadingTo возвращает статусы, из которых пользователь может перейти в target
+func StatusesLeadingTo(target Status) []Status {
+	var sources []Status
+	for from, targets := range statusTransitions {
+		for _, to := range targets {
+			if to == target {
+				sources = append(sources, from)
+			}
+		}
+	}
+	return sources
+}
+
+func StatusTransitions() map[Status][]Status {
+	return copyTransitions(statusTransitions)
+}
+
+func SystemStatusTransitions() map[Status][]Status {
+	return copyTransitions(systemStatusTransitions)
+}
+
+func copyTransitions(src map[Status][]Status) map[Status][]Status {
+	dst := make(map[Status][]Status, len(src))
+	for from, targets := range src {
+		dst[from] = append([]Status{}, targets...)
+	}
+	return dst
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus_CanTransitionTo(t *testing.T) {
+	testCases := []struct {
+		from, to Status
+		allowed  bool
+	}{
+		{StatusPending, StatusInProgress, true},
+		{StatusPending, StatusCompleted, true},
+		{StatusInProgress, StatusCompleted, true},
+		{StatusInProgress, StatusPending, true},
+		{StatusFailed, StatusPending, true},
+		{StatusCompleted, StatusPending, false},
+		{StatusCompleted, StatusInProgress, false},
+		{StatusPending, StatusFailed, false},
+		{StatusInProgress, StatusFailed, false},
+		{StatusFailed, StatusCompleted, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.from)+" -> "+string(tc.to), func(t *testing.T) {
+			assert.Equal(t, tc.allowed, tc.from.CanTransitionTo(tc.to))
+		})
+	}
+}
+
+func TestStatusesLeadingTo(t *testing.T) {
+	assert.ElementsMatch(t, []Status{StatusPending, StatusInProgress}, StatusesLeadingTo(StatusCompleted))
+	assert.Empty(t, StatusesLeadingTo(StatusFailed))
+}
+
+func TestStatusTransitions_ReturnsCopy(t *testing.T) {
+	transitions := StatusTransitions()
+	transitions[StatusCompleted] = append(transitions[StatusCompleted], StatusPending)
+
+	assert.False(t, StatusCompleted.CanTransitionTo(StatusPending))
+}
@@ -0,0 +1,36 @@
+package models
+
+import "errors"
+
+// MaxSubtaskDepth - сколько уровней подзадач может быть под корневой задачей
+const MaxSubtaskDepth = 3
+
+var (
+	ErrSubtaskCycle   = errors.New("task can't be nested into itself or its own subtask")
+	ErrSubtaskTooDeep = errors.New("subtask nesting is too deep")
+)
+
+// ValidateParent проверяет, можно ли сделать задачу taskID подзадачей parentID.
+// parentAncestors - цепочка предков нового родителя (от ближайшего к корню),
+// subtreeHeight - глубина поддерева самой задачи (0, если подзадач нет).
+// Для новой задачи taskID = 0
+func ValidateParent(taskID, parentID uint, parentAncestors []uint, subtreeHeight int) error {
+	if taskID != 0 {
+		if parentID == taskID {
+			return ErrSubtaskCycle
+		}
+		for _, ancestor := range parentAncestors {
+			if ancestor == taskID {
+				return ErrSubtaskCycle
+			}
+		}
+	}
+
+	// Глубина родителя + сама задача + всё, что под ней
+	depth := len(parentAncestors) + 1 + subtreeHeight
+	if depth > MaxSubtaskDepth {
+		return ErrSubtaskTooDeep
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateParent(t *testing.T) {
+	testCases := []struct {
+		name            string
+		taskID          uint
+		parentID        uint
+		parentAncestors []uint
+		subtreeHeight   int
+		expected        error
+	}{
+		{"New subtask of root", 0, 1, nil, 0, nil},
+		{"New subtask at max depth", 0, 3, []uint{2, 1}, 0, nil},
+		{"New subtask too deep", 0, 4, []uint{3, 2, 1}, 0, ErrSubtaskTooDeep},
+		{"Move under itself", 5, 5, nil, 0, ErrSubtaskCycle},
+		{"Move under own descendant", 1, 3, []uint{2, 1}, 2, ErrSubtaskCycle},
+		{"Move with subtree too deep", 5, 2, []uint{1}, 2, ErrSubtaskTooDeep},
+		{"Move with subtree fits", 5, 1, nil, 2, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateParent(tc.taskID, tc.parentID, tc.parentAncestors, tc.subtreeHeight)
+			assert.Equal(t, tc.expected, err)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type Tag struct {
+	ID        uint
+	UserID    uint
+	Name      string
+	CreatedAt time.Time
+}
+
+// NormalizeTagNames обрезает пробелы, выбрасывает пустые имена и дубликаты, сохраняя порядок
+func NormalizeTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+
+	return result
+}
@@ -0,0 +1,12 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTagNames(t *testing.T) {
+	assert.Equal(t, []string{"work", "home"}, NormalizeTagNames([]string{" work", "home", "", "work ", "  "}))
+	assert.Empty(t, NormalizeTagNames(nil))
+}
@@ -14,15 +14,46 @@ const (
 	StatusCompleted  Status = "completed"
 )
 
+type Priority string
+
+const (
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+	PriorityUrgent Priority = "urgent"
+)
+
+func (p Priority) IsValid() bool {
+	switch p {
+	case PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
+}
+
 type Task struct {
 	ID          uint
 	UserID      uint
+	WorkspaceID *uint
+	AssigneeID  *uint
+	ProjectID   *uint
+	ParentID    *uint
+	SeriesID    *uint
+	Occurrence  int
 	Name        string
 	Description string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	Deadline    time.Time
 	Status      Status
+	Priority    Priority
+	CompletedAt *time.Time
+	ArchivedAt  *time.Time
+	DeletedAt   *time.Time
+	Tags        []string
+	// BlockedBy - задачи, которые нужно завершить до начала этой; Blocks - задачи, которые ждут эту
+	BlockedBy []uint
+	Blocks    []uint
 }
 
 func NewTask(name, description string, deadline time.Time) (*Task, error) {
@@ -44,6 +75,7 @@ func NewTask(name, description string, deadline time.Time) (*Task, error) {
 		CreatedAt:   time.Now(),
 		Deadline:    deadline,
 		Status:      StatusPending,
+		Priority:    PriorityMedium,
 	}
 
 	return task, nil
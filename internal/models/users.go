@@ -10,6 +10,7 @@ type User struct {
 	Username  string
 	Email     string
 	Password  []byte // хэш от bcrypt
+	IsAdmin   bool
 	CreatedAt time.Time
 	UpdatedAt time.Time
 
@@ -27,9 +28,9 @@ func NewUser(username, email, password string) (*User, error) {
 		return nil, fmt.Errorf("email should be shorter than 100 characters")
 	}
 	
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := HashPassword(password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash password: %w", err)
+		return nil, err
 	}
 	
 	return &User{
@@ -37,4 +38,12 @@ func NewUser(username, email, password string) (*User, error) {
 		Email:    email,
 		Password: hashedPassword,
 	}, nil
-}
\ No newline at end of file
+}
+
+func HashPassword(password string) ([]byte, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	return hashedPassword, nil
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookTaskCreated       WebhookEvent = "task.created"
+	WebhookTaskUpdated       WebhookEvent = "task.updated"
+	WebhookTaskDeleted       WebhookEvent = "task.deleted"
+	WebhookTaskStatusChanged WebhookEvent = "task.status_changed"
+	WebhookTaskReminder      WebhookEvent = "task.reminder"
+)
+
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookTaskCreated, WebhookTaskUpdated, WebhookTaskDeleted, WebhookTaskStatusChanged, WebhookTaskReminder:
+		return true
+	}
+	return false
+}
+
+// Webhook - адрес, на который отправляются события задач пользователя. Secret подписывает тело запроса
+type Webhook struct {
+	ID        uint
+	UserID    uint
+	URL       string
+	Secret    string
+	Events    []WebhookEvent
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (w Webhook) Subscribed(event WebhookEvent) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookDelivery - одна отправка события на вебхук. Attempts учитывает и текущую попытку
+type WebhookDelivery struct {
+	ID             uint
+	WebhookID      uint
+	Event          WebhookEvent
+	Payload        json.RawMessage
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode *int
+	LastError      *string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// DueWebhookDelivery - доставка, готовая к отправке, вместе с адресом и секретом вебхука
+type DueWebhookDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleOwner  WorkspaceRole = "owner"
+	WorkspaceRoleAdmin  WorkspaceRole = "admin"
+	WorkspaceRoleMember WorkspaceRole = "member"
+)
+
+func (r WorkspaceRole) rank() int {
+	switch r {
+	case WorkspaceRoleOwner:
+		return 3
+	case WorkspaceRoleAdmin:
+		return 2
+	case WorkspaceRoleMember:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (r WorkspaceRole) AtLeast(other WorkspaceRole) bool {
+	return r.rank() >= other.rank() && r.rank() > 0
+}
+
+// TaskAccess - доступ участника к задачам пространства. Администраторы распоряжаются ими как владельцы,
+// участники могут читать и менять содержимое
+func (r WorkspaceRole) TaskAccess() TaskAccess {
+	switch r {
+	case WorkspaceRoleOwner, WorkspaceRoleAdmin:
+		return AccessOwner
+	case WorkspaceRoleMember:
+		return AccessEdit
+	default:
+		return AccessNone
+	}
+}
+
+// CanManage - может ли участник с ролью r менять роль или исключать участника с ролью target.
+// Владелец управляет всеми, кроме владельца, администратор - только обычными участниками
+func (r WorkspaceRole) CanManage(target WorkspaceRole) bool {
+	if target == WorkspaceRoleOwner {
+		return false
+	}
+	return r == WorkspaceRoleOwner || (r == WorkspaceRoleAdmin && target == WorkspaceRoleMember)
+}
+
+type Workspace struct {
+	ID        uint
+	Name      string
+	OwnerID   uint
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// UserWorkspace - пространство из списка пользователя вместе с его ролью в нём
+type UserWorkspace struct {
+	Workspace Workspace
+	Role      WorkspaceRole
+}
+
+type WorkspaceMember struct {
+	WorkspaceID uint
+	UserID      uint
+	Username    string
+	Role        WorkspaceRole
+	JoinedAt    time.Time
+}
+
+// WorkspaceInvitation - приглашение по почте. В базе хранится только хеш токена,
+// сам токен показывается один раз при создании
+type WorkspaceInvitation struct {
+	ID          uint
+	WorkspaceID uint
+	Email       string
+	Role        WorkspaceRole
+	TokenHash   string
+	InvitedBy   *uint
+	ExpiresAt   time.Time
+	AcceptedAt  *time.Time
+	CreatedAt   time.Time
+}
+
+// NewInvitationToken возвращает случайный токен приглашения и его хеш для хранения
+func NewInvitationToken() (string, string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	token := hex.EncodeToString(b)
+	return token, HashInvitationToken(token), nil
+}
+
+func HashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceRole_CanManage(t *testing.T) {
+	assert.True(t, WorkspaceRoleOwner.CanManage(WorkspaceRoleAdmin))
+	assert.True(t, WorkspaceRoleOwner.CanManage(WorkspaceRoleMember))
+	assert.False(t, WorkspaceRoleOwner.CanManage(WorkspaceRoleOwner))
+
+	assert.True(t, WorkspaceRoleAdmin.CanManage(WorkspaceRoleMember))
+	assert.False(t, WorkspaceRoleAdmin.CanManage(WorkspaceRoleAdmin))
+	assert.False(t, WorkspaceRoleAdmin.CanManage(WorkspaceRoleOwner))
+
+	assert.False(t, WorkspaceRoleMember.CanManage(WorkspaceRoleMember))
+}
+
+func TestWorkspaceRole_Access(t *testing.T) {
+	assert.True(t, WorkspaceRoleOwner.AtLeast(WorkspaceRoleAdmin))
+	assert.True(t, WorkspaceRoleAdmin.AtLeast(WorkspaceRoleAdmin))
+	assert.False(t, WorkspaceRoleMember.AtLeast(WorkspaceRoleAdmin))
+	assert.False(t, WorkspaceRole("").AtLeast(""))
+
+	assert.Equal(t, AccessOwner, WorkspaceRoleAdmin.TaskAccess())
+	assert.Equal(t, AccessEdit, WorkspaceRoleMember.TaskAccess())
+	assert.Equal(t, AccessNone, WorkspaceRole("guest").TaskAccess())
+}
+
+func TestNewInvitationToken(t *testing.T) {
+	token, hash, err := NewInvitationToken()
+	require.NoError(t, err)
+	assert.Len(t, token, 64)
+	assert.Equal(t, HashInvitationToken(token), hash)
+	assert.NotEqual(t, token, hash)
+}
+
+func TestResolveTaskAccess(t *testing.T) {
+	workspace := uint(3)
+	task := Task{ID: 1, UserID: 10, WorkspaceID: &workspace}
+	member, admin, view := WorkspaceRoleMember, WorkspaceRoleAdmin, ShareViewer
+
+	assert.Equal(t, AccessOwner, ResolveTaskAccess(task, 10, nil, nil))
+	assert.Equal(t, AccessEdit, ResolveTaskAccess(task, 20, &member, nil))
+	assert.Equal(t, AccessOwner, ResolveTaskAccess(task, 20, &admin, &view))
+	assert.Equal(t, AccessView, ResolveTaskAccess(task, 20, nil, &view))
+	assert.Equal(t, AccessNone, ResolveTaskAccess(task, 20, nil, nil))
+
+	// Роль в пространстве ничего не даёт для личной задачи
+	assert.Equal(t, AccessView, ResolveTaskAccess(Task{ID: 2, UserID: 10}, 20, &admin, &view))
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+	"to-do-list/internal/models"
+)
+
+// SMTPConfig: без Username письма отправляются без авторизации, как принимает локальный MailHog
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	From     string
+	Username string
+	Password string
+}
+
+type UserLookup interface {
+	GetUserByID(ctx context.Context, id uint) (models.User, error)
+}
+
+type sendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// EmailChannel отправляет уведомление письмом на адрес пользователя
+type EmailChannel struct {
+	cfg      SMTPConfig
+	users    UserLookup
+	sendMail sendMailFunc
+}
+
+func NewEmailChannel(cfg SMTPConfig, users UserLookup) *EmailChannel {
+	return &EmailChannel{cfg: cfg, users: users, sendMail: smtp.SendMail}
+}
+
+func (c *EmailChannel) Name() string {
+	return string(models.ChannelEmail)
+}
+
+func (c *EmailChannel) Send(ctx context.Context, n models.Notification) error {
+	user, err := c.users.GetUserByID(ctx, n.UserID)
+	if err != nil {
+		return fmt.Errorf("notify: failed to get recipient: %w", err)
+	}
+
+	var auth smtp.Auth
+	if c.cfg.Username != "" {
+		auth = smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)
+	}
+
+	addr := net.JoinHostPort(c.cfg.Host, strconv.Itoa(c.cfg.Port))
+	err = c.sendMail(addr, auth, c.cfg.From, []string{user.Email}, c.message(user.Email, n))
+	if err != nil {
+		return fmt.Errorf("notify: failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// message собирает письмо в text/plain. Тема кодируется по RFC 2047, переводы строк
+// из неё убираются, чтобы через заголовок нельзя было дописать свои
+func (c *EmailChannel) message(to string, n models.Notification) []byte {
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(n.Title)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", c.cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", n.CreatedAt.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(n.Body, "\n", "\r\n"))
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"to-do-list/internal/models"
+)
+
+// legacySubscriberName - под этим именем события отмечал подписчик, рассылавший сразу во все каналы.
+// Такие события уже доставлены и повторно не рассылаются
+const legacySubscriberName = "notifications"
+
+// Subscriber превращает доменные события в уведомления тем, кого они касаются, и доставляет их по одному
+// каналу. На каждый канал регистрируется свой подписчик, чтобы диспетчер повторял только упавший канал
+// и остальные не присылали уведомление второй раз
+type Subscriber struct {
+	service *Service
+	channel models.NotificationChannelName
+}
+
+func NewSubscriber(service *Service, channel models.NotificationChannelName) *Subscriber {
+	return &Subscriber{service: service, channel: channel}
+}
+
+// NewSubscribers создаёт по подписчику на каждый канал сервиса
+func NewSubscribers(service *Service) []*Subscriber {
+	subscribers := make([]*Subscriber, 0, len(service.channels))
+	for _, ch := range service.channels {
+		subscribers = append(subscribers, NewSubscriber(service, models.NotificationChannelName(ch.Name())))
+	}
+	return subscribers
+}
+
+func (s *Subscriber) Name() string {
+	return legacySubscriberName + "." + string(s.channel)
+}
+
+// Handle уведомляет нового исполнителя задачи, если он назначил себя не сам, и того, с кем поделились задачей.
+// Ошибка канала возвращается диспетчеру, и событие повторяется для этого канала: уведомление может прийти дважды, но не потеряется
+func (s *Subscriber) Handle(ctx context.Context, event models.DomainEvent) error {
+	if slices.Contains(event.Handled, legacySubscriberName) {
+		return nil
+	}
+
+	switch event.Type {
+	case models.EventTaskAssigned:
+		return s.taskAssigned(ctx, event)
+	case models.EventTaskShared:
+		return s.taskShared(ctx, event)
+	}
+	return nil
+}
+
+func (s *Subscriber) taskAssigned(ctx context.Context, event models.DomainEvent) error {
+	payload, err := event.TaskPayload()
+	if err != nil {
+		return fmt.Errorf("notify: failed to decode event payload: %w", err)
+	}
+
+	task := payload.After
+	if task == nil || task.AssigneeID == nil {
+		return nil
+	}
+	if event.ActorID != nil && *event.ActorID == *task.AssigneeID {
+		return nil
+	}
+
+	return s.service.Notify(ctx, models.Notification{
+		UserID:    *task.AssigneeID,
+		Kind:      models.NotificationTaskAssigned,
+		TaskID:    &task.ID,
+		Title:     "You were assigned to a task",
+		Body:      task.Name,
+		Channels:  []models.NotificationChannelName{s.channel},
+		CreatedAt: event.CreatedAt,
+	})
+}
+
+func (s *Subscriber) taskShared(ctx context.Context, event models.DomainEvent) error {
+	payload, err := event.SharePayload()
+	if err != nil {
+		return fmt.Errorf("notify: failed to decode event payload: %w", err)
+	}
+
+	return s.service.Notify(ctx, models.Notification{
+		UserID:    payload.UserID,
+		Kind:      models.NotificationTaskShared,
+		TaskID:    &payload.Task.ID,
+		Title:     "A task was shared with you",
+		Body:      fmt.Sprintf("%s (%s)", payload.Task.Name, payload.Permission),
+		Channels:  []models.NotificationChannelName{s.channel},
+		CreatedAt: event.CreatedAt,
+	})
+}
@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+	"to-do-list/internal/models"
+)
+
+type NotificationStore interface {
+	CreateNotification(ctx context.Context, n *models.Notification) error
+}
+
+// InAppChannel сохраняет уведомление, чтобы пользователь увидел его в приложении
+type InAppChannel struct {
+	store NotificationStore
+}
+
+func NewInAppChannel(store NotificationStore) *InAppChannel {
+	return &InAppChannel{store: store}
+}
+
+func (c *InAppChannel) Name() string {
+	return string(models.ChannelInApp)
+}
+
+func (c *InAppChannel) Send(ctx context.Context, n models.Notification) error {
+	return c.store.CreateNotification(ctx, &n)
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+	"to-do-list/internal/models"
+)
+
+// Channel доставляет уведомление одним способом: в лог, на почту, в приложение
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, n models.Notification) error
+}
+
+// Service рассылает уведомление по каналам, перечисленным в Notification.Channels, или по всем,
+// если там пусто. Ошибка одного канала не мешает остальным: Notify отправляет во все и возвращает
+// ошибки сразу всех неудачных каналов, а повторять ли отправку, решает вызывающий
+type Service struct {
+	channels []Channel
+}
+
+func NewService(channels ...Channel) *Service {
+	return &Service{channels: channels}
+}
+
+func (s *Service) Notify(ctx context.Context, n models.Notification) error {
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+
+	var errs []error
+	for _, ch := range s.channels {
+		if len(n.Channels) > 0 && !slices.Contains(n.Channels, models.NotificationChannelName(ch.Name())) {
+			continue
+		}
+
+		err := ch.Send(ctx, n)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notify: %s channel failed: %w", ch.Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// LogChannel пишет уведомления в лог, пока не настроены другие каналы
+type LogChannel struct{}
+
+func (LogChannel) Name() string {
+	return "log"
+}
+
+func (LogChannel) Send(ctx context.Context, n models.Notification) error {
+	slog.Info("Notification",
+		slog.String("kind", string(n.Kind)),
+		slog.Any("userID", n.UserID),
+		slog.String("title", n.Title),
+	)
+	return nil
+}
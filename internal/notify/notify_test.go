@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"testing"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingChannel struct {
+	err  error
+	sent []models.Notification
+}
+
+func (c *recordingChannel) Name() string {
+	return "recording"
+}
+
+func (c *recordingChannel) Send(ctx context.Context, n models.Notification) error {
+	c.sent = append(c.sent, n)
+	return c.err
+}
+
+func TestService_NotifyAllChannels(t *testing.T) {
+	failing := &recordingChannel{err: errors.New("smtp is down")}
+	working := &recordingChannel{}
+	service := NewService(failing, working)
+
+	err := service.Notify(context.Background(), models.Notification{UserID: 5, Kind: models.NotificationTaskAssigned})
+	assert.ErrorContains(t, err, "smtp is down")
+
+	require.Len(t, failing.sent, 1)
+	require.Len(t, working.sent, 1)
+	assert.Equal(t, uint(5), working.sent[0].UserID)
+	assert.False(t, working.sent[0].CreatedAt.IsZero())
+}
+
+func TestSubscriber_NotifiesAssignee(t *testing.T) {
+	channel := &recordingChannel{}
+	subscriber := NewSubscriber(NewService(channel), "recording")
+
+	ownerID, assigneeID := uint(10), uint(20)
+	before := models.Task{ID: 1, UserID: ownerID, Name: "Report"}
+	after := before
+
+	after.AssigneeID = &ownerID
+	for _, event := range models.TaskUpdateEvents(before, after) {
+		event.ActorID = &ownerID
+		require.NoError(t, subscriber.Handle(context.Background(), event))
+	}
+	assert.Empty(t, channel.sent)
+
+	after.AssigneeID = &assigneeID
+	for _, event := range models.TaskUpdateEvents(before, after) {
+		event.ActorID = &ownerID
+		require.NoError(t, subscriber.Handle(context.Background(), event))
+	}
+	require.Len(t, channel.sent, 1)
+	assert.Equal(t, assigneeID, channel.sent[0].UserID)
+	assert.Equal(t, "Report", channel.sent[0].Body)
+}
+
+type namedChannel struct {
+	recordingChannel
+	name string
+}
+
+func (c *namedChannel) Name() string {
+	return c.name
+}
+
+func TestService_RespectsChannels(t *testing.T) {
+	email := &namedChannel{name: "email"}
+	inApp := &namedChannel{name: "in_app"}
+	service := NewService(email, inApp)
+
+	require.NoError(t, service.Notify(context.Background(), models.Notification{UserID: 5, Channels: []models.NotificationChannelName{models.ChannelInApp}}))
+
+	assert.Empty(t, email.sent)
+	assert.Len(t, inApp.sent, 1)
+}
+
+type fakeUsers struct{}
+
+func (fakeUsers) GetUserByID(ctx context.Context, id uint) (models.User, error) {
+	return models.User{ID: id, Email: "bob@example.com"}, nil
+}
+
+func TestEmailChannel_Send(t *testing.T) {
+	channel := NewEmailChannel(SMTPConfig{Host: "localhost", Port: 1025, From: "todo@localhost"}, fakeUsers{})
+
+	var addr string
+	var auth smtp.Auth
+	var to []string
+	var msg []byte
+	channel.sendMail = func(a string, au smtp.Auth, from string, rcpt []string, m []byte) error {
+		addr, auth, to, msg = a, au, rcpt, m
+		return nil
+	}
+
+	err := channel.Send(context.Background(), models.Notification{
+		UserID:    5,
+		Title:     "Deadline is approaching\r\nBcc: eve@example.com",
+		Body:      "\"Report\" is due in 1h",
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "localhost:1025", addr)
+	assert.Nil(t, auth)
+	assert.Equal(t, []string{"bob@example.com"}, to)
+	assert.Contains(t, string(msg), "To: bob@example.com\r\n")
+	assert.NotContains(t, string(msg), "\r\nBcc:")
+	assert.Contains(t, string(msg), "\r\n\r\n\"Report\" is due in 1h\r\n")
+}
+
+func TestSubscriber_NotifiesShareRecipient(t *testing.T) {
+	channel := &recordingChannel{}
+	subscriber := NewSubscriber(NewService(channel), "recording")
+
+	event := models.NewTaskShareEvent(models.Task{ID: 1, UserID: 10, Name: "Report"}, models.TaskShare{UserID: 30, Permission: models.ShareEditor})
+	require.NoError(t, subscriber.Handle(context.Background(), event))
+
+	require.Len(t, channel.sent, 1)
+	assert.Equal(t, uint(30), channel.sent[0].UserID)
+	assert.Equal(t, models.NotificationTaskShared, channel.sent[0].Kind)
+	assert.Equal(t, "Report (editor)", channel.sent[0].Body)
+}
+
+func TestSubscriber_ReturnsChannelFailure(t *testing.T) {
+	failing := &recordingChannel{err: errors.New("smtp is down")}
+	subscriber := NewSubscriber(NewService(failing), "recording")
+
+	actorID, assigneeID := uint(10), uint(20)
+	before := models.Task{ID: 1, UserID: 10}
+	after := before
+	after.AssigneeID = &assigneeID
+
+	events := models.TaskUpdateEvents(before, after)
+	require.Len(t, events, 2)
+	event := events[1]
+	event.ActorID = &actorID
+
+	// Диспетчер не отметит подписчика обработавшим и повторит событие
+	err := subscriber.Handle(context.Background(), event)
+	assert.ErrorContains(t, err, "smtp is down")
+	assert.Len(t, failing.sent, 1)
+}
+
+// Каждый канал - отдельный подписчик: повтор после сбоя почты не присылает уведомление в приложении второй раз
+func TestNewSubscribers_RetryOnlyFailedChannel(t *testing.T) {
+	inApp := &namedChannel{name: "in_app"}
+	email := &namedChannel{name: "email", recordingChannel: recordingChannel{err: errors.New("smtp is down")}}
+	subscribers := NewSubscribers(NewService(inApp, email))
+	require.Len(t, subscribers, 2)
+	assert.Equal(t, "notifications.in_app", subscribers[0].Name())
+	assert.Equal(t, "notifications.email", subscribers[1].Name())
+
+	event := models.NewTaskShareEvent(models.Task{ID: 1, UserID: 10, Name: "Report"}, models.TaskShare{UserID: 30, Permission: models.ShareEditor})
+	require.NoError(t, subscribers[0].Handle(context.Background(), event))
+	assert.Error(t, subscribers[1].Handle(context.Background(), event))
+
+	email.err = nil
+	require.NoError(t, subscribers[1].Handle(context.Background(), event))
+
+	assert.Len(t, inApp.sent, 1)
+	assert.Len(t, email.sent, 2)
+}
+
+func TestSubscriber_SkipsLegacyHandledEvent(t *testing.T) {
+	channel := &recordingChannel{}
+	subscriber := NewSubscriber(NewService(channel), "recording")
+
+	event := models.NewTaskShareEvent(models.Task{ID: 1, UserID: 10}, models.TaskShare{UserID: 30, Permission: models.ShareViewer})
+	event.Handled = []string{"notifications"}
+	require.NoError(t, subscriber.Handle(context.Background(), event))
+	assert.Empty(t, channel.sent)
+}
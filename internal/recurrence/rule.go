@@ -0,0 +1,268 @@
+// Package recurrence реализует подмножество RRULE из RFC 5545, достаточное для повторяющихся задач:
+// FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY (только для WEEKLY), BYMONTHDAY (только для MONTHLY), COUNT и UNTIL
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+var ErrMissingFrequency = errors.New("recurrence: FREQ is required")
+
+// Готовые правила для самых частых случаев
+var presets = map[string]string{
+	"daily":   "FREQ=DAILY",
+	"weekly":  "FREQ=WEEKLY",
+	"monthly": "FREQ=MONTHLY",
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+const (
+	untilLayout     = "20060102T150405Z"
+	untilDateLayout = "20060102"
+)
+
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	// ByDay - дни недели для WEEKLY, по порядку начиная с понедельника
+	ByDay []time.Weekday
+	// ByMonthDay - дни месяца для MONTHLY, отрицательные считаются с конца месяца (-1 - последний день)
+	ByMonthDay []int
+	// Count - сколько всего экземпляров в серии, 0 - без ограничения
+	Count int
+	Until *time.Time
+}
+
+// Parse разбирает правило вида "FREQ=WEEKLY;BYDAY=MO,WE" (префикс "RRULE:" допускается)
+// или одно из готовых значений: daily, weekly, monthly
+func Parse(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+	if preset, ok := presets[strings.ToLower(s)]; ok {
+		s = preset
+	}
+	s = strings.TrimPrefix(strings.ToUpper(s), "RRULE:")
+
+	rule := Rule{Interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return Rule{}, fmt.Errorf("recurrence: invalid rule part %q", part)
+		}
+		if seen[key] {
+			return Rule{}, fmt.Errorf("recurrence: %s is set twice", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch Frequency(value) {
+			case Daily, Weekly, Monthly:
+				rule.Freq = Frequency(value)
+			default:
+				err = fmt.Errorf("recurrence: unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parseBounded(key, value, 1, 365)
+		case "COUNT":
+			rule.Count, err = parseBounded(key, value, 1, 1000)
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return Rule{}, fmt.Errorf("recurrence: unsupported BYDAY value %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				n, err := parseBounded(key, day, -31, 31)
+				if err != nil {
+					return Rule{}, err
+				}
+				if n == 0 {
+					return Rule{}, errors.New("recurrence: BYMONTHDAY can't be 0")
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "UNTIL":
+			until, parseErr := time.Parse(untilLayout, value)
+			if parseErr != nil {
+				until, parseErr = time.Parse(untilDateLayout, value)
+			}
+			if parseErr != nil {
+				return Rule{}, fmt.Errorf("recurrence: invalid UNTIL %q", value)
+			}
+			rule.Until = &until
+		default:
+			err = fmt.Errorf("recurrence: unsupported rule part %s", key)
+		}
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+
+	if rule.Freq == "" {
+		return Rule{}, ErrMissingFrequency
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return Rule{}, errors.New("recurrence: COUNT and UNTIL can't be used together")
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != Weekly {
+		return Rule{}, errors.New("recurrence: BYDAY is supported only with FREQ=WEEKLY")
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq != Monthly {
+		return Rule{}, errors.New("recurrence: BYMONTHDAY is supported only with FREQ=MONTHLY")
+	}
+
+	slices.SortFunc(rule.ByDay, func(a, b time.Weekday) int { return weekdayIndex(a) - weekdayIndex(b) })
+	rule.ByDay = slices.Compact(rule.ByDay)
+	slices.Sort(rule.ByMonthDay)
+	rule.ByMonthDay = slices.Compact(rule.ByMonthDay)
+
+	return rule, nil
+}
+
+func parseBounded(key, value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("recurrence: invalid %s %q", key, value)
+	}
+	return n, nil
+}
+
+// String возвращает правило в каноничном виде, в котором оно хранится в базе
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			days = append(days, strings.ToUpper(day.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next возвращает первое срабатывание строго после after, сохраняя время суток.
+// false - серия закончилась по UNTIL
+func (r Rule) Next(after time.Time) (time.Time, bool) {
+	interval := max(r.Interval, 1)
+
+	var next time.Time
+	switch r.Freq {
+	case Daily:
+		next = after.AddDate(0, 0, interval)
+	case Weekly:
+		next = r.nextWeekly(after, interval)
+	case Monthly:
+		var ok bool
+		next, ok = r.nextMonthly(after, interval)
+		if !ok {
+			return time.Time{}, false
+		}
+	default:
+		return time.Time{}, false
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// weekdayIndex - номер дня в неделе, начинающейся с понедельника (WKST=MO)
+func weekdayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+func (r Rule) nextWeekly(after time.Time, interval int) time.Time {
+	if len(r.ByDay) == 0 {
+		return after.AddDate(0, 0, 7*interval)
+	}
+
+	current := weekdayIndex(after.Weekday())
+	for _, day := range r.ByDay {
+		if index := weekdayIndex(day); index > current {
+			return after.AddDate(0, 0, index-current)
+		}
+	}
+
+	// В этой неделе дней не осталось - первый день через interval недель
+	weekStart := after.AddDate(0, 0, -current)
+	return weekStart.AddDate(0, 0, 7*interval+weekdayIndex(r.ByDay[0]))
+}
+
+// nextMonthly перебирает месяцы с шагом interval. Несуществующие даты (31 февраля) пропускаются, как в RFC 5545
+func (r Rule) nextMonthly(after time.Time, interval int) (time.Time, bool) {
+	days := r.ByMonthDay
+	if len(days) == 0 {
+		days = []int{after.Day()}
+	}
+
+	hour, minute, sec := after.Clock()
+	for step := 0; step <= 48; step++ {
+		month := time.Date(after.Year(), after.Month()+time.Month(step*interval), 1, hour, minute, sec, after.Nanosecond(), after.Location())
+		daysInMonth := month.AddDate(0, 1, -1).Day()
+
+		candidates := make([]int, 0, len(days))
+		for _, day := range days {
+			if day < 0 {
+				day = daysInMonth + day + 1
+			}
+			if day >= 1 && day <= daysInMonth {
+				candidates = append(candidates, day)
+			}
+		}
+		slices.Sort(candidates)
+
+		for _, day := range candidates {
+			next := month.AddDate(0, 0, day-1)
+			if next.After(after) {
+				return next, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
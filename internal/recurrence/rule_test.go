@@ -0,0 +1,84 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"Preset", "weekly", "FREQ=WEEKLY", false},
+		{"RRULE Prefix", "RRULE:FREQ=DAILY;INTERVAL=2", "FREQ=DAILY;INTERVAL=2", false},
+		{"Days Are Sorted", "freq=weekly;byday=FR,MO,MO", "FREQ=WEEKLY;BYDAY=MO,FR", false},
+		{"Month Days", "FREQ=MONTHLY;BYMONTHDAY=-1,15", "FREQ=MONTHLY;BYMONTHDAY=-1,15", false},
+		{"Until Date", "FREQ=DAILY;UNTIL=20260101", "FREQ=DAILY;UNTIL=20260101T000000Z", false},
+		{"Missing Freq", "INTERVAL=2", "", true},
+		{"Unsupported Freq", "FREQ=HOURLY", "", true},
+		{"Unsupported Part", "FREQ=DAILY;BYHOUR=9", "", true},
+		{"Count With Until", "FREQ=DAILY;COUNT=3;UNTIL=20260101", "", true},
+		{"BYDAY Without Weekly", "FREQ=DAILY;BYDAY=MO", "", true},
+		{"Zero Month Day", "FREQ=MONTHLY;BYMONTHDAY=0", "", true},
+		{"Duplicate Part", "FREQ=DAILY;FREQ=WEEKLY", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := Parse(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, rule.String())
+		})
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	// 2026-01-05 - понедельник
+	monday := time.Date(2026, 1, 5, 9, 30, 0, 0, time.UTC)
+	jan31 := time.Date(2026, 1, 31, 9, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		rule     string
+		after    time.Time
+		expected time.Time
+	}{
+		{"Daily", "FREQ=DAILY", monday, monday.AddDate(0, 0, 1)},
+		{"Every Other Week", "FREQ=WEEKLY;INTERVAL=2", monday, monday.AddDate(0, 0, 14)},
+		{"Next Day In Week", "FREQ=WEEKLY;BYDAY=MO,TH", monday, monday.AddDate(0, 0, 3)},
+		{"Wraps To Next Week", "FREQ=WEEKLY;BYDAY=MO,TH", monday.AddDate(0, 0, 3), monday.AddDate(0, 0, 7)},
+		{"Wraps With Interval", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", monday, monday.AddDate(0, 0, 14)},
+		{"Monthly Skips Short Months", "FREQ=MONTHLY", jan31, time.Date(2026, 3, 31, 9, 30, 0, 0, time.UTC)},
+		{"Last Day Of Month", "FREQ=MONTHLY;BYMONTHDAY=-1", jan31, time.Date(2026, 2, 28, 9, 30, 0, 0, time.UTC)},
+		{"Several Month Days", "FREQ=MONTHLY;BYMONTHDAY=1,15", monday, time.Date(2026, 1, 15, 9, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := Parse(tc.rule)
+			require.NoError(t, err)
+
+			next, ok := rule.Next(tc.after)
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, next)
+		})
+	}
+
+	t.Run("Until Ends Series", func(t *testing.T) {
+		rule, err := Parse("FREQ=DAILY;UNTIL=20260106T000000Z")
+		require.NoError(t, err)
+
+		_, ok := rule.Next(monday)
+		assert.False(t, ok)
+	})
+}
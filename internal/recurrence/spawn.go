@@ -0,0 +1,86 @@
+package recurrence
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+	"to-do-list/internal/models"
+)
+
+// maxSkippedOccurrences ограничивает перебор, если экземпляр закрыли сильно позже дедлайна
+const maxSkippedOccurrences = 10000
+
+type Store interface {
+	GetSeriesByID(ctx context.Context, id uint) (models.TaskSeries, error)
+	CreateOccurrence(ctx context.Context, task *models.Task) (bool, error)
+}
+
+// SpawnNext создаёт следующий экземпляр серии, к которой относится task.
+// Срабатывания, дедлайн которых уже прошёл к now, пропускаются, чтобы новая задача не оказалась сразу просроченной.
+// Возвращает nil, если задача не повторяющаяся, серия остановлена или закончилась,
+// либо следующий экземпляр уже создан
+func SpawnNext(ctx context.Context, store Store, task models.Task, now time.Time) (*models.Task, error) {
+	if task.SeriesID == nil {
+		return nil, nil
+	}
+
+	series, err := store.GetSeriesByID(ctx, *task.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	if !series.Active {
+		return nil, nil
+	}
+
+	rule, err := Parse(series.Rule)
+	if err != nil {
+		return nil, fmt.Errorf("recurrence: series %d has invalid rule: %w", series.ID, err)
+	}
+
+	deadline := task.Deadline
+	occurrence := task.Occurrence
+	for range maxSkippedOccurrences {
+		next, ok := rule.Next(deadline)
+		if !ok {
+			return nil, nil
+		}
+		occurrence++
+		if rule.Count > 0 && occurrence > rule.Count {
+			return nil, nil
+		}
+		deadline = next
+		if deadline.After(now) {
+			break
+		}
+	}
+	if !deadline.After(now) {
+		return nil, nil
+	}
+
+	next := &models.Task{
+		UserID:      task.UserID,
+		WorkspaceID: task.WorkspaceID,
+		AssigneeID:  task.AssigneeID,
+		ProjectID:   task.ProjectID,
+		SeriesID:    task.SeriesID,
+		Occurrence:  occurrence,
+		Name:        task.Name,
+		Description: task.Description,
+		CreatedAt:   now,
+		Deadline:    deadline,
+		Status:      models.StatusPending,
+		Priority:    task.Priority,
+		Tags:        slices.Clone(task.Tags),
+	}
+
+	created, err := store.CreateOccurrence(ctx, next)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, nil
+	}
+
+	return next, nil
+}
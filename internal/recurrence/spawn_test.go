@@ -0,0 +1,96 @@
+package recurrence
+
+import (
+	"context"
+	"testing"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	series  models.TaskSeries
+	created []models.Task
+	exists  bool
+}
+
+func (f *fakeStore) GetSeriesByID(ctx context.Context, id uint) (models.TaskSeries, error) {
+	return f.series, nil
+}
+
+func (f *fakeStore) CreateOccurrence(ctx context.Context, task *models.Task) (bool, error) {
+	if f.exists {
+		return false, nil
+	}
+	f.created = append(f.created, *task)
+	return true, nil
+}
+
+func TestSpawnNext(t *testing.T) {
+	seriesID := uint(7)
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	task := models.Task{
+		ID:         1,
+		UserID:     10,
+		SeriesID:   &seriesID,
+		Occurrence: 1,
+		Name:       "Water plants",
+		Deadline:   time.Date(2026, 1, 10, 18, 0, 0, 0, time.UTC),
+		Status:     models.StatusCompleted,
+		Priority:   models.PriorityHigh,
+		Tags:       []string{"home"},
+	}
+
+	t.Run("Next Occurrence", func(t *testing.T) {
+		store := &fakeStore{series: models.TaskSeries{ID: seriesID, Rule: "FREQ=DAILY", Active: true}}
+
+		next, err := SpawnNext(context.Background(), store, task, now)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+
+		assert.Equal(t, 2, next.Occurrence)
+		assert.Equal(t, task.Deadline.AddDate(0, 0, 1), next.Deadline)
+		assert.Equal(t, models.StatusPending, next.Status)
+		assert.Equal(t, task.Priority, next.Priority)
+		assert.Equal(t, task.Tags, next.Tags)
+	})
+
+	t.Run("Skips Past Occurrences", func(t *testing.T) {
+		store := &fakeStore{series: models.TaskSeries{ID: seriesID, Rule: "FREQ=DAILY", Active: true}}
+		late := now.AddDate(0, 0, 3)
+
+		next, err := SpawnNext(context.Background(), store, task, late)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+
+		assert.True(t, next.Deadline.After(late))
+		assert.Equal(t, 4, next.Occurrence)
+	})
+
+	t.Run("Stopped Series", func(t *testing.T) {
+		store := &fakeStore{series: models.TaskSeries{ID: seriesID, Rule: "FREQ=DAILY", Active: false}}
+
+		next, err := SpawnNext(context.Background(), store, task, now)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		assert.Empty(t, store.created)
+	})
+
+	t.Run("Count Reached", func(t *testing.T) {
+		store := &fakeStore{series: models.TaskSeries{ID: seriesID, Rule: "FREQ=DAILY;COUNT=1", Active: true}}
+
+		next, err := SpawnNext(context.Background(), store, task, now)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+	})
+
+	t.Run("Already Spawned", func(t *testing.T) {
+		store := &fakeStore{series: models.TaskSeries{ID: seriesID, Rule: "FREQ=DAILY", Active: true}, exists: true}
+
+		next, err := SpawnNext(context.Background(), store, task, now)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+	})
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"to-do-list/internal/models"
+)
+
+func (r *PostgresTaskRepository) SetTaskArchived(ctx context.Context, id uint, archived bool) error {
+	query := `UPDATE tasks
+              SET archived_at = CASE WHEN $1 THEN COALESCE(archived_at, NOW()) ELSE NULL END,
+                  updated_at = NOW()
+              WHERE id = $2`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, archived, id)
+
+	if err != nil {
+		return fmt.Errorf("repository: failed to update task archive state: %w", err)
+	}
+
+	return nil
+}
+
+// ArchiveCompletedTasks архивирует все завершённые задачи пользователя и возвращает их в новом состоянии
+func (r *PostgresTaskRepository) ArchiveCompletedTasks(ctx context.Context, userID uint) ([]models.Task, error) {
+	query := `UPDATE tasks SET archived_at = NOW(), updated_at = NOW()
+              WHERE user_id = $1 AND status = $2 AND archived_at IS NULL AND deleted_at IS NULL
+              RETURNING ` + taskColumns
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, models.StatusCompleted)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to archive completed tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	rows.Close()
+
+	err = r.loadDetails(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// ArchiveCompletedBefore архивирует задачи, завершённые раньше cutoff, у всех пользователей, и в той же
+// транзакции пишет о них события. Выполняется под advisory-локом, как и остальные фоновые задачи
+func (r *PostgresTaskRepository) ArchiveCompletedBefore(ctx context.Context, cutoff time.Time) ([]models.Task, error) {
+	tasks := []models.Task{}
+
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+
+		locked, err := tryAdvisoryXactLock(ctx, tx, lockAutoArchive)
+		if err != nil || !locked {
+			return err
+		}
+
+		query := `UPDATE tasks SET archived_at = NOW(), updated_at = NOW()
+                  WHERE status = $1 AND completed_at < $2 AND archived_at IS NULL AND deleted_at IS NULL
+                  RETURNING ` + taskColumns
+
+		rows, err := tx.QueryContext(ctx, query, models.StatusCompleted, cutoff)
+		if err != nil {
+			return fmt.Errorf("repository: failed to auto-archive tasks: %w", err)
+		}
+
+		for rows.Next() {
+			task, err := scanTask(rows)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("repository: failed to scan task: %w", err)
+			}
+			tasks = append(tasks, task)
+		}
+		rows.Close()
+
+		err = r.loadDetails(ctx, tasks)
+		if err != nil {
+			return err
+		}
+
+		var events []models.DomainEvent
+		for _, task := range tasks {
+			before := task
+			before.ArchivedAt = nil
+			events = append(events, models.TaskUpdateEvents(before, task)...)
+		}
+
+		return appendEvents(ctx, tx, events)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
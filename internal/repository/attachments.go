@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"to-do-list/internal/models"
+)
+
+var ErrAttachmentNotFound = errors.New("repository: attachment not found")
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment *models.TaskAttachment) error
+	GetAttachmentByID(ctx context.Context, id uint) (models.TaskAttachment, error)
+	GetAttachmentsByTaskID(ctx context.Context, taskID uint) ([]models.TaskAttachment, error)
+	DeleteAttachment(ctx context.Context, id uint) error
+}
+
+type PostgresAttachmentRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAttachmentRepository(db *sql.DB) *PostgresAttachmentRepository {
+	return &PostgresAttachmentRepository{db: db}
+}
+
+const attachmentColumns = `id, task_id, user_id, file_name, content_type, size, storage_key, created_at`
+
+func scanAttachment(row rowScanner) (models.TaskAttachment, error) {
+	var a models.TaskAttachment
+	err := row.Scan(&a.ID, &a.TaskID, &a.UserID, &a.FileName, &a.ContentType, &a.Size, &a.StorageKey, &a.CreatedAt)
+	return a, err
+}
+
+func (r *PostgresAttachmentRepository) CreateAttachment(ctx context.Context, attachment *models.TaskAttachment) error {
+	query := `INSERT INTO task_attachments (task_id, user_id, file_name, content_type, size, storage_key)
+              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, attachment.TaskID, attachment.UserID, attachment.FileName,
+		attachment.ContentType, attachment.Size, attachment.StorageKey).Scan(&attachment.ID, &attachment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create attachment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAttachmentRepository) GetAttachmentByID(ctx context.Context, id uint) (models.TaskAttachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM task_attachments WHERE id = $1`
+
+	attachment, err := scanAttachment(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TaskAttachment{}, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return models.TaskAttachment{}, fmt.Errorf("repository: failed to get attachment by id: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func (r *PostgresAttachmentRepository) GetAttachmentsByTaskID(ctx context.Context, taskID uint) ([]models.TaskAttachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM task_attachments WHERE task_id = $1 ORDER BY id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get task attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []models.TaskAttachment{}
+
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+func (r *PostgresAttachmentRepository) DeleteAttachment(ctx context.Context, id uint) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM task_attachments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to delete attachment: %w", err)
+	}
+
+	return expectAffected(res, ErrAttachmentNotFound)
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"to-do-list/internal/models"
+
+	_ "github.com/lib/pq"
+)
+
+type AuditFilter struct {
+	UserID *uint
+	Action models.AuditAction
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Offset int
+}
+
+type AuditRepository interface {
+	CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error
+	GetAuditEvents(ctx context.Context, filter AuditFilter) ([]models.AuditEvent, error)
+}
+
+type PostgresAuditRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAuditRepository(db *sql.DB) *PostgresAuditRepository {
+	return &PostgresAuditRepository{db: db}
+}
+
+func (r *PostgresAuditRepository) CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	query := `INSERT INTO audit_events (user_id, action, entity_type, entity_id, request_id, ip, before, after, metadata)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		event.UserID,
+		event.Action,
+		event.EntityType,
+		event.EntityID,
+		event.RequestID,
+		event.IP,
+		nullJSON(event.Before),
+		nullJSON(event.After),
+		nullJSON(event.Metadata),
+	).Scan(&event.ID, &event.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("repository: failed to create audit event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAuditRepository) GetAuditEvents(ctx context.Context, filter AuditFilter) ([]models.AuditEvent, error) {
+	var conditions []string
+	var args []any
+
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conditions = append(conditions, fmt.Sprintf("action = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	query := `SELECT id, user_id, action, entity_type, entity_id, request_id, ip, before, after, metadata, created_at FROM audit_events`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.AuditEvent
+
+	for rows.Next() {
+		var e models.AuditEvent
+		var userID, entityID sql.NullInt64
+		var before, after, metadata []byte
+
+		err := rows.Scan(&e.ID, &userID, &e.Action, &e.EntityType, &entityID, &e.RequestID, &e.IP, &before, &after, &metadata, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan audit event: %w", err)
+		}
+		if userID.Valid {
+			id := uint(userID.Int64)
+			e.UserID = &id
+		}
+		if entityID.Valid {
+			id := uint(entityID.Int64)
+			e.EntityID = &id
+		}
+		e.Before, e.After, e.Metadata = before, after, metadata
+
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// lib/pq передаёт []byte как bytea, поэтому JSON отдаём строкой, а пустой - как NULL
+func nullJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
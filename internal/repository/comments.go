@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"to-do-list/internal/models"
+)
+
+var ErrCommentNotFound = errors.New("repository: comment not found")
+
+type CommentRepository interface {
+	CreateComment(ctx context.Context, comment *models.TaskComment) error
+	GetCommentByID(ctx context.Context, id uint) (models.TaskComment, error)
+	GetCommentsByTaskID(ctx context.Context, taskID uint, limit, offset int) ([]models.TaskComment, error)
+	UpdateCommentBody(ctx context.Context, comment *models.TaskComment) error
+	DeleteComment(ctx context.Context, id uint) error
+}
+
+type PostgresCommentRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCommentRepository(db *sql.DB) *PostgresCommentRepository {
+	return &PostgresCommentRepository{db: db}
+}
+
+const commentColumns = `id, task_id, user_id, body, created_at, edited_at`
+
+func scanComment(row rowScanner) (models.TaskComment, error) {
+	var c models.TaskComment
+	err := row.Scan(&c.ID, &c.TaskID, &c.UserID, &c.Body, &c.CreatedAt, &c.EditedAt)
+	return c, err
+}
+
+func (r *PostgresCommentRepository) CreateComment(ctx context.Context, comment *models.TaskComment) error {
+	query := `INSERT INTO task_comments (task_id, user_id, body) VALUES ($1, $2, $3) RETURNING id, created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, comment.TaskID, comment.UserID, comment.Body).
+		Scan(&comment.ID, &comment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create comment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresCommentRepository) GetCommentByID(ctx context.Context, id uint) (models.TaskComment, error) {
+	query := `SELECT ` + commentColumns + ` FROM task_comments WHERE id = $1`
+
+	comment, err := scanComment(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TaskComment{}, ErrCommentNotFound
+	}
+	if err != nil {
+		return models.TaskComment{}, fmt.Errorf("repository: failed to get comment by id: %w", err)
+	}
+
+	return comment, nil
+}
+
+// GetCommentsByTaskID возвращает комментарии в порядке написания, старые первыми
+func (r *PostgresCommentRepository) GetCommentsByTaskID(ctx context.Context, taskID uint, limit, offset int) ([]models.TaskComment, error) {
+	query := `SELECT ` + commentColumns + ` FROM task_comments WHERE task_id = $1
+              ORDER BY created_at, id LIMIT $2 OFFSET $3`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, taskID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get task comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []models.TaskComment{}
+
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+// UpdateCommentBody меняет текст и проставляет edited_at
+func (r *PostgresCommentRepository) UpdateCommentBody(ctx context.Context, comment *models.TaskComment) error {
+	query := `UPDATE task_comments SET body = $1, edited_at = NOW() WHERE id = $2 RETURNING edited_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, comment.Body, comment.ID).Scan(&comment.EditedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("repository: failed to update comment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresCommentRepository) DeleteComment(ctx context.Context, id uint) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM task_comments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to delete comment: %w", err)
+	}
+
+	return expectAffected(res, ErrCommentNotFound)
+}
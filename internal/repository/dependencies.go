@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"to-do-list/internal/models"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrDependencyExists   = errors.New("repository: dependency already exists")
+	ErrDependencyNotFound = errors.New("repository: dependency not found")
+	ErrDependencyCycle    = errors.New("repository: dependency would create a cycle")
+)
+
+// AddTaskDependency помечает, что taskID нельзя начать до завершения dependsOnID.
+// Зависимости пользователя меняются под advisory-локом, иначе два встречных запроса
+// могли бы одновременно пройти проверку на цикл
+func (r *PostgresTaskRepository) AddTaskDependency(ctx context.Context, userID, taskID, dependsOnID uint) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("repository: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = advisoryXactLockFor(ctx, tx, lockClassTaskDependencies, userID)
+	if err != nil {
+		return err
+	}
+
+	// Цикл появится, если dependsOnID уже (транзитивно) ждёт taskID
+	cycleQuery := `WITH RECURSIVE chain AS (
+                       SELECT depends_on_id FROM task_dependencies WHERE task_id = $1
+                       UNION
+                       SELECT d.depends_on_id FROM task_dependencies d
+                       JOIN chain c ON d.task_id = c.depends_on_id
+                   )
+                   SELECT EXISTS (SELECT 1 FROM chain WHERE depends_on_id = $2)`
+
+	var cycle bool
+	err = tx.QueryRowContext(ctx, cycleQuery, dependsOnID, taskID).Scan(&cycle)
+	if err != nil {
+		return fmt.Errorf("repository: failed to check dependency cycle: %w", err)
+	}
+	if cycle {
+		return ErrDependencyCycle
+	}
+
+	query := `INSERT INTO task_dependencies (task_id, depends_on_id) VALUES ($1, $2)`
+
+	_, err = tx.ExecContext(ctx, query, taskID, dependsOnID)
+	if isUniqueViolation(err) {
+		return ErrDependencyExists
+	}
+	if err != nil {
+		return fmt.Errorf("repository: failed to add task dependency: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("repository: failed to commit task dependency: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTaskRepository) RemoveTaskDependency(ctx context.Context, taskID, dependsOnID uint) error {
+	query := `DELETE FROM task_dependencies WHERE task_id = $1 AND depends_on_id = $2`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, taskID, dependsOnID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to remove task dependency: %w", err)
+	}
+
+	return expectAffected(res, ErrDependencyNotFound)
+}
+
+// CountOpenDependencies считает незавершённые задачи, которые блокируют задачу
+func (r *PostgresTaskRepository) CountOpenDependencies(ctx context.Context, id uint) (int, error) {
+	query := `SELECT COUNT(*) FROM task_dependencies d JOIN tasks t ON t.id = d.depends_on_id
+              WHERE d.task_id = $1 AND t.status <> $2 AND t.deleted_at IS NULL`
+
+	var count int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id, models.StatusCompleted).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to count open dependencies: %w", err)
+	}
+
+	return count, nil
+}
+
+// loadDependencies заполняет BlockedBy и Blocks одним запросом для всех переданных задач
+func (r *PostgresTaskRepository) loadDependencies(ctx context.Context, tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(tasks))
+	index := make(map[uint]int, len(tasks))
+	for i := range tasks {
+		tasks[i].BlockedBy = []uint{}
+		tasks[i].Blocks = []uint{}
+		ids = append(ids, int64(tasks[i].ID))
+		index[tasks[i].ID] = i
+	}
+
+	// Связи с задачами из корзины не показываем
+	query := `SELECT d.task_id, d.depends_on_id FROM task_dependencies d
+              JOIN tasks t ON t.id = d.task_id AND t.deleted_at IS NULL
+              JOIN tasks b ON b.id = d.depends_on_id AND b.deleted_at IS NULL
+              WHERE d.task_id = ANY($1) OR d.depends_on_id = ANY($1)
+              ORDER BY d.task_id, d.depends_on_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("repository: failed to load task dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, dependsOnID uint
+		err := rows.Scan(&taskID, &dependsOnID)
+		if err != nil {
+			return fmt.Errorf("repository: failed to scan task dependency: %w", err)
+		}
+		if i, ok := index[taskID]; ok {
+			tasks[i].BlockedBy = append(tasks[i].BlockedBy, dependsOnID)
+		}
+		if i, ok := index[dependsOnID]; ok {
+			tasks[i].Blocks = append(tasks[i].Blocks, taskID)
+		}
+	}
+
+	return nil
+}
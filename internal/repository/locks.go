@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// Ключи advisory-локов фоновых задач. Значения произвольные, главное - уникальные
+const (
+	lockOverdueTasks int64 = 3001
+	lockPurgeTrash   int64 = 3002
+	lockAutoArchive  int64 = 3003
+)
+
+// Классы advisory-локов, которые берутся на конкретного пользователя
+const (
+	lockClassTaskDependencies int32 = 4001
+)
+
+// tryAdvisoryXactLock берёт транзакционный advisory-лок: если его держит другая реплика,
+// возвращает false, а сам лок снимается вместе с завершением транзакции
+func tryAdvisoryXactLock(ctx context.Context, tx querier, key int64) (bool, error) {
+	var locked bool
+	err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, key).Scan(&locked)
+	if err != nil {
+		return false, fmt.Errorf("repository: failed to acquire advisory lock: %w", err)
+	}
+	return locked, nil
+}
+
+// advisoryXactLockFor ждёт транзакционный advisory-лок класса class для сущности id.
+// Используется, чтобы сериализовать изменения, которые нельзя проверить одним запросом
+func advisoryXactLockFor(ctx context.Context, tx querier, class int32, id uint) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1, $2)`, class, int32(id))
+	if err != nil {
+		return fmt.Errorf("repository: failed to acquire advisory lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"to-do-list/internal/models"
+)
+
+var ErrNotificationNotFound = errors.New("repository: notification not found")
+
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, n *models.Notification) error
+	GetNotifications(ctx context.Context, userID uint, unreadOnly bool, limit, offset int) ([]models.Notification, error)
+	CountUnreadNotifications(ctx context.Context, userID uint) (int, error)
+	MarkNotificationRead(ctx context.Context, userID, id uint) (models.Notification, error)
+	MarkAllNotificationsRead(ctx context.Context, userID uint) (int64, error)
+}
+
+type PostgresNotificationRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresNotificationRepository(db *sql.DB) *PostgresNotificationRepository {
+	return &PostgresNotificationRepository{db: db}
+}
+
+const notificationColumns = `id, user_id, kind, task_id, title, body, read_at, created_at`
+
+func scanNotification(row rowScanner) (models.Notification, error) {
+	var n models.Notification
+	err := row.Scan(&n.ID, &n.UserID, &n.Kind, &n.TaskID, &n.Title, &n.Body, &n.ReadAt, &n.CreatedAt)
+	return n, err
+}
+
+func (r *PostgresNotificationRepository) CreateNotification(ctx context.Context, n *models.Notification) error {
+	query := `INSERT INTO notifications (user_id, kind, task_id, title, body, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, n.UserID, n.Kind, n.TaskID, n.Title, n.Body, n.CreatedAt).Scan(&n.ID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create notification: %w", err)
+	}
+
+	return nil
+}
+
+// GetNotifications возвращает уведомления пользователя, новые первыми
+func (r *PostgresNotificationRepository) GetNotifications(ctx context.Context, userID uint, unreadOnly bool, limit, offset int) ([]models.Notification, error) {
+	query := `SELECT ` + notificationColumns + ` FROM notifications
+              WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+              ORDER BY id DESC LIMIT $3 OFFSET $4`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, nil
+}
+
+func (r *PostgresNotificationRepository) CountUnreadNotifications(ctx context.Context, userID uint) (int, error) {
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+
+	var count int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// MarkNotificationRead отмечает уведомление прочитанным. Повторная отметка не сдвигает read_at.
+// Чужое уведомление неотличимо от несуществующего
+func (r *PostgresNotificationRepository) MarkNotificationRead(ctx context.Context, userID, id uint) (models.Notification, error) {
+	query := `UPDATE notifications SET read_at = COALESCE(read_at, NOW())
+              WHERE id = $1 AND user_id = $2 RETURNING ` + notificationColumns
+
+	n, err := scanNotification(conn(ctx, r.db).QueryRowContext(ctx, query, id, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Notification{}, ErrNotificationNotFound
+	}
+	if err != nil {
+		return models.Notification{}, fmt.Errorf("repository: failed to mark notification read: %w", err)
+	}
+
+	return n, nil
+}
+
+// MarkAllNotificationsRead отмечает прочитанными все уведомления пользователя и возвращает, сколько их было
+func (r *PostgresNotificationRepository) MarkAllNotificationsRead(ctx context.Context, userID uint) (int64, error) {
+	query := `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to mark notifications read: %w", err)
+	}
+
+	return res.RowsAffected()
+}
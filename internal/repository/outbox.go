@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type OutboxRepository interface {
+	AppendEvents(ctx context.Context, events ...models.DomainEvent) error
+	ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]models.DomainEvent, error)
+	MarkEventHandled(ctx context.Context, id uint, subscriber string) error
+	CompleteEvent(ctx context.Context, id uint) error
+	FailEvent(ctx context.Context, id uint, reason string, retryAt *time.Time) error
+	DeleteProcessedEvents(ctx context.Context, before time.Time) (int64, error)
+	GetEvent(ctx context.Context, id uint) (models.DomainEvent, error)
+	GetEventsAfter(ctx context.Context, afterID uint, limit int) ([]models.DomainEvent, error)
+	GetTaskEventsForUser(ctx context.Context, userID, afterID uint, limit int) ([]models.DomainEvent, error)
+}
+
+var ErrEventNotFound = errors.New("repository: outbox event not found")
+
+type PostgresOutboxRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresOutboxRepository(db *sql.DB) *PostgresOutboxRepository {
+	return &PostgresOutboxRepository{db: db}
+}
+
+const outboxEventColumns = `id, event_type, aggregate_type, aggregate_id, actor_id, request_id, ip, payload, status, handled, attempts, created_at`
+
+func scanOutboxEvent(row rowScanner) (models.DomainEvent, error) {
+	var e models.DomainEvent
+	err := row.Scan(&e.ID, &e.Type, &e.AggregateType, &e.AggregateID, &e.ActorID, &e.RequestID, &e.IP,
+		&e.Payload, &e.Status, pq.Array(&e.Handled), &e.Attempts, &e.CreatedAt)
+	return e, err
+}
+
+// AppendEvents пишет события в outbox. Чтобы событие не разошлось с изменением, вызывать его нужно
+// внутри Transactor.WithinTx вместе с самим изменением
+func (r *PostgresOutboxRepository) AppendEvents(ctx context.Context, events ...models.DomainEvent) error {
+	return appendEvents(ctx, conn(ctx, r.db), events)
+}
+
+func appendEvents(ctx context.Context, db querier, events []models.DomainEvent) error {
+	query := `INSERT INTO outbox_events (event_type, aggregate_type, aggregate_id, actor_id, request_id, ip, payload,
+                                       task_user_id, task_assignee_ids)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	for _, e := range events {
+		// Владелец и исполнители лежат в отдельных колонках, по ним GetTaskEventsForUser ищет события по индексу
+		var taskUserID *uint
+		assigneeIDs := []int64{}
+		userID, assignees, ok := e.TaskAudience()
+		if ok {
+			taskUserID = &userID
+			for _, id := range assignees {
+				assigneeIDs = append(assigneeIDs, int64(id))
+			}
+		}
+
+		_, err := db.ExecContext(ctx, query, e.Type, e.AggregateType, e.AggregateID, e.ActorID, e.RequestID, e.IP, []byte(e.Payload),
+			taskUserID, pq.Array(assigneeIDs))
+		if err != nil {
+			return fmt.Errorf("repository: failed to append outbox event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ClaimEvents забирает до limit событий в порядке записи и сдвигает им следующую попытку на lease,
+// как ClaimDueDeliveries у вебхуков: если диспетчер упадёт, событие разберут повторно
+func (r *PostgresOutboxRepository) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]models.DomainEvent, error) {
+	query := `WITH due AS (
+                  SELECT id FROM outbox_events
+                  WHERE status = 'pending' AND next_attempt_at <= NOW()
+                  ORDER BY id LIMIT $1
+                  FOR UPDATE SKIP LOCKED
+              )
+              UPDATE outbox_events e SET attempts = e.attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+              FROM due WHERE e.id = due.id
+              RETURNING e.id, e.event_type, e.aggregate_type, e.aggregate_id, e.actor_id, e.request_id, e.ip,
+                        e.payload, e.status, e.handled, e.attempts, e.created_at`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.DomainEvent
+
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan outbox event: %w", err)
+		}
+		events = append(events, event)
+	}
+	// RETURNING не сохраняет порядок подзапроса
+	slices.SortFunc(events, func(a, b models.DomainEvent) int { return cmp.Compare(a.ID, b.ID) })
+
+	return events, nil
+}
+
+// MarkEventHandled запоминает, что подписчик обработал событие
+func (r *PostgresOutboxRepository) MarkEventHandled(ctx context.Context, id uint, subscriber string) error {
+	query := `UPDATE outbox_events SET handled = array_append(handled, $1) WHERE id = $2 AND NOT ($1 = ANY(handled))`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, subscriber, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to mark outbox event handled: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresOutboxRepository) CompleteEvent(ctx context.Context, id uint) error {
+	query := `UPDATE outbox_events SET status = 'processed', last_error = NULL, processed_at = NOW() WHERE id = $1`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to complete outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// FailEvent записывает неудачную попытку. retryAt = nil означает, что попытки кончились
+func (r *PostgresOutboxRepository) FailEvent(ctx context.Context, id uint, reason string, retryAt *time.Time) error {
+	query := `UPDATE outbox_events SET last_error = $1,
+                  status = CASE WHEN $2::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+                  next_attempt_at = COALESCE($2, next_attempt_at)
+              WHERE id = $3`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, reason, retryAt, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to record outbox event failure: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteProcessedEvents удаляет обработанные события старше before. Неудавшиеся остаются для разбора
+func (r *PostgresOutboxRepository) DeleteProcessedEvents(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM outbox_events WHERE status = 'processed' AND processed_at < $1`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to delete processed outbox events: %w", err)
+	}
+
+	return res.RowsAffected()
+}
+
+func (r *PostgresOutboxRepository) GetEvent(ctx context.Context, id uint) (models.DomainEvent, error) {
+	query := `SELECT ` + outboxEventColumns + ` FROM outbox_events WHERE id = $1`
+
+	event, err := scanOutboxEvent(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DomainEvent{}, ErrEventNotFound
+	}
+	if err != nil {
+		return models.DomainEvent{}, fmt.Errorf("repository: failed to get outbox event: %w", err)
+	}
+
+	return event, nil
+}
+
+// GetEventsAfter возвращает до limit событий с id больше afterID в порядке записи
+func (r *PostgresOutboxRepository) GetEventsAfter(ctx context.Context, afterID uint, limit int) ([]models.DomainEvent, error) {
+	query := `SELECT ` + outboxEventColumns + ` FROM outbox_events WHERE id > $1 ORDER BY id LIMIT $2`
+
+	return r.queryEvents(ctx, query, afterID, limit)
+}
+
+// GetTaskEventsForUser возвращает события создания, изменения и удаления задач, которые пользователь
+// видит в потоке: он владелец задачи или её исполнитель до или после изменения. Нужен, чтобы клиент
+// потока дочитал пропущенное после Last-Event-ID. Глубже срока хранения outbox не достать
+func (r *PostgresOutboxRepository) GetTaskEventsForUser(ctx context.Context, userID, afterID uint, limit int) ([]models.DomainEvent, error) {
+	query := `SELECT ` + outboxEventColumns + ` FROM outbox_events
+              WHERE id > $2 AND event_type = ANY($3)
+                AND (task_user_id = $1 OR task_assignee_ids @> ARRAY[$1::integer])
+              ORDER BY id LIMIT $4`
+
+	types := []string{string(models.EventTaskCreated), string(models.EventTaskUpdated), string(models.EventTaskDeleted)}
+	return r.queryEvents(ctx, query, userID, afterID, pq.Array(types), limit)
+}
+
+func (r *PostgresOutboxRepository) queryEvents(ctx context.Context, query string, args ...any) ([]models.DomainEvent, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.DomainEvent{}
+
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan outbox event: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"to-do-list/internal/models"
+
+	_ "github.com/lib/pq"
+)
+
+var ErrProjectNotFound = errors.New("repository: project not found")
+
+type ProjectRepository interface {
+	CreateProject(ctx context.Context, project *models.Project) error
+	GetProjectByID(ctx context.Context, id uint) (models.Project, error)
+	GetProjectsByUserID(ctx context.Context, userID uint, includeArchived bool) ([]models.Project, error)
+	UpdateProject(ctx context.Context, project *models.Project) error
+	DeleteProject(ctx context.Context, id uint, deleteTasks bool) error
+}
+
+type PostgresProjectRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresProjectRepository(db *sql.DB) *PostgresProjectRepository {
+	return &PostgresProjectRepository{db: db}
+}
+
+const projectColumns = `id, user_id, name, color, archived, created_at, updated_at`
+
+func scanProject(row rowScanner) (models.Project, error) {
+	var p models.Project
+	err := row.Scan(&p.ID, &p.UserID, &p.Name, &p.Color, &p.Archived, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
+func (r *PostgresProjectRepository) CreateProject(ctx context.Context, project *models.Project) error {
+	query := `INSERT INTO projects (user_id, name, color) VALUES ($1, $2, $3) RETURNING id, created_at, updated_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, project.UserID, project.Name, project.Color).
+		Scan(&project.ID, &project.CreatedAt, &project.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create a project: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresProjectRepository) GetProjectByID(ctx context.Context, id uint) (models.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE id = $1`
+
+	project, err := scanProject(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Project{}, ErrProjectNotFound
+	}
+	if err != nil {
+		return models.Project{}, fmt.Errorf("repository: failed to get project by id: %w", err)
+	}
+
+	return project, nil
+}
+
+func (r *PostgresProjectRepository) GetProjectsByUserID(ctx context.Context, userID uint, includeArchived bool) ([]models.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE user_id = $1`
+	if !includeArchived {
+		query += ` AND NOT archived`
+	}
+	query += ` ORDER BY name, id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get projects by user id: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []models.Project
+
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan project: %w", err)
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+func (r *PostgresProjectRepository) UpdateProject(ctx context.Context, project *models.Project) error {
+	query := `UPDATE projects SET name = $1, color = $2, archived = $3, updated_at = NOW()
+              WHERE id = $4 RETURNING updated_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, project.Name, project.Color, project.Archived, project.ID).
+		Scan(&project.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrProjectNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("repository: failed to update project: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteProject удаляет проект. Без deleteTasks задачи остаются у пользователя без проекта (ON DELETE SET NULL)
+func (r *PostgresProjectRepository) DeleteProject(ctx context.Context, id uint, deleteTasks bool) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("repository: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Задачи уходят в корзину: после удаления проекта их ещё можно восстановить, уже без проекта
+	if deleteTasks {
+		rows, err := tx.QueryContext(ctx, `SELECT id FROM tasks WHERE project_id = $1 AND deleted_at IS NULL`, id)
+		if err != nil {
+			return fmt.Errorf("repository: failed to get project tasks: %w", err)
+		}
+		var ids []int64
+		for rows.Next() {
+			var taskID int64
+			err := rows.Scan(&taskID)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("repository: failed to scan task id: %w", err)
+			}
+			ids = append(ids, taskID)
+		}
+		rows.Close()
+
+		err = trashTaskSubtrees(ctx, tx, ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM projects WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to delete project: %w", err)
+	}
+	err = expectAffected(res, ErrProjectNotFound)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("repository: failed to commit project deletion: %w", err)
+	}
+
+	return nil
+}
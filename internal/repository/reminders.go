@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type ReminderRepository interface {
+	GetTaskReminders(ctx context.Context, taskID uint) ([]models.ReminderOffset, error)
+	SetTaskReminders(ctx context.Context, taskID uint, offsets []models.ReminderOffset) error
+	GetReminderPreferences(ctx context.Context, userID uint) (models.ReminderPreferences, error)
+	SetReminderPreferences(ctx context.Context, prefs *models.ReminderPreferences) error
+	ClaimDueReminders(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error)
+}
+
+type PostgresReminderRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresReminderRepository(db *sql.DB) *PostgresReminderRepository {
+	return &PostgresReminderRepository{db: db}
+}
+
+func offsetSeconds(offsets []models.ReminderOffset) []int64 {
+	seconds := make([]int64, 0, len(offsets))
+	for _, o := range offsets {
+		seconds = append(seconds, int64(time.Duration(o)/time.Second))
+	}
+	return seconds
+}
+
+func secondsToOffsets(seconds []int64) []models.ReminderOffset {
+	offsets := make([]models.ReminderOffset, 0, len(seconds))
+	for _, s := range seconds {
+		offsets = append(offsets, models.ReminderOffset(time.Duration(s)*time.Second))
+	}
+	return offsets
+}
+
+func channelNames(channels []models.NotificationChannelName) []string {
+	names := make([]string, 0, len(channels))
+	for _, c := range channels {
+		names = append(names, string(c))
+	}
+	return names
+}
+
+func toChannels(names []string) []models.NotificationChannelName {
+	channels := make([]models.NotificationChannelName, 0, len(names))
+	for _, n := range names {
+		channels = append(channels, models.NotificationChannelName(n))
+	}
+	return channels
+}
+
+// GetTaskReminders возвращает свои напоминания задачи от дальнего к ближнему
+func (r *PostgresReminderRepository) GetTaskReminders(ctx context.Context, taskID uint) ([]models.ReminderOffset, error) {
+	query := `SELECT offset_seconds FROM task_reminders WHERE task_id = $1 ORDER BY offset_seconds DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get task reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var seconds []int64
+
+	for rows.Next() {
+		var s int64
+		err := rows.Scan(&s)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan task reminder: %w", err)
+		}
+		seconds = append(seconds, s)
+	}
+	return secondsToOffsets(seconds), nil
+}
+
+// SetTaskReminders заменяет напоминания задачи. Пустой список возвращает задаче настройки получателя
+func (r *PostgresReminderRepository) SetTaskReminders(ctx context.Context, taskID uint, offsets []models.ReminderOffset) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("repository: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM task_reminders WHERE task_id = $1`, taskID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to clear task reminders: %w", err)
+	}
+
+	if len(offsets) > 0 {
+		query := `INSERT INTO task_reminders (task_id, offset_seconds) SELECT $1, unnest($2::integer[]) ON CONFLICT DO NOTHING`
+
+		_, err = tx.ExecContext(ctx, query, taskID, pq.Array(offsetSeconds(offsets)))
+		if err != nil {
+			return fmt.Errorf("repository: failed to set task reminders: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("repository: failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetReminderPreferences возвращает настройки пользователя; если он их не задавал - пустые:
+// без напоминаний по умолчанию и со всеми каналами
+func (r *PostgresReminderRepository) GetReminderPreferences(ctx context.Context, userID uint) (models.ReminderPreferences, error) {
+	query := `SELECT offsets, channels, updated_at FROM reminder_preferences WHERE user_id = $1`
+
+	prefs := models.ReminderPreferences{UserID: userID}
+	var seconds []int64
+	var channels []string
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(pq.Array(&seconds), pq.Array(&channels), &prefs.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return prefs, nil
+	}
+	if err != nil {
+		return models.ReminderPreferences{}, fmt.Errorf("repository: failed to get reminder preferences: %w", err)
+	}
+
+	prefs.Offsets = secondsToOffsets(seconds)
+	prefs.Channels = toChannels(channels)
+	return prefs, nil
+}
+
+func (r *PostgresReminderRepository) SetReminderPreferences(ctx context.Context, prefs *models.ReminderPreferences) error {
+	query := `INSERT INTO reminder_preferences (user_id, offsets, channels) VALUES ($1, $2, $3)
+              ON CONFLICT (user_id) DO UPDATE SET offsets = EXCLUDED.offsets, channels = EXCLUDED.channels, updated_at = NOW()
+              RETURNING updated_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, prefs.UserID, pq.Array(offsetSeconds(prefs.Offsets)), pq.Array(channelNames(prefs.Channels))).
+		Scan(&prefs.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: failed to set reminder preferences: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDueReminders находит напоминания, время которых пришло, и сразу записывает их отправленными -
+// так каждое срабатывает один раз, даже если планировщик работает на нескольких репликах.
+// Получатель - исполнитель задачи, а без него владелец. Если у задачи сработало сразу несколько
+// напоминаний (например, дедлайн поставили на завтра при напоминании за неделю), возвращается одно,
+// ближайшее к дедлайну, остальные просто отмечаются
+func (r *PostgresReminderRepository) ClaimDueReminders(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error) {
+	query := `WITH due AS (
+                  SELECT t.id AS task_id, COALESCE(t.assignee_id, t.user_id) AS user_id, o.offset_seconds, t.deadline
+                  FROM tasks t
+                  LEFT JOIN reminder_preferences p ON p.user_id = COALESCE(t.assignee_id, t.user_id)
+                  CROSS JOIN LATERAL (
+                      SELECT r.offset_seconds FROM task_reminders r WHERE r.task_id = t.id
+                      UNION ALL
+                      SELECT unnest(p.offsets) WHERE NOT EXISTS (SELECT 1 FROM task_reminders r WHERE r.task_id = t.id)
+                  ) o
+                  WHERE t.status IN ($3, $4) AND t.deleted_at IS NULL AND t.archived_at IS NULL
+                    AND t.deadline > $1 AND t.deadline <= $1 + make_interval(secs => $5)
+                    AND t.deadline - make_interval(secs => o.offset_seconds) <= $1
+                    AND NOT EXISTS (
+                        SELECT 1 FROM reminder_deliveries d
+                        WHERE d.task_id = t.id AND d.offset_seconds = o.offset_seconds AND d.deadline = t.deadline
+                    )
+                  ORDER BY t.deadline, t.id
+                  LIMIT $2
+              ), claimed AS (
+                  INSERT INTO reminder_deliveries (task_id, offset_seconds, deadline, user_id)
+                  SELECT task_id, offset_seconds, deadline, user_id FROM due
+                  ON CONFLICT DO NOTHING
+                  RETURNING task_id, offset_seconds, deadline, user_id
+              )
+              SELECT DISTINCT ON (c.task_id) c.task_id, c.user_id, t.name, c.offset_seconds, c.deadline, COALESCE(p.channels, '{}')
+              FROM claimed c
+              JOIN tasks t ON t.id = c.task_id
+              LEFT JOIN reminder_preferences p ON p.user_id = c.user_id
+              ORDER BY c.task_id, c.offset_seconds`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, now, limit, models.StatusPending, models.StatusInProgress, models.MaxReminderOffset.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to claim due reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []models.DueReminder
+
+	for rows.Next() {
+		var reminder models.DueReminder
+		var seconds int64
+		var channels []string
+		err := rows.Scan(&reminder.TaskID, &reminder.UserID, &reminder.TaskName, &seconds, &reminder.Deadline, pq.Array(&channels))
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan due reminder: %w", err)
+		}
+		reminder.Offset = models.ReminderOffset(time.Duration(seconds) * time.Second)
+		reminder.Channels = toChannels(channels)
+		reminders = append(reminders, reminder)
+	}
+	return reminders, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"to-do-list/internal/models"
+)
+
+var ErrRevisionNotFound = errors.New("repository: task revision not found")
+
+const revisionColumns = `id, task_id, user_id, changes, reverted_to_id, created_at`
+
+func scanRevision(row rowScanner) (models.TaskRevision, error) {
+	var revision models.TaskRevision
+	var changes []byte
+	err := row.Scan(&revision.ID, &revision.TaskID, &revision.UserID, &changes, &revision.RevertedToID, &revision.CreatedAt)
+	if err != nil {
+		return models.TaskRevision{}, err
+	}
+
+	err = json.Unmarshal(changes, &revision.Changes)
+	if err != nil {
+		return models.TaskRevision{}, fmt.Errorf("repository: failed to decode revision changes: %w", err)
+	}
+
+	return revision, nil
+}
+
+func (r *PostgresTaskRepository) CreateTaskRevision(ctx context.Context, revision *models.TaskRevision) error {
+	changes, err := json.Marshal(revision.Changes)
+	if err != nil {
+		return fmt.Errorf("repository: failed to encode revision changes: %w", err)
+	}
+
+	query := `INSERT INTO task_revisions (task_id, user_id, changes, reverted_to_id)
+              VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, revision.TaskID, revision.UserID, changes, revision.RevertedToID).
+		Scan(&revision.ID, &revision.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create task revision: %w", err)
+	}
+
+	return nil
+}
+
+// GetTaskRevisions возвращает историю задачи от новых ревизий к старым
+func (r *PostgresTaskRepository) GetTaskRevisions(ctx context.Context, taskID uint, limit, offset int) ([]models.TaskRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM task_revisions WHERE task_id = $1
+              ORDER BY id DESC LIMIT $2 OFFSET $3`
+
+	return r.queryRevisions(ctx, query, taskID, limit, offset)
+}
+
+// GetTaskRevisionsAfter возвращает ревизии задачи новее revisionID (от новых к старым) -
+// именно их нужно отменить, чтобы вернуть задачу к состоянию revisionID
+func (r *PostgresTaskRepository) GetTaskRevisionsAfter(ctx context.Context, taskID, revisionID uint) ([]models.TaskRevision, error) {
+	var exists bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM task_revisions WHERE id = $1 AND task_id = $2)`, revisionID, taskID).
+		Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to check task revision: %w", err)
+	}
+	if !exists {
+		return nil, ErrRevisionNotFound
+	}
+
+	query := `SELECT ` + revisionColumns + ` FROM task_revisions WHERE task_id = $1 AND id > $2 ORDER BY id DESC`
+
+	return r.queryRevisions(ctx, query, taskID, revisionID)
+}
+
+func (r *PostgresTaskRepository) queryRevisions(ctx context.Context, query string, args ...any) ([]models.TaskRevision, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get task revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.TaskRevision{}
+
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan task revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"to-do-list/internal/models"
+)
+
+var ErrSeriesNotFound = errors.New("repository: task series not found")
+
+type SeriesRepository interface {
+	CreateSeriesTask(ctx context.Context, series *models.TaskSeries, task *models.Task) error
+	CreateOccurrence(ctx context.Context, task *models.Task) (bool, error)
+	GetSeriesByID(ctx context.Context, id uint) (models.TaskSeries, error)
+	GetSeriesByUserID(ctx context.Context, userID uint) ([]models.TaskSeries, error)
+	UpdateSeries(ctx context.Context, series *models.TaskSeries) error
+}
+
+type PostgresSeriesRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSeriesRepository(db *sql.DB) *PostgresSeriesRepository {
+	return &PostgresSeriesRepository{db: db}
+}
+
+const seriesColumns = `id, user_id, rule, active, created_at, updated_at`
+
+func scanSeries(row rowScanner) (models.TaskSeries, error) {
+	var s models.TaskSeries
+	err := row.Scan(&s.ID, &s.UserID, &s.Rule, &s.Active, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+// CreateSeriesTask создаёт серию и её первый экземпляр в одной транзакции
+func (r *PostgresSeriesRepository) CreateSeriesTask(ctx context.Context, series *models.TaskSeries, task *models.Task) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("repository: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO task_series (user_id, rule, active) VALUES ($1, $2, $3) RETURNING id, created_at, updated_at`
+
+	err = tx.QueryRowContext(ctx, query, series.UserID, series.Rule, series.Active).
+		Scan(&series.ID, &series.CreatedAt, &series.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create task series: %w", err)
+	}
+
+	task.SeriesID = &series.ID
+	err = insertTask(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("repository: failed to commit task series: %w", err)
+	}
+
+	return nil
+}
+
+// errOccurrenceExists откатывает вставку экземпляра, который уже создан
+var errOccurrenceExists = errors.New("repository: task occurrence already exists")
+
+// CreateOccurrence добавляет следующий экземпляр серии вместе с событием task.created. false - экземпляр
+// с таким номером уже есть, так что обработчик завершения задачи и фоновая пометка просроченных не создадут дубль.
+// Внутри WithinTx вставка идёт в точке сохранения, поэтому нарушение уникальности не прерывает всю транзакцию
+func (r *PostgresSeriesRepository) CreateOccurrence(ctx context.Context, task *models.Task) (bool, error) {
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		db := conn(ctx, r.db)
+
+		err := insertTask(ctx, db, task)
+		if isUniqueViolation(err) {
+			return errOccurrenceExists
+		}
+		if err != nil {
+			return err
+		}
+
+		return appendEvents(ctx, db, []models.DomainEvent{models.NewTaskEvent(models.EventTaskCreated, nil, task)})
+	})
+	if errors.Is(err, errOccurrenceExists) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *PostgresSeriesRepository) GetSeriesByID(ctx context.Context, id uint) (models.TaskSeries, error) {
+	query := `SELECT ` + seriesColumns + ` FROM task_series WHERE id = $1`
+
+	series, err := scanSeries(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TaskSeries{}, ErrSeriesNotFound
+	}
+	if err != nil {
+		return models.TaskSeries{}, fmt.Errorf("repository: failed to get task series by id: %w", err)
+	}
+
+	return series, nil
+}
+
+func (r *PostgresSeriesRepository) GetSeriesByUserID(ctx context.Context, userID uint) ([]models.TaskSeries, error) {
+	query := `SELECT ` + seriesColumns + ` FROM task_series WHERE user_id = $1 ORDER BY created_at DESC, id DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get task series by user id: %w", err)
+	}
+	defer rows.Close()
+
+	var series []models.TaskSeries
+
+	for rows.Next() {
+		s, err := scanSeries(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan task series: %w", err)
+		}
+		series = append(series, s)
+	}
+
+	return series, nil
+}
+
+func (r *PostgresSeriesRepository) UpdateSeries(ctx context.Context, series *models.TaskSeries) error {
+	query := `UPDATE task_series SET rule = $1, active = $2, updated_at = NOW() WHERE id = $3 RETURNING updated_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, series.Rule, series.Active, series.ID).Scan(&series.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSeriesNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("repository: failed to update task series: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"to-do-list/internal/models"
+
+	_ "github.com/lib/pq"
+)
+
+var ErrSessionNotFound = errors.New("repository: session not found")
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *models.Session) error
+	TouchSession(ctx context.Context, tokenID string) error
+	GetActiveSessionsByUserID(ctx context.Context, userID uint) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID, id uint) error
+}
+
+type PostgresSessionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSessionRepository(db *sql.DB) *PostgresSessionRepository {
+	return &PostgresSessionRepository{db: db}
+}
+
+func (r *PostgresSessionRepository) CreateSession(ctx context.Context, session *models.Session) error {
+	query := `INSERT INTO sessions (user_id, token_id, user_agent, ip, expires_at)
+              VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, last_seen_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		session.UserID,
+		session.TokenID,
+		session.UserAgent,
+		session.IP,
+		session.ExpiresAt,
+	).Scan(&session.ID, &session.CreatedAt, &session.LastSeenAt)
+
+	if err != nil {
+		return fmt.Errorf("repository: failed to create a session: %w", err)
+	}
+
+	return nil
+}
+
+// TouchSession обновляет last_seen_at и заодно проверяет, что сессия не отозвана
+func (r *PostgresSessionRepository) TouchSession(ctx context.Context, tokenID string) error {
+	query := `UPDATE sessions SET last_seen_at = NOW()
+              WHERE token_id = $1 AND revoked_at IS NULL AND expires_at > NOW()`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, tokenID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to touch session: %w", err)
+	}
+
+	return expectAffected(res, ErrSessionNotFound)
+}
+
+func (r *PostgresSessionRepository) GetActiveSessionsByUserID(ctx context.Context, userID uint) ([]models.Session, error) {
+	query := `SELECT id, user_id, token_id, user_agent, ip, created_at, last_seen_at, expires_at
+              FROM sessions
+              WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+              ORDER BY last_seen_at DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get sessions by user id: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []models.Session
+
+	for rows.Next() {
+		var s models.Session
+		err := rows.Scan(&s.ID, &s.UserID, &s.TokenID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastSeenAt, &s.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+func (r *PostgresSessionRepository) RevokeSession(ctx context.Context, userID, id uint) error {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to revoke session: %w", err)
+	}
+
+	return expectAffected(res, ErrSessionNotFound)
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"to-do-list/internal/models"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrShareNotFound     = errors.New("repository: task share not found")
+	ErrShareUserNotFound = errors.New("repository: user to share with not found")
+	ErrShareWithOwner    = errors.New("repository: task can't be shared with its owner")
+)
+
+// ShareTask выдаёт пользователю с логином или почтой login доступ к задаче. Повторная выдача
+// меняет право у существующей записи, created показывает, была ли запись новой
+func (r *PostgresTaskRepository) ShareTask(ctx context.Context, taskID, ownerID uint, login string, permission models.SharePermission) (models.TaskShare, bool, error) {
+	share := models.TaskShare{TaskID: taskID, Permission: permission}
+
+	// Если login совпал и с чьим-то именем, и с чужой почтой, побеждает имя
+	userQuery := `SELECT id, username FROM users WHERE username = $1 OR email = $1
+                  ORDER BY (username = $1) DESC LIMIT 1`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, userQuery, login).Scan(&share.UserID, &share.Username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TaskShare{}, false, ErrShareUserNotFound
+	}
+	if err != nil {
+		return models.TaskShare{}, false, fmt.Errorf("repository: failed to find user to share with: %w", err)
+	}
+	if share.UserID == ownerID {
+		return models.TaskShare{}, false, ErrShareWithOwner
+	}
+
+	// xmax = 0 только у строки, вставленной этим запросом, а не обновлённой через ON CONFLICT
+	query := `INSERT INTO task_shares (task_id, user_id, permission) VALUES ($1, $2, $3)
+              ON CONFLICT (task_id, user_id) DO UPDATE SET permission = EXCLUDED.permission
+              RETURNING id, created_at, (xmax = 0)`
+
+	var created bool
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, taskID, share.UserID, permission).Scan(&share.ID, &share.CreatedAt, &created)
+	if err != nil {
+		return models.TaskShare{}, false, fmt.Errorf("repository: failed to share task: %w", err)
+	}
+
+	return share, created, nil
+}
+
+func (r *PostgresTaskRepository) GetTaskShares(ctx context.Context, taskID uint) ([]models.TaskShare, error) {
+	query := `SELECT s.id, s.task_id, s.user_id, u.username, s.permission, s.created_at
+              FROM task_shares s JOIN users u ON u.id = s.user_id
+              WHERE s.task_id = $1 ORDER BY s.created_at, s.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get task shares: %w", err)
+	}
+	defer rows.Close()
+
+	shares := []models.TaskShare{}
+
+	for rows.Next() {
+		var s models.TaskShare
+		err := rows.Scan(&s.ID, &s.TaskID, &s.UserID, &s.Username, &s.Permission, &s.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan task share: %w", err)
+		}
+		shares = append(shares, s)
+	}
+	return shares, nil
+}
+
+func (r *PostgresTaskRepository) RemoveTaskShare(ctx context.Context, taskID, userID uint) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM task_shares WHERE task_id = $1 AND user_id = $2`, taskID, userID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to remove task share: %w", err)
+	}
+
+	return expectAffected(res, ErrShareNotFound)
+}
+
+func (r *PostgresTaskRepository) GetTaskSharePermission(ctx context.Context, taskID, userID uint) (models.SharePermission, error) {
+	var permission models.SharePermission
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT permission FROM task_shares WHERE task_id = $1 AND user_id = $2`, taskID, userID).
+		Scan(&permission)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrShareNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("repository: failed to get task share permission: %w", err)
+	}
+
+	return permission, nil
+}
+
+// GetSharedTasks возвращает задачи, к которым пользователю выдали доступ, без задач из корзины
+func (r *PostgresTaskRepository) GetSharedTasks(ctx context.Context, userID uint, limit, offset int) ([]models.SharedTask, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks
+              WHERE id IN (SELECT task_id FROM task_shares WHERE user_id = $1) AND deleted_at IS NULL
+              ORDER BY deadline, id LIMIT $2 OFFSET $3`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get shared tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []models.Task
+	var ids []int64
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+		ids = append(ids, int64(task.ID))
+	}
+	rows.Close()
+
+	err = r.loadDetails(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make(map[uint]models.SharePermission, len(tasks))
+	if len(ids) > 0 {
+		rows, err := conn(ctx, r.db).QueryContext(ctx, `SELECT task_id, permission FROM task_shares WHERE user_id = $1 AND task_id = ANY($2)`,
+			userID, pq.Array(ids))
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to get share permissions: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var taskID uint
+			var permission models.SharePermission
+			err := rows.Scan(&taskID, &permission)
+			if err != nil {
+				return nil, fmt.Errorf("repository: failed to scan share permission: %w", err)
+			}
+			permissions[taskID] = permission
+		}
+	}
+
+	shared := make([]models.SharedTask, 0, len(tasks))
+	for _, task := range tasks {
+		shared = append(shared, models.SharedTask{Task: task, Permission: permissions[task.ID]})
+	}
+	return shared, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"to-do-list/internal/models"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrTagNotFound = errors.New("repository: tag not found")
+	ErrTagExists   = errors.New("repository: tag already exists")
+)
+
+type TagRepository interface {
+	CreateTag(ctx context.Context, tag *models.Tag) error
+	GetTagsByUserID(ctx context.Context, userID uint) ([]models.Tag, error)
+	RenameTag(ctx context.Context, userID, id uint, name string) error
+	DeleteTag(ctx context.Context, userID, id uint) error
+}
+
+type PostgresTagRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTagRepository(db *sql.DB) *PostgresTagRepository {
+	return &PostgresTagRepository{db: db}
+}
+
+func (r *PostgresTagRepository) CreateTag(ctx context.Context, tag *models.Tag) error {
+	query := `INSERT INTO tags (user_id, name) VALUES ($1, $2) RETURNING id, created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, tag.UserID, tag.Name).Scan(&tag.ID, &tag.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrTagExists
+	}
+	if err != nil {
+		return fmt.Errorf("repository: failed to create a tag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTagRepository) GetTagsByUserID(ctx context.Context, userID uint) ([]models.Tag, error) {
+	query := `SELECT id, user_id, name, created_at FROM tags WHERE user_id = $1 ORDER BY name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get tags by user id: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+
+	for rows.Next() {
+		var tag models.Tag
+		err := rows.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (r *PostgresTagRepository) RenameTag(ctx context.Context, userID, id uint, name string) error {
+	query := `UPDATE tags SET name = $1 WHERE id = $2 AND user_id = $3`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, name, id, userID)
+	if isUniqueViolation(err) {
+		return ErrTagExists
+	}
+	if err != nil {
+		return fmt.Errorf("repository: failed to rename tag: %w", err)
+	}
+
+	return expectAffected(res, ErrTagNotFound)
+}
+
+// DeleteTag снимает тег со всех задач (каскадом через task_tags), сами задачи не трогает
+func (r *PostgresTagRepository) DeleteTag(ctx context.Context, userID, id uint) error {
+	query := `DELETE FROM tags WHERE id = $1 AND user_id = $2`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to delete tag: %w", err)
+	}
+
+	return expectAffected(res, ErrTagNotFound)
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// expectAffected возвращает notFound, если запрос не затронул ни одной строки
+func expectAffected(res sql.Result, notFound error) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("repository: failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
@@ -3,10 +3,18 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 	"to-do-list/internal/models"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInvalidStatusTransition = errors.New("repository: invalid status transition")
+	ErrTaskNotFound            = errors.New("repository: task not found")
+	ErrParentInTrash           = errors.New("repository: parent task is in trash")
 )
 
 type TaskRepository interface {
@@ -14,9 +22,100 @@ type TaskRepository interface {
 	UpdateTaskName(ctx context.Context, id uint, name string) error
 	UpdateTaskDescription(ctx context.Context, id uint, description string) error
 	UpdateTaskStatus(ctx context.Context, id uint, status models.Status) error
+	UpdateTaskPriority(ctx context.Context, id uint, priority models.Priority) error
+	UpdateTaskDeadline(ctx context.Context, id uint, deadline time.Time) error
+	UpdateTaskProject(ctx context.Context, id uint, projectID *uint) error
 	DeleteTask(ctx context.Context, id uint) error
 	GetTaskByID(ctx context.Context, id uint) (models.Task, error)
-	GetTasksByUserID(ctx context.Context, userID uint, limit, offset int) ([]models.Task, error)
+	GetTasksWithAccess(ctx context.Context, userID uint, ids []uint) ([]models.AccessibleTask, error)
+	LockTask(ctx context.Context, id uint) (models.Task, error)
+	GetTasksByUserID(ctx context.Context, userID uint, filter TaskFilter) ([]models.Task, error)
+	SetTaskTags(ctx context.Context, taskID, userID uint, tags []string) error
+	UpdateTaskParent(ctx context.Context, id uint, parentID *uint) error
+	GetSubtasks(ctx context.Context, parentID uint) ([]models.Task, error)
+	GetTaskAncestorIDs(ctx context.Context, id uint) ([]uint, error)
+	GetSubtreeHeight(ctx context.Context, id uint) (int, error)
+	CountOpenSubtasks(ctx context.Context, id uint) (int, error)
+	AddTaskDependency(ctx context.Context, userID, taskID, dependsOnID uint) error
+	RemoveTaskDependency(ctx context.Context, taskID, dependsOnID uint) error
+	CountOpenDependencies(ctx context.Context, id uint) (int, error)
+	GetTrashedTasks(ctx context.Context, userID uint, limit, offset int) ([]models.Task, error)
+	RestoreTask(ctx context.Context, id uint) error
+	PurgeTask(ctx context.Context, id uint) error
+	GetTrashedTask(ctx context.Context, id uint) (models.Task, error)
+	EmptyTrash(ctx context.Context, userID uint) ([]models.Task, error)
+	SetTaskArchived(ctx context.Context, id uint, archived bool) error
+	ArchiveCompletedTasks(ctx context.Context, userID uint) ([]models.Task, error)
+	LockProjectTasks(ctx context.Context, projectID uint) ([]models.Task, error)
+	CreateTaskRevision(ctx context.Context, revision *models.TaskRevision) error
+	GetTaskRevisions(ctx context.Context, taskID uint, limit, offset int) ([]models.TaskRevision, error)
+	GetTaskRevisionsAfter(ctx context.Context, taskID, revisionID uint) ([]models.TaskRevision, error)
+	ShareTask(ctx context.Context, taskID, ownerID uint, login string, permission models.SharePermission) (models.TaskShare, bool, error)
+	GetTaskShares(ctx context.Context, taskID uint) ([]models.TaskShare, error)
+	RemoveTaskShare(ctx context.Context, taskID, userID uint) error
+	GetTaskSharePermission(ctx context.Context, taskID, userID uint) (models.SharePermission, error)
+	GetSharedTasks(ctx context.Context, userID uint, limit, offset int) ([]models.SharedTask, error)
+	GetWorkspaceRole(ctx context.Context, workspaceID, userID uint) (models.WorkspaceRole, error)
+	UpdateTaskWorkspace(ctx context.Context, id uint, workspaceID *uint) error
+	UpdateTaskAssignee(ctx context.Context, id uint, assigneeID *uint) error
+}
+
+type TaskSort string
+
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any"
+	TagMatchAll TagMatch = "all"
+)
+
+const (
+	SortByCreatedAt TaskSort = "created"
+	SortByPriority  TaskSort = "priority"
+	SortByDeadline  TaskSort = "deadline"
+)
+
+// TaskFilter - параметры выборки списка задач. Пустые поля не фильтруют
+type TaskFilter struct {
+	// WorkspaceID: nil - личные задачи и задачи всех пространств пользователя,
+	// указатель на 0 - только личные задачи, иначе - задачи одного пространства
+	WorkspaceID *uint
+	// AssignedToMe оставляет задачи, где пользователь - исполнитель, включая выданные ему через доступ
+	AssignedToMe bool
+	// ProjectID: nil - любые задачи, указатель на 0 - только задачи без проекта
+	ProjectID *uint
+	// Archived: nil - и архивные, и обычные задачи
+	Archived *bool
+	Priority models.Priority
+	Tags     []string
+	TagMatch TagMatch
+	Sort     TaskSort
+	Limit    int
+	Offset   int
+}
+
+// Пространства, в которых состоит пользователь $1
+const memberWorkspacesQuery = `SELECT workspace_id FROM workspace_members WHERE user_id = $1`
+
+const taskColumns = `id, user_id, workspace_id, assignee_id, project_id, parent_task_id, series_id, occurrence, name, description, created_at, updated_at, deadline, status, priority, completed_at, archived_at, deleted_at`
+
+// Приоритет хранится строкой, поэтому для сортировки переводим его в число
+const priorityRank = `CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END`
+
+var taskOrderBy = map[TaskSort]string{
+	SortByCreatedAt: `created_at DESC`,
+	SortByPriority:  priorityRank + ` DESC, deadline ASC`,
+	SortByDeadline:  `deadline ASC`,
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (models.Task, error) {
+	var task models.Task
+	err := row.Scan(&task.ID, &task.UserID, &task.WorkspaceID, &task.AssigneeID, &task.ProjectID, &task.ParentID, &task.SeriesID, &task.Occurrence, &task.Name, &task.Description, &task.CreatedAt, &task.UpdatedAt, &task.Deadline, &task.Status, &task.Priority, &task.CompletedAt, &task.ArchivedAt, &task.DeletedAt)
+	return task, err
 }
 
 type PostgresTaskRepository struct {
@@ -28,29 +127,57 @@ func NewPostgresTaskRepository(db *sql.DB) *PostgresTaskRepository {
 }
 
 func (r *PostgresTaskRepository) CreateTask(ctx context.Context, task *models.Task) error {
-	query := `INSERT INTO tasks (user_id, name, description, created_at, deadline, status)
-              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("repository: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = insertTask(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("repository: failed to commit task: %w", err)
+	}
 
-	err := r.db.QueryRowContext(ctx, query,
+	return nil
+}
+
+// insertTask вставляет задачу вместе с тегами в рамках переданной транзакции
+func insertTask(ctx context.Context, tx querier, task *models.Task) error {
+	query := `INSERT INTO tasks (user_id, workspace_id, assignee_id, project_id, parent_task_id, series_id, occurrence, name, description, created_at, deadline, status, priority)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id`
+
+	err := tx.QueryRowContext(ctx, query,
 		task.UserID,
+		task.WorkspaceID,
+		task.AssigneeID,
+		task.ProjectID,
+		task.ParentID,
+		task.SeriesID,
+		task.Occurrence,
 		task.Name,
 		task.Description,
 		task.CreatedAt,
 		task.Deadline,
 		task.Status,
+		task.Priority,
 	).Scan(&task.ID)
 
 	if err != nil {
 		return fmt.Errorf("repository: failed to create a task: %w", err)
 	}
 
-	return nil
+	return setTaskTags(ctx, tx, task.ID, task.UserID, task.Tags)
 }
 
 func (r *PostgresTaskRepository) UpdateTaskName(ctx context.Context, id uint, name string) error {
 	query := `UPDATE tasks SET name = $1, updated_at = NOW() WHERE id = $2`
 
-	_, err := r.db.ExecContext(ctx, query, name, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, name, id)
 
 	if err != nil {
 		return fmt.Errorf("repository: failed to update task name: %w", err)
@@ -62,7 +189,7 @@ func (r *PostgresTaskRepository) UpdateTaskName(ctx context.Context, id uint, na
 func (r *PostgresTaskRepository) UpdateTaskDescription(ctx context.Context, id uint, description string) error {
 	query := `UPDATE tasks SET description = $1, updated_at = NOW() WHERE id = $2`
 
-	_, err := r.db.ExecContext(ctx, query, description, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, description, id)
 
 	if err != nil {
 		return fmt.Errorf("repository: failed to update task description: %w", err)
@@ -71,46 +198,263 @@ func (r *PostgresTaskRepository) UpdateTaskDescription(ctx context.Context, id u
 	return nil
 }
 
+// UpdateTaskStatus меняет статус, только если переход разрешён из текущего статуса задачи.
+// Проверка в самом UPDATE защищает от гонки между чтением задачи и записью
 func (r *PostgresTaskRepository) UpdateTaskStatus(ctx context.Context, id uint, status models.Status) error {
-	query := `UPDATE tasks SET status = $1, updated_at = NOW() WHERE id = $2`
+	query := `UPDATE tasks
+              SET status = $1,
+                  completed_at = CASE WHEN $1 = 'completed' THEN NOW() ELSE NULL END,
+                  updated_at = NOW()
+              WHERE id = $2 AND status = ANY($3)`
 
-	_, err := r.db.ExecContext(ctx, query, status, id)
+	sources := make([]string, 0)
+	for _, from := range models.StatusesLeadingTo(status) {
+		sources = append(sources, string(from))
+	}
 
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, status, id, pq.Array(sources))
 	if err != nil {
 		return fmt.Errorf("repository: failed to update task status: %w", err)
 	}
 
+	return expectAffected(res, ErrInvalidStatusTransition)
+}
+
+func (r *PostgresTaskRepository) UpdateTaskPriority(ctx context.Context, id uint, priority models.Priority) error {
+	query := `UPDATE tasks SET priority = $1, updated_at = NOW() WHERE id = $2`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, priority, id)
+
+	if err != nil {
+		return fmt.Errorf("repository: failed to update task priority: %w", err)
+	}
+
 	return nil
 }
 
-func (r *PostgresTaskRepository) DeleteTask(ctx context.Context, id uint) error {
-	query := `DELETE FROM tasks WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+func (r *PostgresTaskRepository) UpdateTaskDeadline(ctx context.Context, id uint, deadline time.Time) error {
+	query := `UPDATE tasks SET deadline = $1, updated_at = NOW() WHERE id = $2`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, deadline, id)
 
 	if err != nil {
-		return fmt.Errorf("repository: failed to delete task: %w", err)
+		return fmt.Errorf("repository: failed to update task deadline: %w", err)
 	}
 
 	return nil
 }
 
-func (r *PostgresTaskRepository) GetTaskByID(ctx context.Context, id uint) (models.Task, error) {
-	var task models.Task
+func (r *PostgresTaskRepository) UpdateTaskProject(ctx context.Context, id uint, projectID *uint) error {
+	query := `UPDATE tasks SET project_id = $1, updated_at = NOW() WHERE id = $2`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, projectID, id)
+
+	if err != nil {
+		return fmt.Errorf("repository: failed to update task project: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTaskRepository) UpdateTaskParent(ctx context.Context, id uint, parentID *uint) error {
+	query := `UPDATE tasks SET parent_task_id = $1, updated_at = NOW() WHERE id = $2`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, parentID, id)
+
+	if err != nil {
+		return fmt.Errorf("repository: failed to update task parent: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTaskRepository) UpdateTaskAssignee(ctx context.Context, id uint, assigneeID *uint) error {
+	query := `UPDATE tasks SET assignee_id = $1, updated_at = NOW() WHERE id = $2`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, assigneeID, id)
+
+	if err != nil {
+		return fmt.Errorf("repository: failed to update task assignee: %w", err)
+	}
 
-	query := `SELECT id, user_id, name, description, created_at, updated_at, deadline, status FROM tasks WHERE id = $1`
-	row := r.db.QueryRowContext(ctx, query, id)
+	return nil
+}
 
-	err := row.Scan(&task.ID, &task.UserID, &task.Name, &task.Description, &task.CreatedAt, &task.UpdatedAt, &task.Deadline, &task.Status)
+// DeleteTask переносит задачу в корзину вместе со всеми подзадачами
+func (r *PostgresTaskRepository) DeleteTask(ctx context.Context, id uint) error {
+	return trashTaskSubtrees(ctx, conn(ctx, r.db), []int64{int64(id)})
+}
+
+func (r *PostgresTaskRepository) GetTaskByID(ctx context.Context, id uint) (models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND deleted_at IS NULL`
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, id)
+
+	task, err := scanTask(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Task{}, ErrTaskNotFound
+	}
 	if err != nil {
 		return models.Task{}, fmt.Errorf("repository: failed to get task by id: %w", err)
 	}
 
-	return task, nil
+	tasks := []models.Task{task}
+	err = r.loadDetails(ctx, tasks)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return tasks[0], nil
 }
 
-func (r *PostgresTaskRepository) GetTasksByUserID(ctx context.Context, userID uint, limit, offset int) ([]models.Task, error) {
-	query := `SELECT id, user_id, name, description, created_at, updated_at, deadline, status FROM tasks WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+// LockTask загружает задачу и блокирует её до конца транзакции, чтобы прочитанное состояние
+// не изменилось до записи. Вызывать внутри WithinTx
+func (r *PostgresTaskRepository) LockTask(ctx context.Context, id uint) (models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	task, err := scanTask(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Task{}, ErrTaskNotFound
+	}
+	if err != nil {
+		return models.Task{}, fmt.Errorf("repository: failed to lock task: %w", err)
+	}
+
+	tasks := []models.Task{task}
+	err = r.loadDetails(ctx, tasks)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return tasks[0], nil
+}
+
+// GetTasksWithAccess одним запросом загружает задачи вместе с доступом к ним пользователя. Задачи
+// блокируются до конца транзакции, чтобы их не изменили между проверкой и изменением. Несуществующих
+// и удалённых задач в ответе нет, порядок - как в ids
+func (r *PostgresTaskRepository) GetTasksWithAccess(ctx context.Context, userID uint, ids []uint) ([]models.AccessibleTask, error) {
+	query := `SELECT t.id, t.user_id, t.workspace_id, t.assignee_id, t.project_id, t.parent_task_id, t.series_id, t.occurrence,
+                     t.name, t.description, t.created_at, t.updated_at, t.deadline, t.status, t.priority,
+                     t.completed_at, t.archived_at, t.deleted_at, m.role, s.permission
+              FROM tasks t
+              LEFT JOIN workspace_members m ON m.workspace_id = t.workspace_id AND m.user_id = $1
+              LEFT JOIN task_shares s ON s.task_id = t.id AND s.user_id = $1
+              WHERE t.id = ANY($2) AND t.deleted_at IS NULL
+              FOR UPDATE OF t`
+
+	taskIDs := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		taskIDs = append(taskIDs, int64(id))
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, pq.Array(taskIDs))
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get tasks with access: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+	access := map[uint]models.TaskAccess{}
+
+	for rows.Next() {
+		var task models.Task
+		var role *models.WorkspaceRole
+		var share *models.SharePermission
+		err := rows.Scan(&task.ID, &task.UserID, &task.WorkspaceID, &task.AssigneeID, &task.ProjectID, &task.ParentID, &task.SeriesID, &task.Occurrence,
+			&task.Name, &task.Description, &task.CreatedAt, &task.UpdatedAt, &task.Deadline, &task.Status, &task.Priority,
+			&task.CompletedAt, &task.ArchivedAt, &task.DeletedAt, &role, &share)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+		access[task.ID] = models.ResolveTaskAccess(task, userID, role, share)
+	}
+	rows.Close()
+
+	err = r.loadDetails(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	result := []models.AccessibleTask{}
+	for _, id := range ids {
+		if task, ok := byID[id]; ok {
+			result = append(result, models.AccessibleTask{Task: task, Access: access[id]})
+		}
+	}
+	return result, nil
+}
+
+func (r *PostgresTaskRepository) GetTasksByUserID(ctx context.Context, userID uint, filter TaskFilter) ([]models.Task, error) {
+	args := []any{userID}
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE deleted_at IS NULL`
+
+	// Видимость задачи определяется членством в пространстве, а не только автором
+	switch {
+	case filter.WorkspaceID == nil:
+		scope := "user_id = $1 OR workspace_id IN (" + memberWorkspacesQuery + ")"
+		if filter.AssignedToMe {
+			scope += " OR id IN (SELECT task_id FROM task_shares WHERE user_id = $1)"
+		}
+		query += " AND (" + scope + ")"
+	case *filter.WorkspaceID == 0:
+		query += " AND user_id = $1 AND workspace_id IS NULL"
+	default:
+		args = append(args, *filter.WorkspaceID)
+		query += fmt.Sprintf(" AND workspace_id = $%d AND workspace_id IN ("+memberWorkspacesQuery+")", len(args))
+	}
+
+	if filter.AssignedToMe {
+		query += " AND assignee_id = $1"
+	}
+
+	if filter.ProjectID != nil {
+		if *filter.ProjectID == 0 {
+			query += " AND project_id IS NULL"
+		} else {
+			args = append(args, *filter.ProjectID)
+			query += fmt.Sprintf(" AND project_id = $%d", len(args))
+		}
+	}
+
+	if filter.Archived != nil {
+		if *filter.Archived {
+			query += " AND archived_at IS NOT NULL"
+		} else {
+			query += " AND archived_at IS NULL"
+		}
+	}
+
+	if filter.Priority != "" {
+		args = append(args, filter.Priority)
+		query += fmt.Sprintf(" AND priority = $%d", len(args))
+	}
+
+	if len(filter.Tags) > 0 {
+		args = append(args, pq.Array(filter.Tags))
+		tagSubquery := fmt.Sprintf(`SELECT tt.task_id FROM task_tags tt JOIN tags t ON t.id = tt.tag_id
+                      WHERE t.name = ANY($%d)`, len(args))
+
+		// all - у задачи должны быть все перечисленные теги, any - хотя бы один
+		if filter.TagMatch == TagMatchAll {
+			args = append(args, len(filter.Tags))
+			tagSubquery += fmt.Sprintf(" GROUP BY tt.task_id HAVING COUNT(DISTINCT t.name) = $%d", len(args))
+		}
+		query += " AND id IN (" + tagSubquery + ")"
+	}
+
+	orderBy, ok := taskOrderBy[filter.Sort]
+	if !ok {
+		orderBy = taskOrderBy[SortByCreatedAt]
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY %s, id DESC LIMIT $%d OFFSET $%d", orderBy, len(args)-1, len(args))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("repository: failed to get tasks by user id: %w", err)
 	}
@@ -119,12 +463,349 @@ func (r *PostgresTaskRepository) GetTasksByUserID(ctx context.Context, userID ui
 	var tasks []models.Task
 
 	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(&task.ID, &task.UserID, &task.Name, &task.Description, &task.CreatedAt, &task.UpdatedAt, &task.Deadline, &task.Status)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, fmt.Errorf("repository: failed to scan task: %w", err)
 		}
 		tasks = append(tasks, task)
 	}
+
+	err = r.loadDetails(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetSubtasks возвращает прямых потомков задачи
+func (r *PostgresTaskRepository) GetSubtasks(ctx context.Context, parentID uint) ([]models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE parent_task_id = $1 AND deleted_at IS NULL ORDER BY created_at, id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get subtasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	err = r.loadDetails(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// LockProjectTasks возвращает задачи проекта вне корзины и блокирует их до конца транзакции,
+// чтобы удаление проекта записало события ровно о тех задачах, которые оно затронуло
+func (r *PostgresTaskRepository) LockProjectTasks(ctx context.Context, projectID uint) ([]models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE project_id = $1 AND deleted_at IS NULL ORDER BY id FOR UPDATE`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get project tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	err = r.loadDetails(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetTaskAncestorIDs возвращает цепочку родителей задачи от ближайшего к корню.
+// Ограничение глубины в CTE страхует от зацикливания, если цикл всё же попал в базу
+func (r *PostgresTaskRepository) GetTaskAncestorIDs(ctx context.Context, id uint) ([]uint, error) {
+	query := `WITH RECURSIVE ancestors AS (
+                  SELECT parent_task_id AS id, 1 AS depth FROM tasks
+                  WHERE id = $1 AND parent_task_id IS NOT NULL
+                  UNION ALL
+                  SELECT t.parent_task_id, a.depth + 1 FROM tasks t
+                  JOIN ancestors a ON t.id = a.id
+                  WHERE t.parent_task_id IS NOT NULL AND a.depth <= $2
+              )
+              SELECT id FROM ancestors ORDER BY depth`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, id, models.MaxSubtaskDepth)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get task ancestors: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uint
+
+	for rows.Next() {
+		var ancestorID uint
+		err := rows.Scan(&ancestorID)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan task ancestor: %w", err)
+		}
+		ids = append(ids, ancestorID)
+	}
+
+	return ids, nil
+}
+
+// GetSubtreeHeight возвращает число уровней подзадач под задачей (0, если подзадач нет)
+func (r *PostgresTaskRepository) GetSubtreeHeight(ctx context.Context, id uint) (int, error) {
+	query := `WITH RECURSIVE subtree AS (
+                  SELECT id, 0 AS depth FROM tasks WHERE id = $1
+                  UNION ALL
+                  SELECT t.id, s.depth + 1 FROM tasks t
+                  JOIN subtree s ON t.parent_task_id = s.id
+                  WHERE s.depth <= $2
+              )
+              SELECT COALESCE(MAX(depth), 0) FROM subtree`
+
+	var height int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id, models.MaxSubtaskDepth).Scan(&height)
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to get subtree height: %w", err)
+	}
+
+	return height, nil
+}
+
+// CountOpenSubtasks считает прямые подзадачи в статусах pending и in progress
+func (r *PostgresTaskRepository) CountOpenSubtasks(ctx context.Context, id uint) (int, error) {
+	query := `SELECT COUNT(*) FROM tasks WHERE parent_task_id = $1 AND status IN ($2, $3) AND deleted_at IS NULL`
+
+	var count int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id, models.StatusPending, models.StatusInProgress).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to count open subtasks: %w", err)
+	}
+
+	return count, nil
+}
+
+// SetTaskTags заменяет теги задачи. Теги, которых у пользователя ещё нет, создаются
+func (r *PostgresTaskRepository) SetTaskTags(ctx context.Context, taskID, userID uint, tags []string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("repository: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM task_tags WHERE task_id = $1`, taskID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to clear task tags: %w", err)
+	}
+
+	err = setTaskTags(ctx, tx, taskID, userID, tags)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE tasks SET updated_at = NOW() WHERE id = $1`, taskID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to touch task: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("repository: failed to commit task tags: %w", err)
+	}
+
+	return nil
+}
+
+func setTaskTags(ctx context.Context, tx querier, taskID, userID uint, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	// DO UPDATE вместо DO NOTHING, чтобы RETURNING вернул id и для уже существующих тегов
+	upsertQuery := `INSERT INTO tags (user_id, name) SELECT $1, unnest($2::varchar[])
+                    ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+                    RETURNING id`
+
+	rows, err := tx.QueryContext(ctx, upsertQuery, userID, pq.Array(tags))
+	if err != nil {
+		return fmt.Errorf("repository: failed to upsert tags: %w", err)
+	}
+
+	var tagIDs []int64
+	for rows.Next() {
+		var id int64
+		err := rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("repository: failed to scan tag id: %w", err)
+		}
+		tagIDs = append(tagIDs, id)
+	}
+	rows.Close()
+
+	linkQuery := `INSERT INTO task_tags (task_id, tag_id) SELECT $1, unnest($2::int[]) ON CONFLICT DO NOTHING`
+
+	_, err = tx.ExecContext(ctx, linkQuery, taskID, pq.Array(tagIDs))
+	if err != nil {
+		return fmt.Errorf("repository: failed to link tags to task: %w", err)
+	}
+
+	return nil
+}
+
+// loadDetails подтягивает связанные с задачами данные, которых нет в самой таблице tasks
+func (r *PostgresTaskRepository) loadDetails(ctx context.Context, tasks []models.Task) error {
+	err := r.loadTags(ctx, tasks)
+	if err != nil {
+		return err
+	}
+	return r.loadDependencies(ctx, tasks)
+}
+
+// loadTags подтягивает имена тегов одним запросом для всех переданных задач
+func (r *PostgresTaskRepository) loadTags(ctx context.Context, tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(tasks))
+	index := make(map[uint]int, len(tasks))
+	for i := range tasks {
+		tasks[i].Tags = []string{}
+		ids = append(ids, int64(tasks[i].ID))
+		index[tasks[i].ID] = i
+	}
+
+	query := `SELECT tt.task_id, t.name FROM task_tags tt JOIN tags t ON t.id = tt.tag_id
+              WHERE tt.task_id = ANY($1) ORDER BY t.name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("repository: failed to load task tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID uint
+		var name string
+		err := rows.Scan(&taskID, &name)
+		if err != nil {
+			return fmt.Errorf("repository: failed to scan task tag: %w", err)
+		}
+		i := index[taskID]
+		tasks[i].Tags = append(tasks[i].Tags, name)
+	}
+
+	return nil
+}
+
+// MarkOverdueTasksFailed переводит в failed незавершённые задачи с дедлайном раньше cutoff
+// и в той же транзакции пишет события их изменения и вызывает spawn для каждой задачи, чтобы создать
+// следующий экземпляр серии. Ошибка spawn откатывает всю пачку. Выполняется под advisory-локом,
+// поэтому при нескольких репликах работает только одна
+func (r *PostgresTaskRepository) MarkOverdueTasksFailed(ctx context.Context, cutoff time.Time, spawn func(ctx context.Context, task models.Task) error) ([]models.Task, error) {
+	var tasks []models.Task
+
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+
+		locked, err := tryAdvisoryXactLock(ctx, tx, lockOverdueTasks)
+		if err != nil || !locked {
+			return err
+		}
+
+		// Прежние статус и время изменения нужны событиям: задача могла быть как pending, так и in progress
+		query := `SELECT id, status, updated_at FROM tasks
+                  WHERE status IN ($1, $2) AND deadline < $3 AND deleted_at IS NULL
+                  FOR UPDATE`
+
+		rows, err := tx.QueryContext(ctx, query, models.StatusPending, models.StatusInProgress, cutoff)
+		if err != nil {
+			return fmt.Errorf("repository: failed to get overdue tasks: %w", err)
+		}
+
+		var ids []int64
+		previous := map[uint]models.Task{}
+
+		for rows.Next() {
+			var task models.Task
+			err := rows.Scan(&task.ID, &task.Status, &task.UpdatedAt)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("repository: failed to scan task: %w", err)
+			}
+			ids = append(ids, int64(task.ID))
+			previous[task.ID] = task
+		}
+		rows.Close()
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		query = `UPDATE tasks SET status = $1, updated_at = NOW() WHERE id = ANY($2) RETURNING ` + taskColumns
+
+		rows, err = tx.QueryContext(ctx, query, models.StatusFailed, pq.Array(ids))
+		if err != nil {
+			return fmt.Errorf("repository: failed to mark overdue tasks: %w", err)
+		}
+
+		for rows.Next() {
+			task, err := scanTask(rows)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("repository: failed to scan task: %w", err)
+			}
+			tasks = append(tasks, task)
+		}
+		rows.Close()
+
+		// Теги нужны событиям и следующему экземпляру повторяющейся задачи
+		err = r.loadDetails(ctx, tasks)
+		if err != nil {
+			return err
+		}
+
+		var events []models.DomainEvent
+		for _, task := range tasks {
+			before := task
+			before.Status = previous[task.ID].Status
+			before.UpdatedAt = previous[task.ID].UpdatedAt
+			events = append(events, models.TaskUpdateEvents(before, task)...)
+		}
+
+		err = appendEvents(ctx, tx, events)
+		if err != nil {
+			return err
+		}
+
+		for _, task := range tasks {
+			err = spawn(ctx, task)
+			if err != nil {
+				return fmt.Errorf("repository: failed to spawn next occurrence of task %d: %w", task.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return tasks, nil
 }
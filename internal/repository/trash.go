@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// taskSubtreeCTE выбирает задачи с id из массива $1 вместе со всеми их подзадачами
+const taskSubtreeCTE = `WITH RECURSIVE subtree AS (
+                            SELECT id FROM tasks WHERE id = ANY($1)
+                            UNION
+                            SELECT t.id FROM tasks t JOIN subtree s ON t.parent_task_id = s.id
+                        ) `
+
+// trashTaskSubtrees переносит задачи в корзину вместе с подзадачами. У всего поддерева одинаковый deleted_at,
+// по нему восстановление отличает подзадачи, удалённые вместе с родителем, от удалённых раньше
+func trashTaskSubtrees(ctx context.Context, db execer, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := taskSubtreeCTE + `UPDATE tasks SET deleted_at = $2, updated_at = NOW()
+              WHERE id IN (SELECT id FROM subtree) AND deleted_at IS NULL`
+
+	_, err := db.ExecContext(ctx, query, pq.Array(ids), time.Now())
+	if err != nil {
+		return fmt.Errorf("repository: failed to move tasks to trash: %w", err)
+	}
+
+	return nil
+}
+
+// trashScope - задачи, которыми пользователь $1 распоряжается как владелец (models.ResolveTaskAccess):
+// свои и задачи пространств, где он владелец или администратор. Это те же задачи, которые он может удалить
+const trashScope = `(t.user_id = $1 OR t.workspace_id IN (
+                        SELECT workspace_id FROM workspace_members WHERE user_id = $1 AND role IN ('owner', 'admin')))`
+
+// GetTrashedTasks возвращает удалённые задачи, которые пользователь может восстановить. Подзадачи,
+// удалённые вместе с родителем, в список не попадают - они восстанавливаются и удаляются вместе с ним
+func (r *PostgresTaskRepository) GetTrashedTasks(ctx context.Context, userID uint, limit, offset int) ([]models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks t
+              WHERE ` + trashScope + ` AND t.deleted_at IS NOT NULL
+              AND NOT EXISTS (SELECT 1 FROM tasks p WHERE p.id = t.parent_task_id AND p.deleted_at IS NOT NULL)
+              ORDER BY t.deleted_at DESC, t.id DESC LIMIT $2 OFFSET $3`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get trashed tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	err = r.loadTags(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetTrashedTask загружает задачу из корзины и блокирует её до конца транзакции. Права проверяет вызывающий
+func (r *PostgresTaskRepository) GetTrashedTask(ctx context.Context, id uint) (models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`
+
+	task, err := scanTask(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Task{}, ErrTaskNotFound
+	}
+	if err != nil {
+		return models.Task{}, fmt.Errorf("repository: failed to get trashed task: %w", err)
+	}
+
+	tasks := []models.Task{task}
+	err = r.loadDetails(ctx, tasks)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return tasks[0], nil
+}
+
+// RestoreTask возвращает задачу из корзины вместе с подзадачами, удалёнными одновременно с ней.
+// Права проверяет вызывающий, как и для GetTrashedTask
+func (r *PostgresTaskRepository) RestoreTask(ctx context.Context, id uint) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("repository: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT t.deleted_at, COALESCE(p.deleted_at IS NOT NULL, FALSE) FROM tasks t
+              LEFT JOIN tasks p ON p.id = t.parent_task_id
+              WHERE t.id = $1 AND t.deleted_at IS NOT NULL
+              FOR UPDATE OF t`
+
+	var deletedAt time.Time
+	var parentTrashed bool
+	err = tx.QueryRowContext(ctx, query, id).Scan(&deletedAt, &parentTrashed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTaskNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("repository: failed to get trashed task: %w", err)
+	}
+	if parentTrashed {
+		return ErrParentInTrash
+	}
+
+	restoreQuery := taskSubtreeCTE + `UPDATE tasks SET deleted_at = NULL, updated_at = NOW()
+                     WHERE id IN (SELECT id FROM subtree) AND deleted_at = $2`
+
+	_, err = tx.ExecContext(ctx, restoreQuery, pq.Array([]int64{int64(id)}), deletedAt)
+	if err != nil {
+		return fmt.Errorf("repository: failed to restore task: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("repository: failed to commit task restore: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeTask окончательно удаляет задачу из корзины, подзадачи удаляются каскадом. Права проверяет вызывающий
+func (r *PostgresTaskRepository) PurgeTask(ctx context.Context, id uint) error {
+	query := `DELETE FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to purge task: %w", err)
+	}
+
+	return expectAffected(res, ErrTaskNotFound)
+}
+
+// EmptyTrash окончательно удаляет всё, что пользователь видит в корзине (см. trashScope), и возвращает
+// удалённые задачи, чтобы вызывающий мог записать события о них
+func (r *PostgresTaskRepository) EmptyTrash(ctx context.Context, userID uint) ([]models.Task, error) {
+	var tasks []models.Task
+
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		var err error
+		tasks, err = r.deleteTasks(ctx, `SELECT `+taskColumns+` FROM tasks t WHERE `+trashScope+` AND t.deleted_at IS NOT NULL FOR UPDATE OF t`, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// deleteTasks окончательно удаляет задачи, которые выбирает query, и возвращает их. Теги и зависимости
+// читаются до удаления, пока связи ещё на месте. Вызывать внутри WithinTx
+func (r *PostgresTaskRepository) deleteTasks(ctx context.Context, query string, args ...any) ([]models.Task, error) {
+	tx := conn(ctx, r.db)
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get trashed tasks: %w", err)
+	}
+
+	tasks := []models.Task{}
+	ids := []int64{}
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("repository: failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+		ids = append(ids, int64(task.ID))
+	}
+	rows.Close()
+
+	err = r.loadDetails(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM tasks WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to delete tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// PurgeDeletedTasks окончательно удаляет задачи, лежащие в корзине дольше cutoff, и в той же транзакции
+// пишет о них task.deleted. Как и пометка просроченных, выполняется только на одной реплике
+func (r *PostgresTaskRepository) PurgeDeletedTasks(ctx context.Context, cutoff time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+
+	err := withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+
+		locked, err := tryAdvisoryXactLock(ctx, tx, lockPurgeTrash)
+		if err != nil || !locked {
+			return err
+		}
+
+		tasks, err = r.deleteTasks(ctx, `SELECT `+taskColumns+` FROM tasks WHERE deleted_at < $1 FOR UPDATE`, cutoff)
+		if err != nil {
+			return err
+		}
+
+		events := make([]models.DomainEvent, 0, len(tasks))
+		for i := range tasks {
+			events = append(events, models.NewTaskEvent(models.EventTaskDeleted, &tasks[i], nil))
+		}
+		return appendEvents(ctx, tx, events)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// querier - общее у *sql.DB и *sql.Tx
+type querier interface {
+	execer
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// Transactor выполняет несколько вызовов репозиториев в одной транзакции. Транзакция передаётся
+// через контекст fn, и все методы Postgres-репозиториев, получившие такой контекст, работают в ней
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type PostgresTransactor struct {
+	db *sql.DB
+}
+
+func NewPostgresTransactor(db *sql.DB) *PostgresTransactor {
+	return &PostgresTransactor{db: db}
+}
+
+// WithinTx фиксирует транзакцию, если fn вернула nil, иначе откатывает. Вложенный вызов
+// переиспользует внешнюю транзакцию через точку сохранения: при ошибке fn откатывается только
+// сделанное в нём, а внешний вызов решает, продолжать ли транзакцию
+func (t *PostgresTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withinTx(ctx, t.db, fn)
+}
+
+func withinTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return withinSavepoint(ctx, tx, fn)
+	}
+
+	tx, err := beginTx(ctx, db)
+	if err != nil {
+		return fmt.Errorf("repository: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = fn(context.WithValue(ctx, txKey{}, tx.Tx))
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("repository: failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// withinSavepoint выполняет fn внутри уже открытой транзакции. Имя точки одно на всех: ROLLBACK TO
+// и RELEASE относятся к последней точке с этим именем, поэтому вложенность любой глубины работает
+func withinSavepoint(ctx context.Context, tx *sql.Tx, fn func(ctx context.Context) error) error {
+	_, err := tx.ExecContext(ctx, `SAVEPOINT nested_tx`)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create savepoint: %w", err)
+	}
+
+	err = fn(ctx)
+	if err != nil {
+		_, rbErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT nested_tx`)
+		if rbErr != nil {
+			return errors.Join(err, fmt.Errorf("repository: failed to roll back to savepoint: %w", rbErr))
+		}
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `RELEASE SAVEPOINT nested_tx`)
+	if err != nil {
+		return fmt.Errorf("repository: failed to release savepoint: %w", err)
+	}
+
+	return nil
+}
+
+// conn возвращает транзакцию из контекста, а без неё - сам пул
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// scopedTx - транзакция, которую открывает сам метод репозитория. Если метод вызван внутри WithinTx,
+// это внешняя транзакция, и Commit/Rollback ничего не делают: её судьбу решает тот, кто её открыл
+type scopedTx struct {
+	*sql.Tx
+	nested bool
+}
+
+func beginTx(ctx context.Context, db *sql.DB) (*scopedTx, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &scopedTx{Tx: tx, nested: true}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &scopedTx{Tx: tx}, nil
+}
+
+func (t *scopedTx) Commit() error {
+	if t.nested {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+func (t *scopedTx) Rollback() error {
+	if t.nested {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
@@ -12,6 +12,8 @@ import (
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *models.User) error
 	GetUserByEmail(ctx context.Context, email string) (models.User, error)
+	GetUserByID(ctx context.Context, id uint) (models.User, error)
+	UpdatePassword(ctx context.Context, id uint, passwordHash []byte) error
 }
 
 type PostgresUserRepository struct {
@@ -26,7 +28,7 @@ func NewPostgresUserRepository(db *sql.DB) *PostgresUserRepository {
 func (r *PostgresUserRepository) CreateUser(ctx context.Context, user *models.User) error {
 	query := `INSERT INTO users (username, email, password) VALUES ($1, $2, $3) RETURNING id, created_at`
 
-	err := r.db.QueryRowContext(ctx, query, user.Username, user.Email, user.Password).Scan(&user.ID, &user.CreatedAt)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, user.Username, user.Email, user.Password).Scan(&user.ID, &user.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("repository: failed to create a user: %w", err)
 	}
@@ -39,7 +41,7 @@ func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email strin
 
 	query := `SELECT id, username, email, password, created_at FROM users WHERE email = $1`
 
-	row := r.db.QueryRowContext(ctx, query, email)
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, email)
 
 	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.CreatedAt)
 	if err != nil {
@@ -48,3 +50,42 @@ func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email strin
 
 	return user, nil
 }
+
+func (r *PostgresUserRepository) GetUserByID(ctx context.Context, id uint) (models.User, error) {
+	var user models.User
+
+	query := `SELECT id, username, email, password, is_admin, created_at FROM users WHERE id = $1`
+
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, id)
+
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.IsAdmin, &user.CreatedAt)
+	if err != nil {
+		return models.User{}, fmt.Errorf("repository: failed to get user by id: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *PostgresUserRepository) UpdatePassword(ctx context.Context, id uint, passwordHash []byte) error {
+	query := `UPDATE users SET password = $1 WHERE id = $2`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, passwordHash, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to update password: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresUserRepository) IsAdmin(ctx context.Context, userID uint) (bool, error) {
+	var isAdmin bool
+
+	query := `SELECT is_admin FROM users WHERE id = $1`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(&isAdmin)
+	if err != nil {
+		return false, fmt.Errorf("repository: failed to check admin flag: %w", err)
+	}
+
+	return isAdmin, nil
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/lib/pq"
+)
+
+var ErrWebhookNotFound = errors.New("repository: webhook not found")
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	GetWebhookByID(ctx context.Context, id uint) (models.Webhook, error)
+	GetWebhooksByUserID(ctx context.Context, userID uint) ([]models.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) error
+	DeleteWebhook(ctx context.Context, id uint) error
+	GetWebhookDeliveries(ctx context.Context, webhookID uint, limit, offset int) ([]models.WebhookDelivery, error)
+	EnqueueWebhookDeliveries(ctx context.Context, userID uint, event models.WebhookEvent, payload json.RawMessage) (int64, error)
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.DueWebhookDelivery, error)
+	CompleteDelivery(ctx context.Context, id uint, statusCode int) error
+	FailDelivery(ctx context.Context, id uint, statusCode *int, reason string, retryAt *time.Time) error
+}
+
+type PostgresWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWebhookRepository(db *sql.DB) *PostgresWebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+const webhookColumns = `id, user_id, url, secret, events, active, created_at, updated_at`
+
+const deliveryColumns = `id, webhook_id, event, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at`
+
+func scanWebhook(row rowScanner) (models.Webhook, error) {
+	var w models.Webhook
+	var events []string
+	err := row.Scan(&w.ID, &w.UserID, &w.URL, &w.Secret, pq.Array(&events), &w.Active, &w.CreatedAt, &w.UpdatedAt)
+	for _, e := range events {
+		w.Events = append(w.Events, models.WebhookEvent(e))
+	}
+	return w, err
+}
+
+func deliveryFields(d *models.WebhookDelivery) []any {
+	return []any{&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt}
+}
+
+func eventNames(events []models.WebhookEvent) []string {
+	names := make([]string, 0, len(events))
+	for _, e := range events {
+		names = append(names, string(e))
+	}
+	return names
+}
+
+func (r *PostgresWebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	query := `INSERT INTO webhooks (user_id, url, secret, events, active) VALUES ($1, $2, $3, $4, $5)
+              RETURNING id, created_at, updated_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, webhook.UserID, webhook.URL, webhook.Secret, pq.Array(eventNames(webhook.Events)), webhook.Active).
+		Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookRepository) GetWebhookByID(ctx context.Context, id uint) (models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+
+	webhook, err := scanWebhook(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Webhook{}, ErrWebhookNotFound
+	}
+	if err != nil {
+		return models.Webhook{}, fmt.Errorf("repository: failed to get webhook by id: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (r *PostgresWebhookRepository) GetWebhooksByUserID(ctx context.Context, userID uint) ([]models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY created_at, id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
+
+func (r *PostgresWebhookRepository) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	query := `UPDATE webhooks SET url = $1, events = $2, active = $3, updated_at = NOW() WHERE id = $4 RETURNING updated_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, webhook.URL, pq.Array(eventNames(webhook.Events)), webhook.Active, webhook.ID).
+		Scan(&webhook.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrWebhookNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("repository: failed to update webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookRepository) DeleteWebhook(ctx context.Context, id uint) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to delete webhook: %w", err)
+	}
+
+	return expectAffected(res, ErrWebhookNotFound)
+}
+
+// GetWebhookDeliveries - журнал доставок вебхука, новые первыми
+func (r *PostgresWebhookRepository) GetWebhookDeliveries(ctx context.Context, webhookID uint, limit, offset int) ([]models.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries
+              WHERE webhook_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, webhookID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+
+	for rows.Next() {
+		var d models.WebhookDelivery
+		err := rows.Scan(deliveryFields(&d)...)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+// EnqueueWebhookDeliveries ставит событие в очередь на все активные вебхуки пользователя, подписанные на него
+func (r *PostgresWebhookRepository) EnqueueWebhookDeliveries(ctx context.Context, userID uint, event models.WebhookEvent, payload json.RawMessage) (int64, error) {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event, payload)
+              SELECT id, $2, $3 FROM webhooks WHERE user_id = $1 AND active AND $2 = ANY(events)`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, userID, event, []byte(payload))
+	if err != nil {
+		return 0, fmt.Errorf("repository: failed to enqueue webhook deliveries: %w", err)
+	}
+
+	return res.RowsAffected()
+}
+
+// ClaimDueDeliveries забирает до limit доставок, время которых пришло, и сдвигает им следующую попытку на lease.
+// Если обработчик упадёт, не отчитавшись, доставка повторится после lease. SKIP LOCKED позволяет
+// нескольким репликам разбирать очередь одновременно, не мешая друг другу
+func (r *PostgresWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.DueWebhookDelivery, error) {
+	query := `WITH due AS (
+                  SELECT d.id FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+                  WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.active
+                  ORDER BY d.next_attempt_at, d.id LIMIT $1
+                  FOR UPDATE OF d SKIP LOCKED
+              )
+              UPDATE webhook_deliveries d SET attempts = d.attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+              FROM due, webhooks w WHERE d.id = due.id AND w.id = d.webhook_id
+              RETURNING d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+                        d.last_status_code, d.last_error, d.created_at, d.delivered_at, w.url, w.secret`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.DueWebhookDelivery
+
+	for rows.Next() {
+		var d models.DueWebhookDelivery
+		err := rows.Scan(append(deliveryFields(&d.WebhookDelivery), &d.URL, &d.Secret)...)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+func (r *PostgresWebhookRepository) CompleteDelivery(ctx context.Context, id uint, statusCode int) error {
+	query := `UPDATE webhook_deliveries SET status = 'delivered', last_status_code = $1, last_error = NULL, delivered_at = NOW()
+              WHERE id = $2`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, statusCode, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to complete webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// FailDelivery записывает неудачную попытку. retryAt = nil означает, что попытки кончились
+func (r *PostgresWebhookRepository) FailDelivery(ctx context.Context, id uint, statusCode *int, reason string, retryAt *time.Time) error {
+	query := `UPDATE webhook_deliveries SET last_status_code = $1, last_error = $2,
+                  status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+                  next_attempt_at = COALESCE($3, next_attempt_at)
+              WHERE id = $4`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, statusCode, reason, retryAt, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to record webhook delivery failure: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrWorkspaceNotFound       = errors.New("repository: workspace not found")
+	ErrNotWorkspaceMember      = errors.New("repository: user is not a workspace member")
+	ErrInvitationNotFound      = errors.New("repository: workspace invitation not found")
+	ErrInvitationUsed          = errors.New("repository: workspace invitation already accepted")
+	ErrInvitationExpired       = errors.New("repository: workspace invitation expired")
+	ErrInvitationEmailMismatch = errors.New("repository: workspace invitation was sent to another email")
+)
+
+type WorkspaceRepository interface {
+	CreateWorkspace(ctx context.Context, workspace *models.Workspace) error
+	GetWorkspaceByID(ctx context.Context, id uint) (models.Workspace, error)
+	GetWorkspacesByUserID(ctx context.Context, userID uint) ([]models.UserWorkspace, error)
+	UpdateWorkspaceName(ctx context.Context, id uint, name string) error
+	DeleteWorkspace(ctx context.Context, id uint) error
+	GetMemberRole(ctx context.Context, workspaceID, userID uint) (models.WorkspaceRole, error)
+	GetMembers(ctx context.Context, workspaceID uint) ([]models.WorkspaceMember, error)
+	UpdateMemberRole(ctx context.Context, workspaceID, userID uint, role models.WorkspaceRole) error
+	RemoveMember(ctx context.Context, workspaceID, userID uint) error
+	CreateInvitation(ctx context.Context, invitation *models.WorkspaceInvitation) error
+	GetInvitations(ctx context.Context, workspaceID uint) ([]models.WorkspaceInvitation, error)
+	DeleteInvitation(ctx context.Context, workspaceID, id uint) error
+	AcceptInvitation(ctx context.Context, tokenHash string, userID uint) (models.WorkspaceMember, error)
+}
+
+type PostgresWorkspaceRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWorkspaceRepository(db *sql.DB) *PostgresWorkspaceRepository {
+	return &PostgresWorkspaceRepository{db: db}
+}
+
+const workspaceColumns = `id, name, owner_id, created_at, updated_at`
+
+const invitationColumns = `id, workspace_id, email, role, token_hash, invited_by, expires_at, accepted_at, created_at`
+
+func scanWorkspace(row rowScanner) (models.Workspace, error) {
+	var ws models.Workspace
+	err := row.Scan(&ws.ID, &ws.Name, &ws.OwnerID, &ws.CreatedAt, &ws.UpdatedAt)
+	return ws, err
+}
+
+func scanInvitation(row rowScanner) (models.WorkspaceInvitation, error) {
+	var inv models.WorkspaceInvitation
+	err := row.Scan(&inv.ID, &inv.WorkspaceID, &inv.Email, &inv.Role, &inv.TokenHash, &inv.InvitedBy, &inv.ExpiresAt, &inv.AcceptedAt, &inv.CreatedAt)
+	return inv, err
+}
+
+// CreateWorkspace создаёт пространство и сразу делает его создателя владельцем
+func (r *PostgresWorkspaceRepository) CreateWorkspace(ctx context.Context, workspace *models.Workspace) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("repository: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO workspaces (name, owner_id) VALUES ($1, $2) RETURNING id, created_at, updated_at`
+
+	err = tx.QueryRowContext(ctx, query, workspace.Name, workspace.OwnerID).
+		Scan(&workspace.ID, &workspace.CreatedAt, &workspace.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create workspace: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)`,
+		workspace.ID, workspace.OwnerID, models.WorkspaceRoleOwner)
+	if err != nil {
+		return fmt.Errorf("repository: failed to add workspace owner: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("repository: failed to commit workspace: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresWorkspaceRepository) GetWorkspaceByID(ctx context.Context, id uint) (models.Workspace, error) {
+	query := `SELECT ` + workspaceColumns + ` FROM workspaces WHERE id = $1`
+
+	ws, err := scanWorkspace(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Workspace{}, ErrWorkspaceNotFound
+	}
+	if err != nil {
+		return models.Workspace{}, fmt.Errorf("repository: failed to get workspace by id: %w", err)
+	}
+
+	return ws, nil
+}
+
+// GetWorkspacesByUserID возвращает пространства, в которых состоит пользователь, вместе с его ролью
+func (r *PostgresWorkspaceRepository) GetWorkspacesByUserID(ctx context.Context, userID uint) ([]models.UserWorkspace, error) {
+	query := `SELECT w.id, w.name, w.owner_id, w.created_at, w.updated_at, m.role
+              FROM workspaces w JOIN workspace_members m ON m.workspace_id = w.id
+              WHERE m.user_id = $1 ORDER BY w.name, w.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get workspaces by user id: %w", err)
+	}
+	defer rows.Close()
+
+	workspaces := []models.UserWorkspace{}
+
+	for rows.Next() {
+		var uw models.UserWorkspace
+		ws := &uw.Workspace
+		err := rows.Scan(&ws.ID, &ws.Name, &ws.OwnerID, &ws.CreatedAt, &ws.UpdatedAt, &uw.Role)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, uw)
+	}
+	return workspaces, nil
+}
+
+func (r *PostgresWorkspaceRepository) UpdateWorkspaceName(ctx context.Context, id uint, name string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE workspaces SET name = $1, updated_at = NOW() WHERE id = $2`, name, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to update workspace name: %w", err)
+	}
+
+	return expectAffected(res, ErrWorkspaceNotFound)
+}
+
+// DeleteWorkspace удаляет пространство. Его задачи остаются у авторов как личные
+func (r *PostgresWorkspaceRepository) DeleteWorkspace(ctx context.Context, id uint) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM workspaces WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("repository: failed to delete workspace: %w", err)
+	}
+
+	return expectAffected(res, ErrWorkspaceNotFound)
+}
+
+func (r *PostgresWorkspaceRepository) GetMemberRole(ctx context.Context, workspaceID, userID uint) (models.WorkspaceRole, error) {
+	return workspaceRole(ctx, conn(ctx, r.db), workspaceID, userID)
+}
+
+func (r *PostgresWorkspaceRepository) GetMembers(ctx context.Context, workspaceID uint) ([]models.WorkspaceMember, error) {
+	query := `SELECT m.workspace_id, m.user_id, u.username, m.role, m.joined_at
+              FROM workspace_members m JOIN users u ON u.id = m.user_id
+              WHERE m.workspace_id = $1 ORDER BY m.joined_at, m.user_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get workspace members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []models.WorkspaceMember{}
+
+	for rows.Next() {
+		var m models.WorkspaceMember
+		err := rows.Scan(&m.WorkspaceID, &m.UserID, &m.Username, &m.Role, &m.JoinedAt)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan workspace member: %w", err)
+		}
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+func (r *PostgresWorkspaceRepository) UpdateMemberRole(ctx context.Context, workspaceID, userID uint, role models.WorkspaceRole) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE workspace_members SET role = $1 WHERE workspace_id = $2 AND user_id = $3`,
+		role, workspaceID, userID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to update member role: %w", err)
+	}
+
+	return expectAffected(res, ErrNotWorkspaceMember)
+}
+
+// RemoveMember исключает участника. Его задачи остаются в пространстве
+func (r *PostgresWorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID uint) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`, workspaceID, userID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to remove workspace member: %w", err)
+	}
+
+	return expectAffected(res, ErrNotWorkspaceMember)
+}
+
+func (r *PostgresWorkspaceRepository) CreateInvitation(ctx context.Context, invitation *models.WorkspaceInvitation) error {
+	query := `INSERT INTO workspace_invitations (workspace_id, email, role, token_hash, invited_by, expires_at)
+              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		invitation.WorkspaceID,
+		invitation.Email,
+		invitation.Role,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.ExpiresAt,
+	).Scan(&invitation.ID, &invitation.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: failed to create workspace invitation: %w", err)
+	}
+
+	return nil
+}
+
+// GetInvitations возвращает ещё не принятые приглашения, включая просроченные
+func (r *PostgresWorkspaceRepository) GetInvitations(ctx context.Context, workspaceID uint) ([]models.WorkspaceInvitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM workspace_invitations
+              WHERE workspace_id = $1 AND accepted_at IS NULL ORDER BY created_at, id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get workspace invitations: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := []models.WorkspaceInvitation{}
+
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository: failed to scan workspace invitation: %w", err)
+		}
+		invitations = append(invitations, inv)
+	}
+	return invitations, nil
+}
+
+func (r *PostgresWorkspaceRepository) DeleteInvitation(ctx context.Context, workspaceID, id uint) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM workspace_invitations WHERE id = $1 AND workspace_id = $2 AND accepted_at IS NULL`,
+		id, workspaceID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to delete workspace invitation: %w", err)
+	}
+
+	return expectAffected(res, ErrInvitationNotFound)
+}
+
+// AcceptInvitation добавляет пользователя в пространство по токену приглашения. Приглашение
+// одноразовое и действует только для пользователя с той почтой, на которую оно выписано.
+// Если пользователь уже состоит в пространстве, его роль не меняется
+func (r *PostgresWorkspaceRepository) AcceptInvitation(ctx context.Context, tokenHash string, userID uint) (models.WorkspaceMember, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return models.WorkspaceMember{}, fmt.Errorf("repository: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + invitationColumns + ` FROM workspace_invitations WHERE token_hash = $1 FOR UPDATE`
+
+	inv, err := scanInvitation(tx.QueryRowContext(ctx, query, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.WorkspaceMember{}, ErrInvitationNotFound
+	}
+	if err != nil {
+		return models.WorkspaceMember{}, fmt.Errorf("repository: failed to get workspace invitation: %w", err)
+	}
+	if inv.AcceptedAt != nil {
+		return models.WorkspaceMember{}, ErrInvitationUsed
+	}
+	if !inv.ExpiresAt.After(time.Now()) {
+		return models.WorkspaceMember{}, ErrInvitationExpired
+	}
+
+	member := models.WorkspaceMember{WorkspaceID: inv.WorkspaceID, UserID: userID}
+	var email string
+	err = tx.QueryRowContext(ctx, `SELECT username, email FROM users WHERE id = $1`, userID).Scan(&member.Username, &email)
+	if err != nil {
+		return models.WorkspaceMember{}, fmt.Errorf("repository: failed to get invited user: %w", err)
+	}
+	if !strings.EqualFold(email, inv.Email) {
+		return models.WorkspaceMember{}, ErrInvitationEmailMismatch
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)
+                                  ON CONFLICT (workspace_id, user_id) DO NOTHING`, inv.WorkspaceID, userID, inv.Role)
+	if err != nil {
+		return models.WorkspaceMember{}, fmt.Errorf("repository: failed to add workspace member: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx, `SELECT role, joined_at FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`,
+		inv.WorkspaceID, userID).Scan(&member.Role, &member.JoinedAt)
+	if err != nil {
+		return models.WorkspaceMember{}, fmt.Errorf("repository: failed to get workspace member: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE workspace_invitations SET accepted_at = NOW() WHERE id = $1`, inv.ID)
+	if err != nil {
+		return models.WorkspaceMember{}, fmt.Errorf("repository: failed to mark invitation accepted: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.WorkspaceMember{}, fmt.Errorf("repository: failed to commit invitation: %w", err)
+	}
+
+	return member, nil
+}
+
+// GetWorkspaceRole возвращает роль пользователя в пространстве или ErrNotWorkspaceMember
+func (r *PostgresTaskRepository) GetWorkspaceRole(ctx context.Context, workspaceID, userID uint) (models.WorkspaceRole, error) {
+	return workspaceRole(ctx, conn(ctx, r.db), workspaceID, userID)
+}
+
+// UpdateTaskWorkspace переносит задачу вместе с подзадачами в пространство или, при nil, в личные задачи
+func (r *PostgresTaskRepository) UpdateTaskWorkspace(ctx context.Context, id uint, workspaceID *uint) error {
+	query := taskSubtreeCTE + `UPDATE tasks SET workspace_id = $2, updated_at = NOW() WHERE id IN (SELECT id FROM subtree)`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, pq.Array([]int64{int64(id)}), workspaceID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to update task workspace: %w", err)
+	}
+
+	return nil
+}
+
+func workspaceRole(ctx context.Context, db querier, workspaceID, userID uint) (models.WorkspaceRole, error) {
+	var role models.WorkspaceRole
+
+	err := db.QueryRowContext(ctx, `SELECT role FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`, workspaceID, userID).
+		Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotWorkspaceMember
+	}
+	if err != nil {
+		return "", fmt.Errorf("repository: failed to get workspace role: %w", err)
+	}
+
+	return role, nil
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"to-do-list/internal/models"
+)
+
+type CompletedTaskArchiver interface {
+	ArchiveCompletedBefore(ctx context.Context, cutoff time.Time) ([]models.Task, error)
+}
+
+// NewAutoArchiveJob архивирует задачи, завершённые больше чем after назад
+func NewAutoArchiveJob(repo CompletedTaskArchiver, interval, after time.Duration) Job {
+	return Job{
+		Name:     "auto-archive",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			archived, err := repo.ArchiveCompletedBefore(ctx, time.Now().Add(-after))
+			if err != nil {
+				return err
+			}
+
+			if len(archived) > 0 {
+				slog.Info("completed tasks archived", slog.Int("count", len(archived)))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeArchiver struct {
+	cutoff time.Time
+}
+
+func (f *fakeArchiver) ArchiveCompletedBefore(ctx context.Context, cutoff time.Time) ([]models.Task, error) {
+	f.cutoff = cutoff
+	return make([]models.Task, 1), nil
+}
+
+func TestAutoArchiveJob_UsesDelay(t *testing.T) {
+	archiver := &fakeArchiver{}
+	job := NewAutoArchiveJob(archiver, time.Hour, 7*24*time.Hour)
+
+	before := time.Now()
+	err := job.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.WithinDuration(t, before.Add(-7*24*time.Hour), archiver.cutoff, time.Second)
+}
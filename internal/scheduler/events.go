@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type EventDispatcher interface {
+	Dispatch(ctx context.Context) (int, error)
+}
+
+// NewEventDispatchJob раздаёт подписчикам события из outbox, по одной пачке за проход
+func NewEventDispatchJob(dispatcher EventDispatcher, interval time.Duration) Job {
+	return Job{
+		Name:     "event-dispatch",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			processed, err := dispatcher.Dispatch(ctx)
+			if err != nil {
+				return err
+			}
+
+			if processed > 0 {
+				slog.Debug("outbox events dispatched", slog.Int("count", processed))
+			}
+			return nil
+		},
+	}
+}
+
+type OutboxCleaner interface {
+	DeleteProcessedEvents(ctx context.Context, before time.Time) (int64, error)
+}
+
+// NewOutboxCleanupJob удаляет обработанные события старше retention
+func NewOutboxCleanupJob(repo OutboxCleaner, interval, retention time.Duration) Job {
+	return Job{
+		Name:     "outbox-cleanup",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			deleted, err := repo.DeleteProcessedEvents(ctx, time.Now().Add(-retention))
+			if err != nil {
+				return err
+			}
+
+			if deleted > 0 {
+				slog.Info("processed outbox events deleted", slog.Int64("count", deleted))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEventDispatcher struct {
+	err error
+}
+
+func (f *fakeEventDispatcher) Dispatch(ctx context.Context) (int, error) {
+	return 0, f.err
+}
+
+type fakeOutboxCleaner struct {
+	before time.Time
+}
+
+func (f *fakeOutboxCleaner) DeleteProcessedEvents(ctx context.Context, before time.Time) (int64, error) {
+	f.before = before
+	return 3, nil
+}
+
+func TestEventDispatchJob_PropagatesError(t *testing.T) {
+	job := NewEventDispatchJob(&fakeEventDispatcher{err: errors.New("db is down")}, time.Second)
+
+	assert.Error(t, job.Run(context.Background()))
+}
+
+func TestOutboxCleanupJob_AppliesRetention(t *testing.T) {
+	cleaner := &fakeOutboxCleaner{}
+	job := NewOutboxCleanupJob(cleaner, time.Hour, 7*24*time.Hour)
+
+	before := time.Now()
+	err := job.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.WithinDuration(t, before.Add(-7*24*time.Hour), cleaner.before, time.Second)
+}
@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"to-do-list/internal/models"
+	"to-do-list/internal/recurrence"
+)
+
+type OverdueTaskMarker interface {
+	MarkOverdueTasksFailed(ctx context.Context, cutoff time.Time, spawn func(ctx context.Context, task models.Task) error) ([]models.Task, error)
+}
+
+// NewOverdueJob переводит в failed задачи, дедлайн которых прошёл больше чем grace назад,
+// и в той же транзакции создаёт следующие экземпляры для повторяющихся задач. Если экземпляр
+// не создался, статусы тоже не меняются, и пачка повторяется на следующем запуске
+func NewOverdueJob(repo OverdueTaskMarker, series recurrence.Store, interval, grace time.Duration) Job {
+	return Job{
+		Name:     "overdue-tasks",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			spawn := func(ctx context.Context, task models.Task) error {
+				_, err := recurrence.SpawnNext(ctx, series, task, time.Now())
+				return err
+			}
+
+			tasks, err := repo.MarkOverdueTasksFailed(ctx, time.Now().Add(-grace), spawn)
+			if err != nil {
+				return err
+			}
+
+			if len(tasks) > 0 {
+				slog.Info("overdue tasks marked as failed", slog.Int("count", len(tasks)))
+			}
+			return nil
+		},
+	}
+}
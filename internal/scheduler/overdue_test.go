@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOverdueMarker ведёт себя как транзакция: если spawn упал, статусы не меняются
+type fakeOverdueMarker struct {
+	cutoff time.Time
+	tasks  []models.Task
+	failed []models.Task
+}
+
+func (f *fakeOverdueMarker) MarkOverdueTasksFailed(ctx context.Context, cutoff time.Time, spawn func(ctx context.Context, task models.Task) error) ([]models.Task, error) {
+	f.cutoff = cutoff
+	tasks := f.tasks
+	if tasks == nil {
+		tasks = []models.Task{{ID: 1, Status: models.StatusFailed}}
+	}
+
+	for _, task := range tasks {
+		err := spawn(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f.failed = tasks
+	return tasks, nil
+}
+
+type fakeSeriesStore struct {
+	created []models.Task
+	err     error
+}
+
+func (f *fakeSeriesStore) GetSeriesByID(ctx context.Context, id uint) (models.TaskSeries, error) {
+	return models.TaskSeries{ID: id, Rule: "FREQ=DAILY", Active: true}, nil
+}
+
+func (f *fakeSeriesStore) CreateOccurrence(ctx context.Context, task *models.Task) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	f.created = append(f.created, *task)
+	return true, nil
+}
+
+func TestOverdueJob_AppliesGracePeriod(t *testing.T) {
+	marker := &fakeOverdueMarker{}
+	job := NewOverdueJob(marker, &fakeSeriesStore{}, time.Minute, time.Hour)
+
+	before := time.Now()
+	err := job.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.WithinDuration(t, before.Add(-time.Hour), marker.cutoff, time.Second)
+}
+
+func TestOverdueJob_SpawnsNextOccurrence(t *testing.T) {
+	seriesID := uint(3)
+	deadline := time.Now().Add(-time.Hour)
+	marker := &fakeOverdueMarker{tasks: []models.Task{
+		{ID: 1, Status: models.StatusFailed, Deadline: deadline},
+		{ID: 2, Status: models.StatusFailed, Deadline: deadline, SeriesID: &seriesID, Occurrence: 1},
+	}}
+	store := &fakeSeriesStore{}
+	job := NewOverdueJob(marker, store, time.Minute, 0)
+
+	err := job.Run(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, store.created, 1)
+	assert.Equal(t, seriesID, *store.created[0].SeriesID)
+	assert.Equal(t, deadline.AddDate(0, 0, 1), store.created[0].Deadline)
+}
+
+func TestOverdueJob_SpawnFailureRollsBack(t *testing.T) {
+	seriesID := uint(3)
+	marker := &fakeOverdueMarker{tasks: []models.Task{
+		{ID: 2, Status: models.StatusFailed, Deadline: time.Now().Add(-time.Hour), SeriesID: &seriesID, Occurrence: 1},
+	}}
+	store := &fakeSeriesStore{err: errors.New("connection reset")}
+	job := NewOverdueJob(marker, store, time.Minute, 0)
+
+	err := job.Run(context.Background())
+	assert.ErrorContains(t, err, "connection reset")
+
+	assert.Empty(t, marker.failed)
+	assert.Empty(t, store.created)
+}
@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"to-do-list/internal/models"
+)
+
+type DueReminderClaimer interface {
+	ClaimDueReminders(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, n models.Notification) error
+}
+
+// NewReminderJob рассылает напоминания о приближающихся дедлайнах, по одной пачке за проход.
+// Напоминание отмечается отправленным до рассылки, поэтому при сбое канала оно теряется, а не дублируется
+func NewReminderJob(repo DueReminderClaimer, notifier Notifier, interval time.Duration, batchSize int) Job {
+	return Job{
+		Name:     "deadline-reminders",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			now := time.Now()
+
+			reminders, err := repo.ClaimDueReminders(ctx, now, batchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, reminder := range reminders {
+				err := notifier.Notify(ctx, reminderNotification(reminder, now))
+				if err != nil {
+					slog.Error("Failed to send deadline reminder", slog.Any("taskID", reminder.TaskID), slog.Any("error", err))
+				}
+			}
+
+			if len(reminders) > 0 {
+				slog.Info("deadline reminders sent", slog.Int("count", len(reminders)))
+			}
+			return nil
+		},
+	}
+}
+
+func reminderNotification(reminder models.DueReminder, now time.Time) models.Notification {
+	// Пишем, сколько осталось на самом деле: напоминание могло сработать позже своего срока
+	left := max(reminder.Deadline.Sub(now).Truncate(time.Minute), time.Minute)
+
+	return models.Notification{
+		UserID:    reminder.UserID,
+		Kind:      models.NotificationTaskReminder,
+		TaskID:    &reminder.TaskID,
+		Title:     "Deadline is approaching",
+		Body:      fmt.Sprintf("%q is due in %s", reminder.TaskName, models.ReminderOffset(left)),
+		Channels:  reminder.Channels,
+		CreatedAt: now,
+	}
+}
@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReminderClaimer struct {
+	limit     int
+	reminders []models.DueReminder
+}
+
+func (f *fakeReminderClaimer) ClaimDueReminders(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error) {
+	f.limit = limit
+	reminders := f.reminders
+	f.reminders = nil
+	return reminders, nil
+}
+
+type fakeNotifier struct {
+	sent []models.Notification
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, n models.Notification) error {
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+func TestReminderJob_NotifiesRecipients(t *testing.T) {
+	claimer := &fakeReminderClaimer{reminders: []models.DueReminder{{
+		TaskID:   3,
+		UserID:   20,
+		TaskName: "Report",
+		Offset:   models.ReminderOffset(time.Hour),
+		Deadline: time.Now().Add(2*time.Hour + 30*time.Second),
+		Channels: []models.NotificationChannelName{models.ChannelEmail},
+	}}}
+	notifier := &fakeNotifier{}
+	job := NewReminderJob(claimer, notifier, time.Minute, 50)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	assert.Equal(t, 50, claimer.limit)
+	require.Len(t, notifier.sent, 1)
+	n := notifier.sent[0]
+	assert.Equal(t, uint(20), n.UserID)
+	assert.Equal(t, models.NotificationTaskReminder, n.Kind)
+	assert.Equal(t, uint(3), *n.TaskID)
+	assert.Equal(t, `"Report" is due in 2h`, n.Body)
+	assert.Equal(t, []models.NotificationChannelName{models.ChannelEmail}, n.Channels)
+
+	// Второй проход ничего не находит: повтор отсекает репозиторий
+	require.NoError(t, job.Run(context.Background()))
+	assert.Len(t, notifier.sent, 1)
+}
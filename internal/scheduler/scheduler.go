@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job - периодическая фоновая задача. Run не должен блокироваться дольше, чем нужно на один проход
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler запускает каждую задачу в своей горутине и останавливает их при отмене контекста
+type Scheduler struct {
+	jobs []Job
+	wg   sync.WaitGroup
+	log  *slog.Logger
+}
+
+func New(log *slog.Logger) *Scheduler {
+	return &Scheduler{
+		log: log.With(slog.String("component", "scheduler")),
+	}
+}
+
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Wait дожидается завершения всех задач после отмены контекста, переданного в Start
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	log := s.log.With(slog.String("job", job.Name))
+	log.Info("job started", slog.String("interval", job.Interval.String()))
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("job stopped")
+			return
+		case <-ticker.C:
+			err := job.Run(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Error("job run failed", slog.Any("error", err))
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"to-do-list/internal/models"
+)
+
+type TrashPurger interface {
+	PurgeDeletedTasks(ctx context.Context, cutoff time.Time) ([]models.Task, error)
+}
+
+// NewTrashPurgeJob окончательно удаляет задачи, пролежавшие в корзине дольше retention
+func NewTrashPurgeJob(repo TrashPurger, interval, retention time.Duration) Job {
+	return Job{
+		Name:     "trash-purge",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			purged, err := repo.PurgeDeletedTasks(ctx, time.Now().Add(-retention))
+			if err != nil {
+				return err
+			}
+
+			if len(purged) > 0 {
+				slog.Info("trashed tasks purged", slog.Int("count", len(purged)))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTrashPurger struct {
+	cutoff time.Time
+}
+
+func (f *fakeTrashPurger) PurgeDeletedTasks(ctx context.Context, cutoff time.Time) ([]models.Task, error) {
+	f.cutoff = cutoff
+	return make([]models.Task, 2), nil
+}
+
+func TestTrashPurgeJob_AppliesRetention(t *testing.T) {
+	purger := &fakeTrashPurger{}
+	job := NewTrashPurgeJob(purger, time.Hour, 30*24*time.Hour)
+
+	before := time.Now()
+	err := job.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.WithinDuration(t, before.Add(-30*24*time.Hour), purger.cutoff, time.Second)
+}
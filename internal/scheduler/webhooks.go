@@ -0,0 +1,30 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type WebhookDeliverer interface {
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+// NewWebhookDeliveryJob отправляет накопившиеся доставки вебхуков, по одной пачке за проход
+func NewWebhookDeliveryJob(deliverer WebhookDeliverer, interval time.Duration) Job {
+	return Job{
+		Name:     "webhook-delivery",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			delivered, err := deliverer.DeliverDue(ctx)
+			if err != nil {
+				return err
+			}
+
+			if delivered > 0 {
+				slog.Info("webhooks delivered", slog.Int("count", delivered))
+			}
+			return nil
+		},
+	}
+}
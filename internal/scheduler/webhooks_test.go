@@ -0,0 +1,30 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWebhookDeliverer struct {
+	calls int
+	err   error
+}
+
+func (f *fakeWebhookDeliverer) DeliverDue(ctx context.Context) (int, error) {
+	f.calls++
+	return 1, f.err
+}
+
+func TestWebhookDeliveryJob_PropagatesError(t *testing.T) {
+	deliverer := &fakeWebhookDeliverer{err: errors.New("db is down")}
+	job := NewWebhookDeliveryJob(deliverer, time.Second)
+
+	err := job.Run(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, deliverer.calls)
+}
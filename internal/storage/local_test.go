@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	key, err := NewKey("tasks/1")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, key, strings.NewReader("hello"), 5, "text/plain"))
+
+	rc, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	require.NoError(t, store.Delete(ctx, key))
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, key), ErrBlobNotFound)
+}
+
+func TestLocalStore_SizeMismatch(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	require.Error(t, store.Put(ctx, "tasks/1/short", strings.NewReader("abc"), 10, ""))
+	_, err = store.Get(ctx, "tasks/1/short")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func TestValidKey(t *testing.T) {
+	for _, key := range []string{"tasks/1/abc", "a"} {
+		assert.True(t, validKey(key), key)
+	}
+	for _, key := range []string{"", "/etc/passwd", "../x", "tasks/../../x", "tasks//x", "a\\b", "tasks/."} {
+		assert.False(t, validKey(key), key)
+	}
+}
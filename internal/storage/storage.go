@@ -0,0 +1,47 @@
+// Package storage хранит содержимое вложений отдельно от базы. Метаданные живут в Postgres,
+// здесь только байты по ключу
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	ErrBlobNotFound = errors.New("storage: blob not found")
+	ErrInvalidKey   = errors.New("storage: invalid key")
+)
+
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewKey возвращает случайный ключ вида prefix/<32 hex>. Имя файла от клиента в ключ не попадает
+func NewKey(prefix string) (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("storage: failed to generate key: %w", err)
+	}
+	return prefix + "/" + hex.EncodeToString(b), nil
+}
+
+// validKey отсекает ключи, которые вышли бы за пределы корня хранилища
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
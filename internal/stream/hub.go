@@ -0,0 +1,160 @@
+package stream
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+	"to-do-list/internal/models"
+)
+
+var ErrHubClosed = errors.New("stream: hub is closed")
+
+// Event - изменение задачи для потока. У task.deleted Task - задача до удаления
+type Event struct {
+	ID         uint
+	Type       models.EventType
+	Task       models.Task
+	OccurredAt time.Time
+}
+
+// FromDomainEvent превращает доменное событие в событие потока и возвращает, кому его показать:
+// владельцу задачи, исполнителю и прежнему исполнителю, если его сняли. ok = false для событий,
+// которые в поток не попадают
+func FromDomainEvent(e models.DomainEvent) (event Event, recipients []uint, ok bool) {
+	switch e.Type {
+	case models.EventTaskCreated, models.EventTaskUpdated, models.EventTaskDeleted:
+	default:
+		return Event{}, nil, false
+	}
+
+	payload, err := e.TaskPayload()
+	if err != nil {
+		slog.Error("Failed to decode task event payload", slog.Any("eventID", e.ID), slog.Any("error", err))
+		return Event{}, nil, false
+	}
+
+	task := payload.After
+	if task == nil {
+		task = payload.Before
+	}
+	if task == nil {
+		return Event{}, nil, false
+	}
+
+	recipients = []uint{task.UserID}
+	for _, t := range []*models.Task{payload.Before, payload.After} {
+		if t != nil && t.AssigneeID != nil && !slices.Contains(recipients, *t.AssigneeID) {
+			recipients = append(recipients, *t.AssigneeID)
+		}
+	}
+
+	return Event{ID: e.ID, Type: e.Type, Task: *task, OccurredAt: e.CreatedAt}, recipients, true
+}
+
+// Subscription - подписка одного соединения. Done закрывается, когда хаб отключил подписчика:
+// тот не успевал забирать события или сервер останавливается
+type Subscription struct {
+	userID uint
+	events chan Event
+	done   chan struct{}
+}
+
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Hub раздаёт события задач открытым соединениям их пользователей. Publish никогда не ждёт
+// медленного клиента: если буфер подписки полон, подписка закрывается, и клиент переподключается
+// с Last-Event-ID, догоняя пропущенное из outbox
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[uint]map[*Subscription]struct{}
+	buffer int
+	closed bool
+}
+
+func NewHub(buffer int) *Hub {
+	return &Hub{subs: make(map[uint]map[*Subscription]struct{}), buffer: buffer}
+}
+
+func (h *Hub) Subscribe(userID uint) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+
+	sub := &Subscription{userID: userID, events: make(chan Event, h.buffer), done: make(chan struct{})}
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*Subscription]struct{})
+	}
+	h.subs[userID][sub] = struct{}{}
+	return sub, nil
+}
+
+// Unsubscribe можно вызывать и для уже отключённой хабом подписки
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub)
+}
+
+// Publish раздаёт доменное событие подписчикам; события, которые в поток не попадают, пропускаются
+func (h *Hub) Publish(e models.DomainEvent) {
+	event, recipients, ok := FromDomainEvent(e)
+	if !ok {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, userID := range recipients {
+		for sub := range h.subs[userID] {
+			select {
+			case sub.events <- event:
+			default:
+				slog.Warn("Stream subscriber is lagging, disconnecting", slog.Any("userID", userID))
+				h.remove(sub)
+			}
+		}
+	}
+}
+
+// Close отключает всех подписчиков, новые подписки после него не принимаются
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, subs := range h.subs {
+		for sub := range subs {
+			h.remove(sub)
+		}
+	}
+}
+
+// remove вызывается под h.mu. Канал событий не закрывается, чтобы Publish не писал в закрытый
+func (h *Hub) remove(sub *Subscription) {
+	subs, ok := h.subs[sub.userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subs, sub.userID)
+	}
+	close(sub.done)
+}
@@ -0,0 +1,255 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"to-do-list/internal/models"
+)
+
+var (
+	ErrTooManyConnections = errors.New("stream: too many connections")
+	ErrTooManyTopics      = errors.New("stream: too many subscriptions")
+	ErrLagging            = errors.New("stream: client is not keeping up")
+	ErrInvalidTopic       = errors.New("stream: topic must look like task:12 or project:3")
+)
+
+// Topic - на что подписывается соединение WebSocket: на задачу (task:12) или на все задачи проекта (project:3)
+type Topic string
+
+func TaskTopic(id uint) Topic {
+	return Topic(fmt.Sprintf("task:%d", id))
+}
+
+func ProjectTopic(id uint) Topic {
+	return Topic(fmt.Sprintf("project:%d", id))
+}
+
+// ParseTopic разбирает тему на вид ("task" или "project") и id
+func ParseTopic(s string) (kind string, id uint, err error) {
+	kind, value, ok := strings.Cut(s, ":")
+	if !ok || (kind != "task" && kind != "project") {
+		return "", 0, ErrInvalidTopic
+	}
+
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || n == 0 {
+		return "", 0, ErrInvalidTopic
+	}
+	return kind, uint(n), nil
+}
+
+// eventTopics - темы, которых касается изменение задачи: сама задача и проекты до и после изменения
+func eventTopics(e models.DomainEvent) (Event, []Topic, bool) {
+	event, _, ok := FromDomainEvent(e)
+	if !ok {
+		return Event{}, nil, false
+	}
+
+	topics := []Topic{TaskTopic(event.Task.ID)}
+
+	payload, _ := e.TaskPayload()
+	for _, t := range []*models.Task{payload.Before, payload.After} {
+		if t != nil && t.ProjectID != nil {
+			topic := ProjectTopic(*t.ProjectID)
+			if !slices.Contains(topics, topic) {
+				topics = append(topics, topic)
+			}
+		}
+	}
+
+	return event, topics, true
+}
+
+// TopicEvent - событие для соединения; Topic - тема, по которой оно пришло
+type TopicEvent struct {
+	Topic Topic
+	Event Event
+}
+
+// Member - одно соединение WebSocket в Rooms. Done закрывается, когда Rooms его отключили, причина - в Err
+type Member struct {
+	userID uint
+	events chan TopicEvent
+	done   chan struct{}
+	topics map[Topic]struct{}
+	err    error
+}
+
+func (m *Member) Events() <-chan TopicEvent {
+	return m.events
+}
+
+func (m *Member) Done() <-chan struct{} {
+	return m.done
+}
+
+// Err - причина отключения: ErrLagging или ErrHubClosed. Читать только после закрытия Done
+func (m *Member) Err() error {
+	return m.err
+}
+
+// RoomsConfig: MaxConnsPerUser и MaxTopics - ограничения на пользователя и на одно соединение,
+// Buffer - сколько событий ждут медленное соединение, прежде чем его отключить
+type RoomsConfig struct {
+	MaxConnsPerUser int
+	MaxTopics       int
+	Buffer          int
+}
+
+// Rooms раздаёт изменения задач соединениям WebSocket по темам, на которые они подписаны.
+// Как и Hub, никогда не ждёт медленного клиента. Права на тему проверяет вызывающий при Join
+type Rooms struct {
+	mu      sync.Mutex
+	cfg     RoomsConfig
+	members map[Topic]map[*Member]struct{}
+	users   map[uint]map[*Member]struct{}
+	closed  bool
+}
+
+func NewRooms(cfg RoomsConfig) *Rooms {
+	return &Rooms{
+		cfg:     cfg,
+		members: make(map[Topic]map[*Member]struct{}),
+		users:   make(map[uint]map[*Member]struct{}),
+	}
+}
+
+// Connect регистрирует соединение пользователя, если он не превысил лимит соединений
+func (r *Rooms) Connect(userID uint) (*Member, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil, ErrHubClosed
+	}
+	if len(r.users[userID]) >= r.cfg.MaxConnsPerUser {
+		return nil, ErrTooManyConnections
+	}
+
+	m := &Member{
+		userID: userID,
+		events: make(chan TopicEvent, r.cfg.Buffer),
+		done:   make(chan struct{}),
+		topics: make(map[Topic]struct{}),
+	}
+	if r.users[userID] == nil {
+		r.users[userID] = make(map[*Member]struct{})
+	}
+	r.users[userID][m] = struct{}{}
+	return m, nil
+}
+
+// Disconnect можно вызывать и для уже отключённого соединения
+func (r *Rooms) Disconnect(m *Member) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(m, nil)
+}
+
+// Join подписывает соединение на тему. Повторная подписка ничего не меняет
+func (r *Rooms) Join(m *Member, topic Topic) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[m.userID][m]; !ok {
+		return ErrHubClosed
+	}
+	if _, ok := m.topics[topic]; ok {
+		return nil
+	}
+	if len(m.topics) >= r.cfg.MaxTopics {
+		return ErrTooManyTopics
+	}
+
+	m.topics[topic] = struct{}{}
+	if r.members[topic] == nil {
+		r.members[topic] = make(map[*Member]struct{})
+	}
+	r.members[topic][m] = struct{}{}
+	return nil
+}
+
+func (r *Rooms) Leave(m *Member, topic Topic) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.leave(m, topic)
+}
+
+// Publish раздаёт событие подписчикам его тем. Соединение, подписанное и на задачу, и на её проект,
+// получит событие один раз
+func (r *Rooms) Publish(e models.DomainEvent) {
+	event, topics, ok := eventTopics(e)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivered := make(map[*Member]struct{})
+	for _, topic := range topics {
+		for m := range r.members[topic] {
+			if _, ok := delivered[m]; ok {
+				continue
+			}
+			delivered[m] = struct{}{}
+
+			select {
+			case m.events <- TopicEvent{Topic: topic, Event: event}:
+			default:
+				slog.Warn("WebSocket client is lagging, disconnecting", slog.Any("userID", m.userID))
+				r.remove(m, ErrLagging)
+			}
+		}
+	}
+}
+
+// Close отключает все соединения, новые после него не принимаются
+func (r *Rooms) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	for _, members := range r.users {
+		for m := range members {
+			r.remove(m, ErrHubClosed)
+		}
+	}
+}
+
+// leave и remove вызываются под r.mu
+func (r *Rooms) leave(m *Member, topic Topic) {
+	delete(m.topics, topic)
+	delete(r.members[topic], m)
+	if len(r.members[topic]) == 0 {
+		delete(r.members, topic)
+	}
+}
+
+func (r *Rooms) remove(m *Member, reason error) {
+	members, ok := r.users[m.userID]
+	if !ok {
+		return
+	}
+	if _, ok := members[m]; !ok {
+		return
+	}
+
+	for topic := range m.topics {
+		r.leave(m, topic)
+	}
+	delete(members, m)
+	if len(members) == 0 {
+		delete(r.users, m.userID)
+	}
+
+	m.err = reason
+	close(m.done)
+}
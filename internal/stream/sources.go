@@ -0,0 +1,193 @@
+package stream
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"to-do-list/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// NotifyChannel - канал Postgres, в который триггер outbox_events шлёт id каждого нового события
+const NotifyChannel = "outbox_events"
+
+// Publisher раздаёт события outbox открытым соединениям: Hub - потокам SSE, Rooms - WebSocket
+type Publisher interface {
+	Publish(event models.DomainEvent)
+}
+
+// Publishers передаёт событие каждому из получателей
+type Publishers []Publisher
+
+func (p Publishers) Publish(event models.DomainEvent) {
+	for _, pub := range p {
+		pub.Publish(event)
+	}
+}
+
+// Subscriber публикует события из диспетчера outbox. Диспетчер разбирает событие на одной
+// из реплик, поэтому так поток работает только при одной реплике; для нескольких нужен Listen
+type Subscriber struct {
+	pub Publisher
+}
+
+func NewSubscriber(pub Publisher) *Subscriber {
+	return &Subscriber{pub: pub}
+}
+
+func (s *Subscriber) Name() string {
+	return "stream"
+}
+
+func (s *Subscriber) Handle(ctx context.Context, event models.DomainEvent) error {
+	s.pub.Publish(event)
+	return nil
+}
+
+// EventLoader читает события outbox по id, которые приходят через LISTEN/NOTIFY
+type EventLoader interface {
+	GetEvent(ctx context.Context, id uint) (models.DomainEvent, error)
+	GetEventsAfter(ctx context.Context, afterID uint, limit int) ([]models.DomainEvent, error)
+}
+
+// NotificationSource - источник уведомлений Postgres, обычно *pq.Listener, подписанный на NotifyChannel
+type NotificationSource interface {
+	NotificationChannel() <-chan *pq.Notification
+}
+
+// catchUpLimit - сколько событий догоняется за раз после переподключения к Postgres
+const catchUpLimit = 500
+
+// catchUpWindow - на сколько id назад от последнего опубликованного события перечитывается outbox после
+// переподключения. id выдаётся при вставке, а видно событие после коммита, поэтому транзакция, начатая
+// раньше, может закоммитить событие с меньшим id уже после опубликованного. Окно должно покрывать
+// столько событий, сколько успевает записаться за время самой долгой транзакции
+const catchUpWindow = 1000
+
+// Listen публикует события, о которых сообщает Postgres, пока не отменён ctx. Так каждая реплика
+// видит все события, а не только разобранные её диспетчером. pq.Listener сам переподключается
+// и после этого присылает nil - тогда пропущенное за время разрыва дочитывается из outbox
+func Listen(ctx context.Context, source NotificationSource, loader EventLoader, pub Publisher) {
+	seen := newSeenEvents(catchUpWindow)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n, ok := <-source.NotificationChannel():
+			if !ok {
+				return
+			}
+
+			if n == nil {
+				catchUp(ctx, loader, pub, seen)
+				continue
+			}
+
+			id, err := parseEventID(n.Extra)
+			if err != nil {
+				slog.Error("Invalid outbox notification", slog.String("payload", n.Extra), slog.Any("error", err))
+				continue
+			}
+			if seen.has(id) {
+				continue
+			}
+
+			event, err := loader.GetEvent(ctx, id)
+			if err != nil {
+				slog.Error("Failed to load outbox event", slog.Any("eventID", id), slog.Any("error", err))
+				continue
+			}
+
+			pub.Publish(event)
+			seen.add(id)
+		}
+	}
+}
+
+// catchUp публикует события, закоммиченные за время разрыва: всё после последнего опубликованного
+// и неопубликованные из окна перед ним. Пока ни одного события не было, догонять не от чего:
+// клиенты сами дочитают пропущенное по Last-Event-ID
+func catchUp(ctx context.Context, loader EventLoader, pub Publisher, seen *seenEvents) {
+	afterID, ok := seen.catchUpFrom()
+	if !ok {
+		return
+	}
+
+	for {
+		events, err := loader.GetEventsAfter(ctx, afterID, catchUpLimit)
+		if err != nil {
+			slog.Error("Failed to catch up outbox events", slog.Any("afterID", afterID), slog.Any("error", err))
+			return
+		}
+
+		for _, event := range events {
+			if !seen.has(event.ID) {
+				pub.Publish(event)
+				seen.add(event.ID)
+			}
+			afterID = event.ID
+		}
+
+		if len(events) < catchUpLimit {
+			return
+		}
+	}
+}
+
+// seenEvents - id опубликованных событий в пределах окна от последнего. Не даёт опубликовать событие
+// дважды, когда оно приходит и через NOTIFY, и при догонянии
+type seenEvents struct {
+	window      uint
+	first, last uint
+	ids         map[uint]struct{}
+}
+
+func newSeenEvents(window uint) *seenEvents {
+	return &seenEvents{window: window, ids: map[uint]struct{}{}}
+}
+
+func (s *seenEvents) has(id uint) bool {
+	_, ok := s.ids[id]
+	return ok
+}
+
+func (s *seenEvents) add(id uint) {
+	s.ids[id] = struct{}{}
+	if s.first == 0 || id < s.first {
+		s.first = id
+	}
+	if id <= s.last {
+		return
+	}
+
+	s.last = id
+	// Старые id чистятся пачкой, когда их набирается с окно
+	if s.last > s.window && len(s.ids) > 2*int(s.window) {
+		for seen := range s.ids {
+			if seen <= s.last-s.window {
+				delete(s.ids, seen)
+			}
+		}
+	}
+}
+
+// catchUpFrom - id, после которого перечитывать outbox: начало окна, но не раньше первого увиденного
+// события - то, что было до запуска, публиковать не нужно
+func (s *seenEvents) catchUpFrom() (uint, bool) {
+	if s.last == 0 {
+		return 0, false
+	}
+
+	from := s.first - 1
+	if s.last > s.window {
+		from = max(from, s.last-s.window)
+	}
+	return from, true
+}
+
+func parseEventID(s string) (uint, error) {
+	id, err := strconv.ParseUint(s, 10, 64)
+	return uint(id), err
+}
@@ -0,0 +1,364 @@
+package stream
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func taskEvent(id uint, eventType models.EventType, before, after *models.Task) models.DomainEvent {
+	event := models.NewTaskEvent(eventType, before, after)
+	event.ID = id
+	return event
+}
+
+func receive(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+	select {
+	case event := <-sub.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return Event{}
+	}
+}
+
+func assertNoEvent(t *testing.T, sub *Subscription) {
+	t.Helper()
+	select {
+	case event := <-sub.Events():
+		t.Fatalf("unexpected event %d", event.ID)
+	default:
+	}
+}
+
+func TestFromDomainEvent(t *testing.T) {
+	oldAssignee, newAssignee := uint(2), uint(3)
+	before := &models.Task{ID: 7, UserID: 1, AssigneeID: &oldAssignee}
+	after := &models.Task{ID: 7, UserID: 1, AssigneeID: &newAssignee}
+
+	event, recipients, ok := FromDomainEvent(taskEvent(5, models.EventTaskUpdated, before, after))
+	require.True(t, ok)
+	assert.Equal(t, uint(5), event.ID)
+	assert.Equal(t, after.AssigneeID, event.Task.AssigneeID)
+	assert.Equal(t, []uint{1, 2, 3}, recipients)
+
+	event, recipients, ok = FromDomainEvent(taskEvent(6, models.EventTaskDeleted, before, nil))
+	require.True(t, ok)
+	assert.Equal(t, uint(7), event.Task.ID)
+	assert.Equal(t, []uint{1, 2}, recipients)
+
+	_, _, ok = FromDomainEvent(taskEvent(7, models.EventTaskStatusChanged, before, after))
+	assert.False(t, ok)
+
+	_, _, ok = FromDomainEvent(models.NewUserEvent(models.EventUserRegistered, models.User{ID: 1}))
+	assert.False(t, ok)
+}
+
+func TestHub_PublishRoutesToRecipients(t *testing.T) {
+	hub := NewHub(4)
+	assignee := uint(2)
+
+	owner, err := hub.Subscribe(1)
+	require.NoError(t, err)
+	other, err := hub.Subscribe(3)
+	require.NoError(t, err)
+	assigned, err := hub.Subscribe(2)
+	require.NoError(t, err)
+
+	hub.Publish(taskEvent(1, models.EventTaskCreated, nil, &models.Task{ID: 10, UserID: 1, AssigneeID: &assignee}))
+
+	assert.Equal(t, uint(1), receive(t, owner).ID)
+	assert.Equal(t, uint(1), receive(t, assigned).ID)
+	assertNoEvent(t, other)
+}
+
+func TestHub_DropsLaggingSubscriber(t *testing.T) {
+	hub := NewHub(1)
+
+	slow, err := hub.Subscribe(1)
+	require.NoError(t, err)
+
+	hub.Publish(taskEvent(1, models.EventTaskCreated, nil, &models.Task{ID: 10, UserID: 1}))
+	hub.Publish(taskEvent(2, models.EventTaskCreated, nil, &models.Task{ID: 11, UserID: 1}))
+
+	select {
+	case <-slow.Done():
+	default:
+		t.Fatal("lagging subscriber was not disconnected")
+	}
+	assert.Equal(t, uint(1), receive(t, slow).ID)
+
+	// Повторная отписка уже отключённого подписчика безопасна
+	hub.Unsubscribe(slow)
+
+	fresh, err := hub.Subscribe(1)
+	require.NoError(t, err)
+	hub.Publish(taskEvent(3, models.EventTaskCreated, nil, &models.Task{ID: 12, UserID: 1}))
+	assert.Equal(t, uint(3), receive(t, fresh).ID)
+}
+
+func TestHub_Close(t *testing.T) {
+	hub := NewHub(1)
+
+	sub, err := hub.Subscribe(1)
+	require.NoError(t, err)
+
+	hub.Close()
+
+	select {
+	case <-sub.Done():
+	default:
+		t.Fatal("subscriber was not disconnected on close")
+	}
+
+	_, err = hub.Subscribe(1)
+	assert.ErrorIs(t, err, ErrHubClosed)
+}
+
+type fakeSource struct {
+	ch chan *pq.Notification
+}
+
+func (f *fakeSource) NotificationChannel() <-chan *pq.Notification {
+	return f.ch
+}
+
+type fakeLoader struct {
+	events []models.DomainEvent
+}
+
+func (f *fakeLoader) GetEvent(ctx context.Context, id uint) (models.DomainEvent, error) {
+	for _, e := range f.events {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return models.DomainEvent{}, assert.AnError
+}
+
+func (f *fakeLoader) GetEventsAfter(ctx context.Context, afterID uint, limit int) ([]models.DomainEvent, error) {
+	var events []models.DomainEvent
+	for _, e := range f.events {
+		if e.ID > afterID && len(events) < limit {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func TestListen_PublishesNotifiedEventsAndCatchesUpAfterReconnect(t *testing.T) {
+	hub := NewHub(4)
+	sub, err := hub.Subscribe(1)
+	require.NoError(t, err)
+
+	loader := &fakeLoader{events: []models.DomainEvent{
+		taskEvent(1, models.EventTaskCreated, nil, &models.Task{ID: 10, UserID: 1}),
+		taskEvent(2, models.EventTaskCreated, nil, &models.Task{ID: 11, UserID: 1}),
+		taskEvent(3, models.EventTaskCreated, nil, &models.Task{ID: 12, UserID: 1}),
+	}}
+	source := &fakeSource{ch: make(chan *pq.Notification)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Listen(ctx, source, loader, hub)
+		close(done)
+	}()
+
+	source.ch <- &pq.Notification{Channel: NotifyChannel, Extra: strconv.Itoa(1)}
+	assert.Equal(t, uint(1), receive(t, sub).ID)
+
+	// Переподключение: события 2 и 3 пришли, пока соединения не было
+	source.ch <- nil
+	assert.Equal(t, uint(2), receive(t, sub).ID)
+	assert.Equal(t, uint(3), receive(t, sub).ID)
+
+	cancel()
+	<-done
+}
+
+func TestListen_CatchesUpEventsCommittedOutOfOrder(t *testing.T) {
+	hub := NewHub(8)
+	sub, err := hub.Subscribe(1)
+	require.NoError(t, err)
+
+	loader := &fakeLoader{events: []models.DomainEvent{
+		taskEvent(1, models.EventTaskCreated, nil, &models.Task{ID: 10, UserID: 1}),
+		taskEvent(3, models.EventTaskCreated, nil, &models.Task{ID: 12, UserID: 1}),
+	}}
+	source := &fakeSource{ch: make(chan *pq.Notification)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Listen(ctx, source, loader, hub)
+		close(done)
+	}()
+
+	source.ch <- &pq.Notification{Channel: NotifyChannel, Extra: "1"}
+	assert.Equal(t, uint(1), receive(t, sub).ID)
+	source.ch <- &pq.Notification{Channel: NotifyChannel, Extra: "3"}
+	assert.Equal(t, uint(3), receive(t, sub).ID)
+
+	// Событие 2 получило id раньше 3, но закоммитилось уже во время разрыва, вместе с 4
+	loader.events = []models.DomainEvent{
+		loader.events[0],
+		taskEvent(2, models.EventTaskCreated, nil, &models.Task{ID: 11, UserID: 1}),
+		loader.events[1],
+		taskEvent(4, models.EventTaskCreated, nil, &models.Task{ID: 13, UserID: 1}),
+		taskEvent(5, models.EventTaskCreated, nil, &models.Task{ID: 14, UserID: 1}),
+	}
+	source.ch <- nil
+	assert.Equal(t, uint(2), receive(t, sub).ID)
+	assert.Equal(t, uint(4), receive(t, sub).ID)
+	assert.Equal(t, uint(5), receive(t, sub).ID)
+
+	// Запоздавшее уведомление об уже догнанном событии не публикует его второй раз
+	source.ch <- &pq.Notification{Channel: NotifyChannel, Extra: "4"}
+	loader.events = append(loader.events, taskEvent(6, models.EventTaskCreated, nil, &models.Task{ID: 15, UserID: 1}))
+	source.ch <- &pq.Notification{Channel: NotifyChannel, Extra: "6"}
+	assert.Equal(t, uint(6), receive(t, sub).ID)
+
+	cancel()
+	<-done
+}
+
+func TestSeenEvents_CatchUpFrom(t *testing.T) {
+	seen := newSeenEvents(10)
+	_, ok := seen.catchUpFrom()
+	assert.False(t, ok)
+
+	seen.add(5)
+	from, _ := seen.catchUpFrom()
+	assert.Equal(t, uint(4), from)
+
+	seen.add(40)
+	from, _ = seen.catchUpFrom()
+	assert.Equal(t, uint(30), from)
+}
+
+func TestParseTopic(t *testing.T) {
+	kind, id, err := ParseTopic("project:3")
+	require.NoError(t, err)
+	assert.Equal(t, "project", kind)
+	assert.Equal(t, uint(3), id)
+
+	for _, invalid := range []string{"", "task", "task:", "task:0", "task:-1", "comment:1", "task:abc"} {
+		_, _, err := ParseTopic(invalid)
+		assert.ErrorIs(t, err, ErrInvalidTopic, invalid)
+	}
+}
+
+func testRooms() *Rooms {
+	return NewRooms(RoomsConfig{MaxConnsPerUser: 2, MaxTopics: 2, Buffer: 1})
+}
+
+func receiveTopic(t *testing.T, m *Member) TopicEvent {
+	t.Helper()
+	select {
+	case event := <-m.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return TopicEvent{}
+	}
+}
+
+func TestRooms_PublishRoutesByTopic(t *testing.T) {
+	rooms := NewRooms(RoomsConfig{MaxConnsPerUser: 2, MaxTopics: 2, Buffer: 4})
+	oldProject, newProject := uint(3), uint(4)
+
+	taskWatcher, err := rooms.Connect(1)
+	require.NoError(t, err)
+	require.NoError(t, rooms.Join(taskWatcher, TaskTopic(10)))
+	require.NoError(t, rooms.Join(taskWatcher, ProjectTopic(newProject)))
+
+	projectWatcher, err := rooms.Connect(2)
+	require.NoError(t, err)
+	require.NoError(t, rooms.Join(projectWatcher, ProjectTopic(oldProject)))
+
+	other, err := rooms.Connect(3)
+	require.NoError(t, err)
+	require.NoError(t, rooms.Join(other, TaskTopic(11)))
+
+	// Задачу перенесли из проекта 3 в проект 4: увидят и следящие за старым проектом
+	moved := taskEvent(1, models.EventTaskUpdated,
+		&models.Task{ID: 10, UserID: 1, ProjectID: &oldProject},
+		&models.Task{ID: 10, UserID: 1, ProjectID: &newProject})
+	rooms.Publish(moved)
+
+	event := receiveTopic(t, taskWatcher)
+	assert.Equal(t, TaskTopic(10), event.Topic)
+	assert.Equal(t, uint(1), event.Event.ID)
+	assert.Equal(t, ProjectTopic(oldProject), receiveTopic(t, projectWatcher).Topic)
+
+	// Подписка и на задачу, и на её проект не даёт дубля
+	select {
+	case e := <-taskWatcher.Events():
+		t.Fatalf("duplicate event %v", e)
+	case e := <-other.Events():
+		t.Fatalf("unexpected event %v", e)
+	default:
+	}
+
+	rooms.Leave(taskWatcher, TaskTopic(10))
+	rooms.Leave(taskWatcher, ProjectTopic(newProject))
+	rooms.Publish(taskEvent(2, models.EventTaskDeleted, &models.Task{ID: 10, UserID: 1}, nil))
+	select {
+	case e := <-taskWatcher.Events():
+		t.Fatalf("event after unsubscribe %v", e)
+	default:
+	}
+}
+
+func TestRooms_Limits(t *testing.T) {
+	rooms := testRooms()
+
+	first, err := rooms.Connect(1)
+	require.NoError(t, err)
+	_, err = rooms.Connect(1)
+	require.NoError(t, err)
+	_, err = rooms.Connect(1)
+	assert.ErrorIs(t, err, ErrTooManyConnections)
+
+	require.NoError(t, rooms.Join(first, TaskTopic(1)))
+	require.NoError(t, rooms.Join(first, TaskTopic(2)))
+	require.NoError(t, rooms.Join(first, TaskTopic(2)))
+	assert.ErrorIs(t, rooms.Join(first, TaskTopic(3)), ErrTooManyTopics)
+
+	// Освободившееся место можно занять снова
+	rooms.Disconnect(first)
+	_, err = rooms.Connect(1)
+	assert.NoError(t, err)
+}
+
+func TestRooms_DropsLaggingMemberAndClose(t *testing.T) {
+	rooms := testRooms()
+
+	slow, err := rooms.Connect(1)
+	require.NoError(t, err)
+	require.NoError(t, rooms.Join(slow, TaskTopic(10)))
+
+	rooms.Publish(taskEvent(1, models.EventTaskUpdated, &models.Task{ID: 10, UserID: 1}, &models.Task{ID: 10, UserID: 1}))
+	rooms.Publish(taskEvent(2, models.EventTaskUpdated, &models.Task{ID: 10, UserID: 1}, &models.Task{ID: 10, UserID: 1}))
+
+	<-slow.Done()
+	assert.ErrorIs(t, slow.Err(), ErrLagging)
+	assert.ErrorIs(t, rooms.Join(slow, TaskTopic(11)), ErrHubClosed)
+
+	active, err := rooms.Connect(2)
+	require.NoError(t, err)
+	rooms.Close()
+
+	<-active.Done()
+	assert.ErrorIs(t, active.Err(), ErrHubClosed)
+	_, err = rooms.Connect(2)
+	assert.ErrorIs(t, err, ErrHubClosed)
+}
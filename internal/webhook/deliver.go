@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+	"to-do-list/internal/models"
+)
+
+type DeliveryStore interface {
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.DueWebhookDelivery, error)
+	CompleteDelivery(ctx context.Context, id uint, statusCode int) error
+	FailDelivery(ctx context.Context, id uint, statusCode *int, reason string, retryAt *time.Time) error
+}
+
+// DelivererConfig: Lease должен покрывать отправку всей пачки с таймаутами клиента, иначе доставку
+// заберёт другая реплика, пока эта ещё ждёт ответа
+type DelivererConfig struct {
+	BatchSize   int
+	Lease       time.Duration
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Deliverer разбирает очередь доставок. Доставка считается успешной при ответе 2xx,
+// иначе повторяется с экспоненциальной задержкой, пока не кончатся попытки
+type Deliverer struct {
+	store  DeliveryStore
+	client *http.Client
+	cfg    DelivererConfig
+}
+
+func NewDeliverer(store DeliveryStore, client *http.Client, cfg DelivererConfig) *Deliverer {
+	return &Deliverer{store: store, client: client, cfg: cfg}
+}
+
+// Backoff - задержка перед попыткой attempt+1: BaseBackoff, 2*BaseBackoff, 4*BaseBackoff... но не больше MaxBackoff
+func (d *Deliverer) Backoff(attempt int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return min(delay, d.cfg.MaxBackoff)
+}
+
+// DeliverDue отправляет одну пачку готовых доставок и возвращает число успешных
+func (d *Deliverer) DeliverDue(ctx context.Context) (int, error) {
+	due, err := d.store.ClaimDueDeliveries(ctx, d.cfg.BatchSize, d.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+
+		statusCode, err := d.send(ctx, delivery)
+		if err == nil {
+			err = d.store.CompleteDelivery(ctx, delivery.ID, statusCode)
+			if err != nil {
+				slog.Error("Failed to mark webhook delivery completed", slog.Any("deliveryID", delivery.ID), slog.Any("error", err))
+				continue
+			}
+			delivered++
+			continue
+		}
+
+		var code *int
+		if statusCode != 0 {
+			code = &statusCode
+		}
+		var retryAt *time.Time
+		if delivery.Attempts < d.cfg.MaxAttempts {
+			next := time.Now().Add(d.Backoff(delivery.Attempts))
+			retryAt = &next
+		}
+
+		err = d.store.FailDelivery(ctx, delivery.ID, code, err.Error(), retryAt)
+		if err != nil {
+			slog.Error("Failed to record webhook delivery failure", slog.Any("deliveryID", delivery.ID), slog.Any("error", err))
+		}
+	}
+
+	return delivered, nil
+}
+
+func (d *Deliverer) send(ctx context.Context, delivery models.DueWebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "to-do-list-webhooks")
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, delivery.Payload))
+	req.Header.Set(EventHeader, string(delivery.Event))
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	// Дочитываем тело, чтобы соединение вернулось в пул
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidURL       = errors.New("webhook: URL must be http or https")
+	ErrForbiddenAddress = errors.New("webhook: URL must point to a public address")
+	ErrUnresolvableHost = errors.New("webhook: host can't be resolved")
+)
+
+// sharedAddressSpace - 100.64.0.0/10 (RFC 6598), адреса провайдерского NAT. netip не считает их частными
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Guard не даёт вебхукам ходить во внутреннюю сеть сервера: на loopback, в частные сети, на link-local
+// (в том числе 169.254.169.254 с метаданными облака) и IPv6 ULA. Адрес проверяется дважды: при сохранении
+// вебхука по DNS и при каждом соединении по фактическому IP, поэтому подмена DNS после проверки
+// (DNS rebinding) не помогает. Allowed - сети, куда ходить всё же можно, например для тестовых получателей
+type Guard struct {
+	allowed []netip.Prefix
+	lookup  func(ctx context.Context, host string) ([]netip.Addr, error)
+}
+
+// NewGuard принимает разрешённые сети в виде CIDR или отдельных адресов
+func NewGuard(allowed []string) (*Guard, error) {
+	g := &Guard{lookup: func(ctx context.Context, host string) ([]netip.Addr, error) {
+		return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	}}
+
+	for _, s := range allowed {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(s)
+			if addrErr != nil {
+				return nil, fmt.Errorf("webhook: invalid allowed network %q: %w", s, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		g.allowed = append(g.allowed, prefix)
+	}
+
+	return g, nil
+}
+
+// CheckURL проверяет адрес вебхука при создании и изменении: схему и все адреса, в которые разрешается хост
+func (g *Guard) CheckURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidURL
+	}
+
+	addrs, err := g.lookup(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return ErrUnresolvableHost
+	}
+
+	for _, addr := range addrs {
+		if !g.Allowed(addr) {
+			return ErrForbiddenAddress
+		}
+	}
+
+	return nil
+}
+
+// Allowed - можно ли отправлять вебхук на адрес
+func (g *Guard) Allowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range g.allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// Control - для net.Dialer: проверяет адрес, к которому соединение идёт на самом деле, уже после DNS
+func (g *Guard) Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("webhook: unexpected dial address %q: %w", address, err)
+	}
+	if !g.Allowed(addrPort.Addr()) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// Client - HTTP-клиент для доставки вебхуков. Каждое соединение, в том числе после редиректа, проходит
+// через Control; прокси из окружения не используется, иначе проверялся бы адрес прокси, а не получателя
+func (g *Guard) Client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: g.Control}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuard_CheckURL(t *testing.T) {
+	guard, err := NewGuard(nil)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		url  string
+		err  error
+	}{
+		{"Public", "https://203.0.113.10/hook", nil},
+		{"Not HTTP", "ftp://203.0.113.10/hook", ErrInvalidURL},
+		{"No Host", "https:///hook", ErrInvalidURL},
+		{"Loopback", "http://127.0.0.1:8080/", ErrForbiddenAddress},
+		{"Private", "http://10.1.2.3/", ErrForbiddenAddress},
+		{"Cloud Metadata", "http://169.254.169.254/latest/meta-data", ErrForbiddenAddress},
+		{"Shared Address Space", "http://100.64.0.1/", ErrForbiddenAddress},
+		{"Unspecified", "http://0.0.0.0/", ErrForbiddenAddress},
+		{"IPv6 Loopback", "http://[::1]/", ErrForbiddenAddress},
+		{"IPv6 ULA", "http://[fd00::1]/", ErrForbiddenAddress},
+		{"IPv4-Mapped Loopback", "http://[::ffff:127.0.0.1]/", ErrForbiddenAddress},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorIs(t, guard.CheckURL(context.Background(), tc.url), tc.err)
+		})
+	}
+}
+
+func TestGuard_CheckURL_RejectsHostWithAnyPrivateAddress(t *testing.T) {
+	guard, err := NewGuard(nil)
+	require.NoError(t, err)
+	guard.lookup = func(ctx context.Context, host string) ([]netip.Addr, error) {
+		return []netip.Addr{netip.MustParseAddr("203.0.113.10"), netip.MustParseAddr("192.168.1.1")}, nil
+	}
+
+	assert.ErrorIs(t, guard.CheckURL(context.Background(), "https://hooks.example.com/"), ErrForbiddenAddress)
+}
+
+func TestGuard_AllowedNetworks(t *testing.T) {
+	guard, err := NewGuard([]string{"10.0.5.0/24", "192.168.1.7"})
+	require.NoError(t, err)
+
+	assert.True(t, guard.Allowed(netip.MustParseAddr("10.0.5.20")))
+	assert.True(t, guard.Allowed(netip.MustParseAddr("192.168.1.7")))
+	assert.False(t, guard.Allowed(netip.MustParseAddr("10.0.6.1")))
+
+	_, err = NewGuard([]string{"not-a-network"})
+	assert.Error(t, err)
+}
+
+// Клиент проверяет адрес при соединении, поэтому хост, который после проверки стал указывать
+// на внутренний адрес, всё равно недоступен
+func TestGuard_ClientBlocksPrivateAddressAtDial(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	guard, err := NewGuard(nil)
+	require.NoError(t, err)
+
+	_, err = guard.Client(time.Second).Get(receiver.URL)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+
+	guard, err = NewGuard([]string{"127.0.0.0/8"})
+	require.NoError(t, err)
+
+	resp, err := guard.Client(time.Second).Get(receiver.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
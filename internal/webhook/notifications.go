@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"to-do-list/internal/models"
+)
+
+// ReminderPayload - тело запроса с напоминанием о дедлайне
+type ReminderPayload struct {
+	Event      models.WebhookEvent `json:"event"`
+	OccurredAt time.Time           `json:"occurredAt"`
+	TaskID     uint                `json:"taskId"`
+	Title      string              `json:"title"`
+	Body       string              `json:"body"`
+}
+
+// NotificationChannel - канал уведомлений через вебхуки получателя. Вебхукам интересны только
+// напоминания: о назначении они и так узнают из task.updated
+type NotificationChannel struct {
+	repo Enqueuer
+}
+
+func NewNotificationChannel(repo Enqueuer) *NotificationChannel {
+	return &NotificationChannel{repo: repo}
+}
+
+func (c *NotificationChannel) Name() string {
+	return string(models.ChannelWebhook)
+}
+
+func (c *NotificationChannel) Send(ctx context.Context, n models.Notification) error {
+	if n.Kind != models.NotificationTaskReminder || n.TaskID == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(ReminderPayload{
+		Event:      models.WebhookTaskReminder,
+		OccurredAt: n.CreatedAt.UTC(),
+		TaskID:     *n.TaskID,
+		Title:      n.Title,
+		Body:       n.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("webhook: failed to marshal payload: %w", err)
+	}
+
+	_, err = c.repo.EnqueueWebhookDeliveries(ctx, n.UserID, models.WebhookTaskReminder, body)
+	return err
+}
@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+	"to-do-list/internal/api/types"
+	"to-do-list/internal/models"
+)
+
+// Заголовки запроса к получателю. Подпись - HMAC-SHA256 тела с секретом вебхука в hex,
+// по DeliveryHeader получатель может отбрасывать повторы
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Payload - тело запроса с событием задачи. PreviousStatus заполняется только для task.status_changed.
+// EventID одинаков у повторов одного события, по нему получатель может отбрасывать дубли.
+// Задача передаётся в том же виде, что и в ответах API
+type Payload struct {
+	EventID        uint                `json:"eventId"`
+	Event          models.WebhookEvent `json:"event"`
+	OccurredAt     time.Time           `json:"occurredAt"`
+	Task           types.TaskResponse  `json:"task"`
+	PreviousStatus models.Status       `json:"previousStatus,omitempty"`
+}
+
+// Sign возвращает значение заголовка подписи для тела body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись за постоянное время. Нужен получателям и тестам
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("webhook: failed to generate secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type Enqueuer interface {
+	EnqueueWebhookDeliveries(ctx context.Context, userID uint, event models.WebhookEvent, payload json.RawMessage) (int64, error)
+}
+
+// Subscriber превращает доменные события задач в доставки на вебхуки владельца задачи
+type Subscriber struct {
+	repo Enqueuer
+}
+
+func NewSubscriber(repo Enqueuer) *Subscriber {
+	return &Subscriber{repo: repo}
+}
+
+func (s *Subscriber) Name() string {
+	return "webhooks"
+}
+
+// Handle ставит доставки для task.created, task.updated, task.deleted и task.status_changed,
+// остальные события вебхукам не интересны
+func (s *Subscriber) Handle(ctx context.Context, event models.DomainEvent) error {
+	hookEvent := models.WebhookEvent(event.Type)
+	if event.AggregateType != "task" || !hookEvent.IsValid() {
+		return nil
+	}
+
+	taskPayload, err := event.TaskPayload()
+	if err != nil {
+		return fmt.Errorf("webhook: failed to decode event payload: %w", err)
+	}
+
+	task := taskPayload.After
+	if task == nil {
+		task = taskPayload.Before
+	}
+	if task == nil {
+		return fmt.Errorf("webhook: event %d has no task", event.ID)
+	}
+
+	payload := Payload{EventID: event.ID, Event: hookEvent, OccurredAt: event.CreatedAt.UTC(), Task: types.NewTaskResponse(*task)}
+	if hookEvent == models.WebhookTaskStatusChanged && taskPayload.Before != nil {
+		payload.PreviousStatus = taskPayload.Before.Status
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("webhook: failed to marshal payload: %w", err)
+	}
+
+	_, err = s.repo.EnqueueWebhookDeliveries(ctx, task.UserID, hookEvent, body)
+	return err
+}
@@ -0,0 +1,238 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEnqueuer struct {
+	userIDs  []uint
+	events   []models.WebhookEvent
+	payloads []Payload
+	bodies   []json.RawMessage
+}
+
+func (f *fakeEnqueuer) EnqueueWebhookDeliveries(ctx context.Context, userID uint, event models.WebhookEvent, payload json.RawMessage) (int64, error) {
+	var p Payload
+	err := json.Unmarshal(payload, &p)
+	if err != nil {
+		return 0, err
+	}
+	f.userIDs = append(f.userIDs, userID)
+	f.events = append(f.events, event)
+	f.payloads = append(f.payloads, p)
+	f.bodies = append(f.bodies, payload)
+	return 1, nil
+}
+
+type failure struct {
+	statusCode *int
+	reason     string
+	retryAt    *time.Time
+}
+
+type fakeStore struct {
+	due       []models.DueWebhookDelivery
+	completed map[uint]int
+	failed    map[uint]failure
+}
+
+func (f *fakeStore) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.DueWebhookDelivery, error) {
+	due := f.due
+	f.due = nil
+	return due, nil
+}
+
+func (f *fakeStore) CompleteDelivery(ctx context.Context, id uint, statusCode int) error {
+	f.completed[id] = statusCode
+	return nil
+}
+
+func (f *fakeStore) FailDelivery(ctx context.Context, id uint, statusCode *int, reason string, retryAt *time.Time) error {
+	f.failed[id] = failure{statusCode, reason, retryAt}
+	return nil
+}
+
+func newFakeStore(due ...models.DueWebhookDelivery) *fakeStore {
+	return &fakeStore{due: due, completed: map[uint]int{}, failed: map[uint]failure{}}
+}
+
+func testConfig() DelivererConfig {
+	return DelivererConfig{BatchSize: 10, Lease: time.Minute, MaxAttempts: 3, BaseBackoff: 30 * time.Second, MaxBackoff: time.Hour}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"event":"task.created"}`)
+	signature := Sign("secret", body)
+
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.True(t, Verify("secret", body, signature))
+	assert.False(t, Verify("other", body, signature))
+	assert.False(t, Verify("secret", []byte(`{}`), signature))
+}
+
+func TestSubscriber_Handle(t *testing.T) {
+	enqueuer := &fakeEnqueuer{}
+	subscriber := NewSubscriber(enqueuer)
+
+	before := models.Task{ID: 1, UserID: 10, Name: "Report", Status: models.StatusPending}
+	started := before
+	started.Status = models.StatusInProgress
+
+	for _, event := range models.TaskUpdateEvents(before, started) {
+		event.ID = 5
+		require.NoError(t, subscriber.Handle(context.Background(), event))
+	}
+
+	require.Equal(t, []models.WebhookEvent{models.WebhookTaskUpdated, models.WebhookTaskStatusChanged}, enqueuer.events)
+	assert.Equal(t, models.StatusPending, enqueuer.payloads[1].PreviousStatus)
+	assert.Equal(t, uint(5), enqueuer.payloads[1].EventID)
+	assert.Equal(t, uint(10), enqueuer.userIDs[1])
+
+	enqueuer.events = nil
+	deleted := models.NewTaskEvent(models.EventTaskDeleted, &before, nil)
+	require.NoError(t, subscriber.Handle(context.Background(), deleted))
+	assert.Equal(t, []models.WebhookEvent{models.WebhookTaskDeleted}, enqueuer.events)
+
+	enqueuer.events = nil
+	registered := models.NewUserEvent(models.EventUserRegistered, models.User{ID: 10})
+	require.NoError(t, subscriber.Handle(context.Background(), registered))
+	assert.Empty(t, enqueuer.events)
+}
+
+// Получатели видят задачу с теми же ключами, что и клиенты API
+func TestSubscriber_PayloadKeys(t *testing.T) {
+	enqueuer := &fakeEnqueuer{}
+	subscriber := NewSubscriber(enqueuer)
+
+	task := models.Task{ID: 1, UserID: 10, Name: "Report", Status: models.StatusPending}
+	require.NoError(t, subscriber.Handle(context.Background(), models.NewTaskEvent(models.EventTaskCreated, nil, &task)))
+	require.Len(t, enqueuer.bodies, 1)
+
+	var body map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(enqueuer.bodies[0], &body))
+	assert.Contains(t, body, "eventId")
+	assert.Contains(t, body, "occurredAt")
+
+	var keys map[string]any
+	require.NoError(t, json.Unmarshal(body["task"], &keys))
+	for _, key := range []string{"id", "userId", "name", "status", "deadline", "createdAt", "updatedAt", "tags"} {
+		assert.Contains(t, keys, key)
+	}
+	assert.NotContains(t, keys, "ID")
+	assert.NotContains(t, keys, "UserID")
+	assert.Equal(t, []any{}, keys["tags"])
+}
+
+func TestDeliverer_SignedDelivery(t *testing.T) {
+	var gotBody []byte
+	var gotHeaders http.Header
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeaders = r.Header.Clone()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+
+	payload := json.RawMessage(`{"event":"task.created","task":{"id":1}}`)
+	store := newFakeStore(models.DueWebhookDelivery{
+		WebhookDelivery: models.WebhookDelivery{ID: 7, Event: models.WebhookTaskCreated, Payload: payload, Attempts: 1},
+		URL:             receiver.URL,
+		Secret:          "s3cr3t",
+	})
+	deliverer := NewDeliverer(store, receiver.Client(), testConfig())
+
+	delivered, err := deliverer.DeliverDue(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, http.StatusAccepted, store.completed[7])
+	assert.JSONEq(t, string(payload), string(gotBody))
+	assert.True(t, Verify("s3cr3t", gotBody, gotHeaders.Get(SignatureHeader)))
+	assert.Equal(t, "task.created", gotHeaders.Get(EventHeader))
+	assert.Equal(t, "7", gotHeaders.Get(DeliveryHeader))
+}
+
+func TestDeliverer_RetriesWithBackoff(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	delivery := func(id uint, attempts int) models.DueWebhookDelivery {
+		return models.DueWebhookDelivery{
+			WebhookDelivery: models.WebhookDelivery{ID: id, Payload: json.RawMessage(`{}`), Attempts: attempts},
+			URL:             receiver.URL,
+			Secret:          "s",
+		}
+	}
+	store := newFakeStore(delivery(1, 2), delivery(2, 3))
+	deliverer := NewDeliverer(store, receiver.Client(), testConfig())
+
+	before := time.Now()
+	delivered, err := deliverer.DeliverDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+
+	retry := store.failed[1]
+	require.NotNil(t, retry.statusCode)
+	assert.Equal(t, http.StatusInternalServerError, *retry.statusCode)
+	require.NotNil(t, retry.retryAt)
+	assert.WithinDuration(t, before.Add(time.Minute), *retry.retryAt, time.Second)
+
+	last := store.failed[2]
+	assert.Nil(t, last.retryAt, "attempts are exhausted")
+}
+
+func TestDeliverer_UnreachableReceiver(t *testing.T) {
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	url := receiver.URL
+	receiver.Close()
+
+	store := newFakeStore(models.DueWebhookDelivery{
+		WebhookDelivery: models.WebhookDelivery{ID: 1, Payload: json.RawMessage(`{}`), Attempts: 1},
+		URL:             url,
+	})
+	deliverer := NewDeliverer(store, http.DefaultClient, testConfig())
+
+	_, err := deliverer.DeliverDue(context.Background())
+	require.NoError(t, err)
+
+	assert.Nil(t, store.failed[1].statusCode)
+	assert.NotNil(t, store.failed[1].retryAt)
+	assert.Contains(t, store.failed[1].reason, "request failed")
+}
+
+func TestDeliverer_Backoff(t *testing.T) {
+	deliverer := NewDeliverer(nil, nil, DelivererConfig{BaseBackoff: 30 * time.Second, MaxBackoff: 5 * time.Minute})
+
+	assert.Equal(t, 30*time.Second, deliverer.Backoff(1))
+	assert.Equal(t, time.Minute, deliverer.Backoff(2))
+	assert.Equal(t, 4*time.Minute, deliverer.Backoff(4))
+	assert.Equal(t, 5*time.Minute, deliverer.Backoff(5))
+	assert.Equal(t, 5*time.Minute, deliverer.Backoff(50))
+}
+
+func TestNotificationChannel_SendsOnlyReminders(t *testing.T) {
+	repo := &fakeEnqueuer{}
+	channel := NewNotificationChannel(repo)
+	taskID := uint(3)
+
+	err := channel.Send(context.Background(), models.Notification{UserID: 20, Kind: models.NotificationTaskAssigned, TaskID: &taskID})
+	require.NoError(t, err)
+	assert.Empty(t, repo.events)
+
+	err = channel.Send(context.Background(), models.Notification{UserID: 20, Kind: models.NotificationTaskReminder, TaskID: &taskID, CreatedAt: time.Now()})
+	require.NoError(t, err)
+	assert.Equal(t, []models.WebhookEvent{models.WebhookTaskReminder}, repo.events)
+	assert.Equal(t, []uint{20}, repo.userIDs)
+}
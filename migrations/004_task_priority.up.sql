@@ -0,0 +1,4 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS priority VARCHAR(10) NOT NULL DEFAULT 'medium'
+    CHECK (priority IN ('low', 'medium', 'high', 'urgent'));
+
+CREATE INDEX IF NOT EXISTS idx_tasks_user_priority ON tasks(user_id, priority);
@@ -0,0 +1,3 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+
+UPDATE tasks SET completed_at = updated_at WHERE status = 'completed' AND completed_at IS NULL;
@@ -0,0 +1,18 @@
+-- task_id нельзя начать, пока не завершена depends_on_id
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id INTEGER NOT NULL,
+    depends_on_id INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, depends_on_id),
+    CONSTRAINT fk_task
+        FOREIGN KEY(task_id)
+        REFERENCES tasks(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_depends_on
+        FOREIGN KEY(depends_on_id)
+        REFERENCES tasks(id)
+        ON DELETE CASCADE,
+    CONSTRAINT chk_task_dependencies_self CHECK (task_id <> depends_on_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_depends_on_id ON task_dependencies(depends_on_id);
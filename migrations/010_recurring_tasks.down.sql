@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS uq_tasks_series_occurrence;
+ALTER TABLE tasks DROP COLUMN IF EXISTS occurrence;
+ALTER TABLE tasks DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS task_series;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS task_series (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    rule VARCHAR(200) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_series_user_id ON task_series(user_id);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS series_id INTEGER
+    REFERENCES task_series(id) ON DELETE SET NULL;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS occurrence INTEGER NOT NULL DEFAULT 0;
+
+-- Один номер экземпляра на серию: повторная генерация того же экземпляра ничего не создаёт
+CREATE UNIQUE INDEX IF NOT EXISTS uq_tasks_series_occurrence ON tasks(series_id, occurrence)
+    WHERE series_id IS NOT NULL;
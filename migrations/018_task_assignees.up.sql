@@ -0,0 +1,5 @@
+-- Исполнитель задачи. При удалении пользователя задача остаётся без исполнителя
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assignee_id INTEGER
+    REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_assignee_id ON tasks(assignee_id);
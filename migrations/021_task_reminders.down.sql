@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tasks_deadline_open;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS reminder_deliveries;
+DROP TABLE IF EXISTS reminder_preferences;
+DROP TABLE IF EXISTS task_reminders;
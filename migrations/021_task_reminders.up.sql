@@ -0,0 +1,62 @@
+-- Свои напоминания задачи: за сколько секунд до дедлайна. Если их нет, действуют настройки получателя
+CREATE TABLE IF NOT EXISTS task_reminders (
+    task_id INTEGER NOT NULL,
+    offset_seconds INTEGER NOT NULL CHECK (offset_seconds > 0),
+    CONSTRAINT pk_task_reminders PRIMARY KEY (task_id, offset_seconds),
+    CONSTRAINT fk_task
+        FOREIGN KEY(task_id)
+        REFERENCES tasks(id)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS reminder_preferences (
+    user_id INTEGER PRIMARY KEY,
+    offsets INTEGER[] NOT NULL DEFAULT '{}',
+    channels VARCHAR(20)[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- Отправленные напоминания, по ним каждое срабатывает один раз. Дедлайн входит в ключ:
+-- после переноса срока напоминания сработают снова
+CREATE TABLE IF NOT EXISTS reminder_deliveries (
+    task_id INTEGER NOT NULL,
+    offset_seconds INTEGER NOT NULL,
+    deadline TIMESTAMPTZ NOT NULL,
+    user_id INTEGER NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_reminder_deliveries PRIMARY KEY (task_id, offset_seconds, deadline),
+    CONSTRAINT fk_task
+        FOREIGN KEY(task_id)
+        REFERENCES tasks(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- Уведомления внутри приложения
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    kind VARCHAR(30) NOT NULL,
+    task_id INTEGER,
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_task
+        FOREIGN KEY(task_id)
+        REFERENCES tasks(id)
+        ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_tasks_deadline_open ON tasks(deadline) WHERE status IN ('pending', 'in progress');
@@ -0,0 +1,12 @@
+-- Каждая реплика слушает outbox_events и раздаёт новые события своим SSE-клиентам.
+-- pg_notify внутри транзакции доставляется только после её коммита
+CREATE OR REPLACE FUNCTION notify_outbox_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox_events', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_outbox_events_notify ON outbox_events;
+CREATE TRIGGER trg_outbox_events_notify AFTER INSERT ON outbox_events
+    FOR EACH ROW EXECUTE FUNCTION notify_outbox_event();
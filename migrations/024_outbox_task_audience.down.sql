@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_outbox_events_task_assignees;
+DROP INDEX IF EXISTS idx_outbox_events_task_user;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS task_assignee_ids;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS task_user_id;
@@ -0,0 +1,15 @@
+-- Владелец и исполнители задачи из события хранятся отдельно от payload, чтобы повтор событий
+-- для SSE-клиента выбирался по индексу. В task_assignee_ids - исполнитель до и после изменения
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS task_user_id INTEGER;
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS task_assignee_ids INTEGER[] NOT NULL DEFAULT '{}';
+
+UPDATE outbox_events SET
+    task_user_id = (COALESCE(payload->'after', payload->'before')->>'UserID')::integer,
+    task_assignee_ids = ARRAY_REMOVE(ARRAY[
+        (payload->'after'->>'AssigneeID')::integer,
+        (payload->'before'->>'AssigneeID')::integer
+    ], NULL)
+WHERE aggregate_type = 'task' AND (payload ? 'after' OR payload ? 'before');
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_task_user ON outbox_events(task_user_id, id) WHERE task_user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_task_assignees ON outbox_events USING GIN (task_assignee_ids);